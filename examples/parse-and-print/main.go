@@ -33,14 +33,14 @@ func main() {
 	if len(scene.ExtResources) > 0 {
 		fmt.Println("## ExtResources:")
 		for _, res := range scene.ExtResources {
-			fmt.Printf("\t%s (id=%d, path='%s') [%s]\n", res.Type, res.ID, res.Path, res.LexerPosition)
+			fmt.Printf("\t%s (id=%s, path='%s') [%s]\n", res.Type, res.ID, res.Path, res.LexerPosition)
 		}
 	}
 
 	if len(scene.SubResources) > 0 {
 		fmt.Println("## SubResources:")
 		for _, res := range scene.SubResources {
-			fmt.Printf("\t%s (id=%d) [%s]\n", res.Type, res.ID, res.LexerPosition)
+			fmt.Printf("\t%s (id=%s) [%s]\n", res.Type, res.ID, res.LexerPosition)
 		}
 	}
 
@@ -92,11 +92,11 @@ func printNodesWithIndent(node *godot.Node, indent int) {
 		fmt.Printf("%s = %v\n", field, value)
 	}
 
-	if len(node.Children) > 0 {
+	if node.ChildCount() > 0 {
 		printIndent(indent + 1)
 		fmt.Println("Children:")
 
-		for _, childNode := range node.Children {
+		for _, childNode := range node.Children() {
 			printNodesWithIndent(childNode, indent+2)
 		}
 	}
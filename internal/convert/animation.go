@@ -0,0 +1,352 @@
+package convert
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// AnimationResourceType is the type of sub resources and .tres files containing an animation
+const AnimationResourceType = "Animation"
+
+// defaultAnimationLength is the length Godot uses if the file doesn't contain one
+const defaultAnimationLength = 1.0
+
+// defaultAnimationStepGodot3 and defaultAnimationStepGodot4 are the steps Godot uses if the file doesn't contain one
+const (
+	defaultAnimationStepGodot3 = 0.1
+	defaultAnimationStepGodot4 = 1.0 / 30
+)
+
+type animationFields struct {
+	ResourceName string
+	Length       *float64
+	Loop         bool
+	LoopMode     *int64
+	Step         *float64
+}
+
+// animationTrackFields uses pointers for the flags which are true if the file doesn't contain them
+type animationTrackFields struct {
+	Type     string
+	Path     interface{}
+	Interp   int64
+	LoopWrap *bool
+	Imported bool
+	Enabled  *bool
+}
+
+// animationKeyFields are the dictionaries stored in tracks/N/keys, which entries exist depends on the track type
+type animationKeyFields struct {
+	Times       []float64
+	Transitions []float64
+	Update      int64
+	Values      []interface{}
+	Points      []float64
+	HandleModes []int64
+	Clips       []interface{}
+}
+
+type animationMethodCallFields struct {
+	Method string
+	Args   []interface{}
+}
+
+type animationAudioClipFields struct {
+	Stream      *godot.Reference
+	StartOffset float64
+	EndOffset   float64
+}
+
+// ToGodotAnimation converts the fields of an Animation resource into a godot.Animation, the format of the file the
+// resource was parsed from decides the default values
+func ToGodotAnimation(fields map[string]interface{}, format int64, pos lexer.Position) (*godot.Animation, error) {
+	expanded := godot.ExpandFields(fields)
+
+	var decoded animationFields
+	if err := UnmarshalFields(expanded, &decoded); err != nil {
+		return nil, errors.Wrap(err, "could not decode animation")
+	}
+
+	animation := &godot.Animation{
+		Name:     decoded.ResourceName,
+		Length:   defaultAnimationLength,
+		Step:     defaultAnimationStepGodot4,
+		MetaData: godot.MetaData{LexerPosition: pos},
+	}
+
+	if decoded.Length != nil {
+		animation.Length = *decoded.Length
+	}
+
+	switch {
+	case decoded.Step != nil:
+		animation.Step = *decoded.Step
+	case format == godot.FormatVersionGodot3:
+		animation.Step = defaultAnimationStepGodot3
+	}
+
+	switch {
+	case decoded.LoopMode != nil:
+		animation.LoopMode = godot.AnimationLoopMode(*decoded.LoopMode)
+	case decoded.Loop:
+		animation.LoopMode = godot.AnimationLoopLinear
+	}
+
+	trackGroups, err := animationTrackGroups(expanded["tracks"])
+	if err != nil {
+		return nil, err
+	}
+
+	for index, group := range trackGroups {
+		track, err := convertAnimationTrack(group, fmt.Sprintf("tracks[%d]", index))
+		if err != nil {
+			return nil, err
+		}
+		animation.Tracks = append(animation.Tracks, track)
+	}
+
+	return animation, nil
+}
+
+// animationTrackGroups returns the fields of every track, tracks with missing indices are kept in order
+func animationTrackGroups(tracks interface{}) ([]godot.FieldGroup, error) {
+	var values []interface{}
+	switch v := tracks.(type) {
+	case nil:
+		return nil, nil
+	case godot.FieldList:
+		values = v
+	case godot.FieldGroup:
+		for _, key := range v.Keys() {
+			values = append(values, v[key])
+		}
+	default:
+		return nil, decodeError("tracks", positionOf(tracks), "tracks must be a group of fields like tracks/0/type")
+	}
+
+	groups := make([]godot.FieldGroup, len(values))
+	for index, value := range values {
+		group, ok := value.(godot.FieldGroup)
+		if !ok {
+			key := fmt.Sprintf("tracks/%d", index)
+			return nil, decodeError(key, positionOf(value), "%s must be a group of fields like %[1]s/type", key)
+		}
+		groups[index] = group
+	}
+	return groups, nil
+}
+
+func convertAnimationTrack(group godot.FieldGroup, key string) (*godot.AnimationTrack, error) {
+	var fields animationTrackFields
+	if err := UnmarshalFields(group, &fields); err != nil {
+		return nil, errors.Wrap(err, "could not decode animation track")
+	}
+	keys := group["keys"]
+
+	track := &godot.AnimationTrack{
+		Type:          godot.AnimationTrackType(fields.Type),
+		Interpolation: fields.Interp,
+		LoopWrap:      true,
+		Imported:      fields.Imported,
+		Enabled:       true,
+		MetaData:      godot.MetaData{LexerPosition: positionOf(group["type"])},
+	}
+	if fields.LoopWrap != nil {
+		track.LoopWrap = *fields.LoopWrap
+	}
+	if fields.Enabled != nil {
+		track.Enabled = *fields.Enabled
+	}
+
+	if path, ok := nodePathOf(fields.Path); ok {
+		track.Path = path
+	}
+
+	if keys == nil {
+		return track, nil
+	}
+
+	var err error
+	switch track.Type {
+	case godot.AnimationTrackTransform:
+		track.Keys, err = convertPackedAnimationKeys(keys, key, 12, func(f []float64) interface{} {
+			return godot.AnimationTransformKey{
+				Position: godot.Vector3{X: f[0], Y: f[1], Z: f[2]},
+				Rotation: godot.Quaternion{X: f[3], Y: f[4], Z: f[5], W: f[6]},
+				Scale:    godot.Vector3{X: f[7], Y: f[8], Z: f[9]},
+			}
+		})
+	case godot.AnimationTrackPosition3D, godot.AnimationTrackScale3D:
+		track.Keys, err = convertPackedAnimationKeys(keys, key, 5, func(f []float64) interface{} {
+			return godot.Vector3{X: f[0], Y: f[1], Z: f[2]}
+		})
+	case godot.AnimationTrackRotation3D:
+		track.Keys, err = convertPackedAnimationKeys(keys, key, 6, func(f []float64) interface{} {
+			return godot.Quaternion{X: f[0], Y: f[1], Z: f[2], W: f[3]}
+		})
+	case godot.AnimationTrackBlendShape:
+		track.Keys, err = convertPackedAnimationKeys(keys, key, 3, func(f []float64) interface{} {
+			return f[0]
+		})
+	default:
+		track.Keys, track.UpdateMode, err = convertAnimationKeyDictionary(track.Type, keys, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(track.Keys, func(i, j int) bool {
+		return track.Keys[i].Time < track.Keys[j].Time
+	})
+
+	return track, nil
+}
+
+// convertPackedAnimationKeys converts keys stored in a single packed array like
+// PackedFloat32Array(time, transition, x, y, z, ...), size is the number of components per key
+func convertPackedAnimationKeys(
+	raw interface{},
+	key string,
+	size int,
+	create func(f []float64) interface{},
+) ([]godot.AnimationKey, error) {
+	var components []float64
+	if err := unmarshalValue(raw, reflect.ValueOf(&components).Elem(), key+".keys"); err != nil {
+		return nil, err
+	}
+
+	if len(components)%size != 0 {
+		return nil, decodeError(
+			key+".keys",
+			positionOf(raw),
+			"%s.keys contains %d components, expected a multiple of %d",
+			key,
+			len(components),
+			size,
+		)
+	}
+
+	var keys []godot.AnimationKey
+	for start := 0; start < len(components); start += size {
+		f := components[start : start+size]
+		keys = append(keys, godot.AnimationKey{Time: f[0], Transition: f[1], Value: create(f[2:])})
+	}
+	return keys, nil
+}
+
+// convertAnimationKeyDictionary converts keys stored in a dictionary of arrays like
+// { "times": [...], "transitions": [...], "values": [...] } and returns the update mode of value tracks
+func convertAnimationKeyDictionary(
+	trackType godot.AnimationTrackType,
+	raw interface{},
+	key string,
+) ([]godot.AnimationKey, int64, error) {
+	var fields animationKeyFields
+	if err := unmarshalValue(raw, reflect.ValueOf(&fields).Elem(), key+".keys"); err != nil {
+		return nil, 0, err
+	}
+
+	var values []interface{}
+	valuesKey := key + ".keys.values"
+
+	switch trackType {
+	case godot.AnimationTrackValue:
+		values = fields.Values
+	case godot.AnimationTrackMethod:
+		for index, value := range fields.Values {
+			var call animationMethodCallFields
+			elementKey := fmt.Sprintf("%s[%d]", valuesKey, index)
+			if err := unmarshalValue(value, reflect.ValueOf(&call).Elem(), elementKey); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, godot.AnimationMethodCall{Method: call.Method, Args: call.Args})
+		}
+	case godot.AnimationTrackBezier:
+		valuesKey = key + ".keys.points"
+		if len(fields.Points) != 5*len(fields.Times) {
+			return nil, 0, decodeError(valuesKey, positionOf(raw), "%s must contain 5 values per key", valuesKey)
+		}
+		for index := range fields.Times {
+			f := fields.Points[index*5 : index*5+5]
+			bezierKey := godot.AnimationBezierKey{
+				Value:     f[0],
+				InHandle:  godot.Vector2{X: f[1], Y: f[2]},
+				OutHandle: godot.Vector2{X: f[3], Y: f[4]},
+			}
+			if index < len(fields.HandleModes) {
+				bezierKey.HandleMode = fields.HandleModes[index]
+			}
+			values = append(values, bezierKey)
+		}
+	case godot.AnimationTrackAudio:
+		valuesKey = key + ".keys.clips"
+		for index, value := range fields.Clips {
+			var clip animationAudioClipFields
+			elementKey := fmt.Sprintf("%s[%d]", valuesKey, index)
+			if err := unmarshalValue(value, reflect.ValueOf(&clip).Elem(), elementKey); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, godot.AnimationAudioClip{
+				Stream:      clip.Stream,
+				StartOffset: clip.StartOffset,
+				EndOffset:   clip.EndOffset,
+			})
+		}
+	case godot.AnimationTrackAnimation:
+		valuesKey = key + ".keys.clips"
+		for index, value := range fields.Clips {
+			var name string
+			elementKey := fmt.Sprintf("%s[%d]", valuesKey, index)
+			if err := unmarshalValue(value, reflect.ValueOf(&name).Elem(), elementKey); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, name)
+		}
+	default:
+		// unknown track types only provide the times of their keys
+		values = make([]interface{}, len(fields.Times))
+	}
+
+	if len(values) != len(fields.Times) {
+		return nil, 0, decodeError(
+			valuesKey,
+			positionOf(raw),
+			"%s contains %d entries but there are %d keys",
+			valuesKey,
+			len(values),
+			len(fields.Times),
+		)
+	}
+
+	keys := make([]godot.AnimationKey, len(fields.Times))
+	for index, time := range fields.Times {
+		keys[index] = godot.AnimationKey{Time: time, Transition: 1, Value: values[index]}
+		if index < len(fields.Transitions) {
+			keys[index].Transition = fields.Transitions[index]
+		}
+	}
+	return keys, fields.Update, nil
+}
+
+// nodePathOf returns the path of NodePath("Sprite:frame")
+func nodePathOf(value interface{}) (string, bool) {
+	raw, _ := unwrapValue(value)
+	switch v := raw.(type) {
+	case string:
+		return v, true
+	case godot.Type:
+		if v.Identifier != "NodePath" || len(v.Parameters) != 1 {
+			return "", false
+		}
+		path, ok := unwrapComponent(v.Parameters[0]).(string)
+		return path, ok
+	default:
+		return "", false
+	}
+}
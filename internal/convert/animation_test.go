@@ -0,0 +1,207 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestToGodotAnimation(t *testing.T) {
+	fields := parseFieldsForTest(t, `resource_name = "Attack"
+length = 0.4
+loop = true
+step = 0.05
+tracks/0/type = "value"
+tracks/0/path = NodePath("Sprite:frame")
+tracks/0/interp = 1
+tracks/0/loop_wrap = true
+tracks/0/imported = false
+tracks/0/enabled = true
+tracks/0/keys = {
+"times": PoolRealArray( 0.2, 0, 0.1 ),
+"transitions": PoolRealArray( 1, 1, 0.5 ),
+"update": 1,
+"values": [ 38, 36, 37 ]
+}
+tracks/1/type = "method"
+tracks/1/path = NodePath(".")
+tracks/1/loop_wrap = false
+tracks/1/enabled = false
+tracks/1/keys = {
+"times": PoolRealArray( 0.4 ),
+"transitions": PoolRealArray( 1 ),
+"values": [ {
+"args": [ 1, "two" ],
+"method": "attack_finished"
+} ]
+}
+tracks/2/type = "audio"
+tracks/2/path = NodePath("AudioStreamPlayer")
+tracks/2/keys = {
+"clips": [ {
+"end_offset": 0.5,
+"start_offset": 0.0,
+"stream": ExtResource( 8 )
+} ],
+"times": PoolRealArray( 0.1 )
+}
+tracks/3/type = "animation"
+tracks/3/path = NodePath("AnimationPlayer")
+tracks/3/keys = {
+"clips": PoolStringArray( "Idle" ),
+"times": PoolRealArray( 0 )
+}`)
+
+	animation, err := ToGodotAnimation(fields, godot.FormatVersionGodot3, lexer.Position{Line: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, "Attack", animation.Name)
+	assert.Equal(t, 0.4, animation.Length)
+	assert.Equal(t, godot.AnimationLoopLinear, animation.LoopMode)
+	assert.Equal(t, 0.05, animation.Step)
+	assert.Equal(t, 3, animation.LexerPosition.Line)
+	assert.Len(t, animation.Tracks, 4)
+
+	value := animation.Tracks[0]
+	assert.Equal(t, godot.AnimationTrackValue, value.Type)
+	assert.Equal(t, "Sprite:frame", value.Path)
+	assert.Equal(t, int64(1), value.Interpolation)
+	assert.Equal(t, int64(1), value.UpdateMode)
+	assert.True(t, value.LoopWrap)
+	assert.True(t, value.Enabled)
+	assert.False(t, value.Imported)
+	assert.Equal(t, 5, value.LexerPosition.Line)
+	assert.Len(t, value.Keys, 3)
+	for index, key := range value.Keys {
+		assert.Equal(t, float64(index)/10, key.Time)
+		assert.Equal(t, int64(36+index), key.Value.(godot.Value).Value)
+	}
+	assert.Equal(t, 0.5, value.Keys[1].Transition)
+
+	method := animation.Tracks[1]
+	assert.Equal(t, godot.AnimationTrackMethod, method.Type)
+	assert.False(t, method.LoopWrap)
+	assert.False(t, method.Enabled)
+	assert.Len(t, method.Keys, 1)
+	call := method.Keys[0].Value.(godot.AnimationMethodCall)
+	assert.Equal(t, "attack_finished", call.Method)
+	assert.Len(t, call.Args, 2)
+
+	audio := animation.Tracks[2]
+	clip := audio.Keys[0].Value.(godot.AnimationAudioClip)
+	assert.Equal(t, 0.1, audio.Keys[0].Time)
+	assert.Equal(t, 0.5, clip.EndOffset)
+	assert.Equal(t, "8", clip.Stream.ID)
+	assert.True(t, clip.Stream.External)
+
+	assert.Equal(t, "Idle", animation.Tracks[3].Keys[0].Value)
+}
+
+func TestToGodotAnimationWithGodot4Tracks(t *testing.T) {
+	fields := parseFieldsForTest(t, `loop_mode = 2
+tracks/0/type = "position_3d"
+tracks/0/path = NodePath("Skeleton3D:Hip")
+tracks/0/keys = PackedFloat32Array(0, 1, 0, 1, 0, 0.5, 1, 0, 2, 0)
+tracks/1/type = "rotation_3d"
+tracks/1/path = NodePath("Skeleton3D:Hip")
+tracks/1/keys = PackedFloat32Array(0, 1, 0, 0, 0, 1)
+tracks/2/type = "scale_3d"
+tracks/2/keys = PackedFloat32Array(0, 1, 2, 2, 2)
+tracks/3/type = "blend_shape"
+tracks/3/keys = PackedFloat32Array(0, 1, 0.25)
+tracks/4/type = "bezier"
+tracks/4/path = NodePath("Sprite2D:modulate:a")
+tracks/4/keys = {
+"handle_modes": PackedInt32Array(0, 1),
+"points": PackedFloat32Array(1, -0.25, 0, 0.25, 0, 0, -0.25, 0, 0.25, 0),
+"times": PackedFloat32Array(0, 1)
+}
+tracks/5/type = "method"
+tracks/5/keys = {
+"times": PackedFloat32Array(0.4),
+"transitions": PackedFloat32Array(1),
+"values": [{
+"args": [],
+"method": &"attack_finished"
+}]
+}`)
+
+	animation, err := ToGodotAnimation(fields, godot.FormatVersionGodot4, lexer.Position{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, animation.Length)
+	assert.Equal(t, godot.AnimationLoopPingPong, animation.LoopMode)
+	assert.Len(t, animation.Tracks, 6)
+
+	// tracks without loop_wrap and enabled keys are enabled and wrap like in Godot
+	assert.True(t, animation.Tracks[0].LoopWrap)
+	assert.True(t, animation.Tracks[0].Enabled)
+
+	assert.Equal(t, []godot.AnimationKey{
+		{Time: 0, Transition: 1, Value: godot.Vector3{X: 0, Y: 1, Z: 0}},
+		{Time: 0.5, Transition: 1, Value: godot.Vector3{X: 0, Y: 2, Z: 0}},
+	}, animation.Tracks[0].Keys)
+	assert.Equal(t, godot.QuaternionIdentity, animation.Tracks[1].Keys[0].Value)
+	assert.Equal(t, godot.Vector3{X: 2, Y: 2, Z: 2}, animation.Tracks[2].Keys[0].Value)
+	assert.Equal(t, 0.25, animation.Tracks[3].Keys[0].Value)
+
+	bezier := animation.Tracks[4]
+	assert.Equal(t, "Sprite2D:modulate:a", bezier.Path)
+	assert.Equal(t, godot.AnimationBezierKey{
+		Value:      0,
+		InHandle:   godot.Vector2{X: -0.25},
+		OutHandle:  godot.Vector2{X: 0.25},
+		HandleMode: 1,
+	}, bezier.Keys[1].Value)
+	assert.Equal(t, 1.0, bezier.Keys[1].Transition)
+
+	assert.Equal(t, "attack_finished", animation.Tracks[5].Keys[0].Value.(godot.AnimationMethodCall).Method)
+}
+
+func TestToGodotAnimationWithGodot3TransformTrack(t *testing.T) {
+	fields := parseFieldsForTest(t, `tracks/0/type = "transform"
+tracks/0/path = NodePath("Armature/Skeleton:Hip")
+tracks/0/keys = PoolRealArray( 0, 1, 1, 2, 3, 0, 0, 0, 1, 1, 1, 1 )`)
+
+	animation, err := ToGodotAnimation(fields, godot.FormatVersionGodot3, lexer.Position{})
+	assert.NoError(t, err)
+	assert.Equal(t, godot.AnimationTransformKey{
+		Position: godot.Vector3{X: 1, Y: 2, Z: 3},
+		Rotation: godot.QuaternionIdentity,
+		Scale:    godot.Vector3{X: 1, Y: 1, Z: 1},
+	}, animation.Tracks[0].Keys[0].Value)
+}
+
+func TestToGodotAnimationWithDefaultStep(t *testing.T) {
+	fields := parseFieldsForTest(t, `length = 0.4`)
+
+	animation, err := ToGodotAnimation(fields, godot.FormatVersionGodot3, lexer.Position{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, animation.Step)
+
+	animation, err = ToGodotAnimation(fields, godot.FormatVersionGodot4, lexer.Position{})
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.0/30, animation.Step, 1e-9)
+}
+
+func TestToGodotAnimationWithInvalidKeys(t *testing.T) {
+	fields := parseFieldsForTest(t, `tracks/0/type = "position_3d"
+tracks/0/keys = PackedFloat32Array(0, 1, 0, 1)`)
+	_, err := ToGodotAnimation(fields, godot.FormatVersionGodot4, lexer.Position{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "tracks[0].keys")
+
+	fields = parseFieldsForTest(t, `tracks/0/type = "value"
+tracks/0/keys = {
+"times": PoolRealArray( 0, 1 ),
+"values": [ 1 ]
+}`)
+	_, err = ToGodotAnimation(fields, godot.FormatVersionGodot3, lexer.Position{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "2:17")
+
+	fields = parseFieldsForTest(t, `length = "long"`)
+	_, err = ToGodotAnimation(fields, godot.FormatVersionGodot3, lexer.Position{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package convert
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// ToGodotConfig converts a TscnFile structure into a godot.ConfigFile, the format is derived from the types used in
+// the file and defaults to the Godot 4 format
+func ToGodotConfig(tscn *parser.TscnFile) (*godot.ConfigFile, error) {
+	config := godot.NewConfigFile()
+	config.Format = configFormatOf(tscn)
+	config.LexerPosition = tscn.Pos
+
+	sections, err := configSections(tscn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, section := range sections {
+		for _, field := range section.Fields {
+			config.SetValue(section.ResourceType, field.Key, convertGdValue(field.Value))
+		}
+	}
+
+	return config, nil
+}
+
+// configSections returns all sections of a config file, the fields following the first section header are part of the
+// TscnFile itself and are returned as first section
+func configSections(tscn *parser.TscnFile) ([]*parser.GdResource, error) {
+	first := &parser.GdResource{ResourceType: tscn.Key, Attributes: tscn.Attributes, Fields: tscn.Fields, Pos: tscn.Pos}
+	sections := append([]*parser.GdResource{first}, tscn.Sections...)
+
+	for _, section := range sections {
+		if len(section.Attributes) > 0 {
+			attr := section.Attributes[0]
+			return nil, invalidValue(
+				section.Location(attr.Pos),
+				attr.Key,
+				attr.Value,
+				"config file sections can't have attributes like %s",
+				attr.Key,
+			)
+		}
+	}
+
+	return sections, nil
+}
+
+// FromGodotConfig converts a godot.ConfigFile back into a TscnFile structure and returns the formatting of its format
+func FromGodotConfig(config *godot.ConfigFile) (*parser.TscnFile, parser.PrintOptions, error) {
+	tscn := &parser.TscnFile{}
+
+	for _, name := range config.Sections() {
+		var fields []*parser.GdField
+		for _, key := range config.SectionKeys(name) {
+			value, _ := config.Field(name, key)
+			field, err := newGdField(key, value)
+			if err != nil {
+				return nil, parser.PrintOptions{}, errors.Wrapf(err, "could not convert section %s", name)
+			}
+			fields = append(fields, field)
+		}
+
+		switch {
+		// fields without a section are written in front of the first section header
+		case name == "":
+			tscn.Fields = fields
+		case tscn.Key == "" && !config.HasSection(""):
+			tscn.Key = name
+			tscn.Fields = fields
+		default:
+			tscn.Sections = append(tscn.Sections, &parser.GdResource{ResourceType: name, Fields: fields})
+		}
+	}
+
+	format := config.Format
+	if format == 0 {
+		format = godot.FormatVersionGodot4
+	}
+
+	options := printOptionsForFormat(format)
+	options.ConfigFile = true
+	return tscn, options, nil
+}
+
+// configFormatOf guesses the format of a config file by the types it uses, e.g. PoolStringArray was renamed to
+// PackedStringArray in Godot 4
+func configFormatOf(tscn *parser.TscnFile) int64 {
+	format := int64(godot.FormatVersionGodot4)
+
+	var visit func(value *parser.GdValue) bool
+	visit = func(value *parser.GdValue) bool {
+		if value.Type != nil {
+			switch key := value.Type.Key; {
+			case strings.HasPrefix(key, "Pool"), key == "Quat", key == "Transform":
+				format = godot.FormatVersionGodot3
+				return true
+			case strings.HasPrefix(key, "Packed"), key == "Transform3D", key == "Quaternion":
+				format = godot.FormatVersionGodot4
+				return true
+			}
+			for _, param := range value.Type.Parameters {
+				if visit(param) {
+					return true
+				}
+			}
+		}
+		for _, elem := range value.Array {
+			if visit(elem) {
+				return true
+			}
+		}
+		for _, field := range value.Map {
+			if visit(field.Value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	sections := append([]*parser.GdResource{{Fields: tscn.Fields}}, tscn.Sections...)
+	for _, section := range sections {
+		for _, field := range section.Fields {
+			if visit(field.Value) {
+				return format
+			}
+		}
+	}
+	return format
+}
@@ -0,0 +1,64 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestToGodotConfig(t *testing.T) {
+	content := `[preset.0]
+name="Windows Desktop"
+[preset.0.options]
+binary_format/64_bits=true
+application/icon=""`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	config, err := ToGodotConfig(tscnFile)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"preset.0", "preset.0.options"}, config.Sections())
+	assert.Equal(t, []string{"binary_format/64_bits", "application/icon"}, config.SectionKeys("preset.0.options"))
+
+	name, ok := config.Field("preset.0", "name")
+	assert.True(t, ok)
+	assert.Equal(t, "Windows Desktop", name.(godot.Value).Value)
+	assert.Equal(t, 2, name.(godot.Value).LexerPosition.Line)
+}
+
+func TestFromGodotConfig(t *testing.T) {
+	config := godot.NewConfigFile()
+	config.Format = godot.FormatVersionGodot3
+	config.SetValue("plugin", "name", "Test")
+	config.SetValue("plugin", "files", godot.Value{Value: []interface{}{"a.gd"}})
+	config.SetValue("deps", "size", godot.Vector2{X: 1, Y: 2})
+
+	tscnFile, options, err := FromGodotConfig(config)
+	assert.NoError(t, err)
+	assert.True(t, options.ConfigFile)
+	assert.True(t, options.Godot3)
+
+	assert.Equal(t, "plugin", tscnFile.Key)
+	assert.Len(t, tscnFile.Fields, 2)
+	assert.Len(t, tscnFile.Sections, 1)
+	assert.Equal(t, "deps", tscnFile.Sections[0].ResourceType)
+	assert.Equal(t, "size", tscnFile.Sections[0].Fields[0].Key)
+}
+
+func TestFromGodotConfigWithFieldsWithoutSection(t *testing.T) {
+	config := godot.NewConfigFile()
+	config.SetValue("application", "config/name", "Test")
+	config.SetValue("", "config_version", 5)
+
+	tscnFile, _, err := FromGodotConfig(config)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", tscnFile.Key)
+	assert.Equal(t, "config_version", tscnFile.Fields[0].Key)
+	assert.Equal(t, "application", tscnFile.Sections[0].ResourceType)
+}
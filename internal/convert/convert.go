@@ -2,6 +2,13 @@
 package convert
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/pkg/errors"
+
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
 	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
 )
@@ -13,9 +20,27 @@ const (
 	TscnTypeGodotResource = "gd_resource"
 )
 
+// formatVersionOf returns the format version of the file, files without a format attribute are treated as Godot 3
+func formatVersionOf(tscn *parser.TscnFile) int64 {
+	format, err := tscn.GetAttribute("format")
+	if err != nil || format.Integer == nil {
+		return godot.FormatVersionGodot3
+	}
+	return *format.Integer
+}
+
+// uidOf returns the uid attribute Godot 4 adds to scenes and resources, or an empty string
+func uidOf(tscn *parser.TscnFile) string {
+	uid, err := tscn.GetAttribute("uid")
+	if err != nil || uid.String == nil {
+		return ""
+	}
+	return *uid.String
+}
+
 func insertFieldEntriesFromSection(section *parser.GdResource, fieldMap map[string]interface{}) {
 	for _, field := range section.Fields {
-		// TODO: properly parse structures like bones/0/name = "Bone", bones/0/parent = -1, etc.
+		// structures like bones/0/name = "Bone", bones/0/parent = -1 stay flat, see godot.ExpandFields
 		fieldMap[field.Key] = convertGdValue(field.Value)
 	}
 }
@@ -35,25 +60,41 @@ func convertGdValue(val *parser.GdValue) interface{} {
 		}
 	case []*parser.GdMapField:
 		m := make(map[string]interface{})
+		var stringNameKeys map[string]bool
 		for _, kv := range value {
 			m[kv.Key] = convertGdValue(kv.Value)
+			if kv.IsStringNameKey {
+				if stringNameKeys == nil {
+					stringNameKeys = make(map[string]bool)
+				}
+				stringNameKeys[kv.Key] = true
+			}
 		}
 		return godot.Value{
-			Value: m,
+			Value:          m,
+			StringNameKeys: stringNameKeys,
 			MetaData: godot.MetaData{
 				LexerPosition: val.Pos,
 			},
 		}
 	case parser.GdType:
-		params := make([]interface{}, len(value.Parameters))
-		for index, p := range value.Parameters {
-			params[index] = convertGdValue(p)
+		if object, ok := convertGdTypeToObject(&value); ok {
+			return object
 		}
-		return godot.Type{
-			Identifier: value.Key,
-			Parameters: params,
+		if mathType, ok := convertGdTypeToMathType(&value); ok {
+			return godot.Value{
+				Value: mathType,
+				MetaData: godot.MetaData{
+					LexerPosition: val.Pos,
+				},
+			}
+		}
+		return convertGdType(&value)
+	case parser.StringName:
+		return godot.Value{
+			Value: godot.StringName(value),
 			MetaData: godot.MetaData{
-				LexerPosition: value.Pos,
+				LexerPosition: val.Pos,
 			},
 		}
 	case parser.GdMapField:
@@ -73,3 +114,336 @@ func convertGdValue(val *parser.GdValue) interface{} {
 		}
 	}
 }
+
+func convertGdType(value *parser.GdType) godot.Type {
+	var typeParams []godot.Type
+	for _, t := range value.TypeParameters {
+		typeParams = append(typeParams, convertGdType(t))
+	}
+
+	var params []interface{}
+	if value.HasParameterList || len(value.Parameters) > 0 {
+		params = make([]interface{}, len(value.Parameters))
+		for index, p := range value.Parameters {
+			params[index] = convertGdValue(p)
+		}
+	}
+
+	return godot.Type{
+		Identifier:     value.Key,
+		TypeParameters: typeParams,
+		Parameters:     params,
+		MetaData: godot.MetaData{
+			LexerPosition: value.Pos,
+		},
+	}
+}
+
+// objectTypeKey is the identifier of inline objects like Object(InputEventKey,"device":0)
+const objectTypeKey = "Object"
+
+// convertGdTypeToObject converts an inline object into a godot.Object, objects without class like Object(1, 2) stay a
+// godot.Type
+func convertGdTypeToObject(value *parser.GdType) (godot.Object, bool) {
+	if value.Key != objectTypeKey || len(value.Parameters) == 0 {
+		return godot.Object{}, false
+	}
+
+	class := value.Parameters[0].Type
+	if class == nil || class.HasParameterList || len(class.TypeParameters) > 0 {
+		return godot.Object{}, false
+	}
+
+	properties := make(map[string]interface{}, len(value.Parameters)-1)
+	for _, param := range value.Parameters[1:] {
+		if param.KeyValuePair == nil {
+			return godot.Object{}, false
+		}
+		properties[param.KeyValuePair.Key] = convertGdValue(param.KeyValuePair.Value)
+	}
+
+	return godot.Object{
+		Class:      class.Key,
+		Properties: properties,
+		MetaData: godot.MetaData{
+			LexerPosition: value.Pos,
+		},
+	}, true
+}
+
+// convertToGdValue converts a value from the godot package back into its parser representation
+func convertToGdValue(value interface{}) (*parser.GdValue, error) {
+	switch v := value.(type) {
+	case godot.Value:
+		if m, ok := v.Value.(map[string]interface{}); ok {
+			return convertMapToGdValue(m, v.StringNameKeys)
+		}
+		return convertToGdValue(v.Value)
+	case godot.Type:
+		t, err := convertToGdType(&v)
+		if err != nil {
+			return nil, err
+		}
+		return &parser.GdValue{Type: t}, nil
+	case godot.Object:
+		t, err := convertObjectToGdType(&v)
+		if err != nil {
+			return nil, err
+		}
+		return &parser.GdValue{Type: t}, nil
+	case godot.KeyValuePair:
+		kv, err := convertToGdMapField(v.Key, v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &parser.GdValue{KeyValuePair: kv}, nil
+	case []interface{}:
+		return convertSliceToGdValue(v)
+	case []string:
+		values := make([]interface{}, len(v))
+		for index, s := range v {
+			values[index] = s
+		}
+		return convertSliceToGdValue(values)
+	case map[string]interface{}:
+		return convertMapToGdValue(v, nil)
+	default:
+		if t, ok := convertMathTypeToGdType(value); ok {
+			return &parser.GdValue{Type: t}, nil
+		}
+		return convertScalarToGdValue(value)
+	}
+}
+
+func convertScalarToGdValue(value interface{}) (*parser.GdValue, error) {
+	switch v := value.(type) {
+	case nil:
+		isNull := true
+		return &parser.GdValue{Null: &isNull}, nil
+	case godot.StringName:
+		s := string(v)
+		return &parser.GdValue{StringName: &s}, nil
+	case string:
+		return &parser.GdValue{String: &v}, nil
+	case bool:
+		b := parser.Boolean(v)
+		return &parser.GdValue{Bool: &b}, nil
+	case int:
+		i := int64(v)
+		return &parser.GdValue{Integer: &i}, nil
+	case int32:
+		i := int64(v)
+		return &parser.GdValue{Integer: &i}, nil
+	case int64:
+		return &parser.GdValue{Integer: &v}, nil
+	case float32:
+		f := float64(v)
+		return &parser.GdValue{Float: &f}, nil
+	case float64:
+		return &parser.GdValue{Float: &v}, nil
+	default:
+		return nil, fmt.Errorf("can't convert value of type %T", value)
+	}
+}
+
+func convertSliceToGdValue(values []interface{}) (*parser.GdValue, error) {
+	if len(values) == 0 {
+		isEmpty := true
+		return &parser.GdValue{IsEmptyArray: &isEmpty}, nil
+	}
+
+	array := make([]*parser.GdValue, len(values))
+	for index, v := range values {
+		gdValue, err := convertToGdValue(v)
+		if err != nil {
+			return nil, err
+		}
+		array[index] = gdValue
+	}
+	return &parser.GdValue{Array: array}, nil
+}
+
+// convertMapToGdValue converts a dictionary, the keys contained in stringNameKeys are written as StringNames
+func convertMapToGdValue(m map[string]interface{}, stringNameKeys map[string]bool) (*parser.GdValue, error) {
+	if len(m) == 0 {
+		isEmpty := true
+		return &parser.GdValue{IsEmptyMap: &isEmpty}, nil
+	}
+
+	var fields []*parser.GdMapField
+	for _, key := range sortedFieldKeys(m) {
+		field, err := convertToGdMapField(key, m[key])
+		if err != nil {
+			return nil, err
+		}
+		field.IsStringNameKey = stringNameKeys[key]
+		fields = append(fields, field)
+	}
+	return &parser.GdValue{Map: fields}, nil
+}
+
+func convertToGdMapField(key string, value interface{}) (*parser.GdMapField, error) {
+	gdValue, err := convertToGdValue(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not convert value of key %s", key)
+	}
+	return &parser.GdMapField{Key: key, Value: gdValue}, nil
+}
+
+func convertToGdType(t *godot.Type) (*parser.GdType, error) {
+	gdType := &parser.GdType{
+		Key:              t.Identifier,
+		HasParameterList: t.Parameters != nil,
+	}
+
+	for index := range t.TypeParameters {
+		typeParam, err := convertToGdType(&t.TypeParameters[index])
+		if err != nil {
+			return nil, err
+		}
+		gdType.TypeParameters = append(gdType.TypeParameters, typeParam)
+	}
+
+	for _, param := range t.Parameters {
+		gdValue, err := convertToGdValue(param)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not convert parameter of %s", t.Identifier)
+		}
+		gdType.Parameters = append(gdType.Parameters, gdValue)
+	}
+
+	return gdType, nil
+}
+
+func convertObjectToGdType(object *godot.Object) (*parser.GdType, error) {
+	gdType := &parser.GdType{
+		Key:              objectTypeKey,
+		HasParameterList: true,
+		Parameters:       []*parser.GdValue{{Type: &parser.GdType{Key: object.Class}}},
+	}
+
+	for _, key := range sortedFieldKeys(object.Properties) {
+		field, err := convertToGdMapField(key, object.Properties[key])
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not convert property of %s", object.Class)
+		}
+		gdType.Parameters = append(gdType.Parameters, &parser.GdValue{KeyValuePair: field})
+	}
+
+	return gdType, nil
+}
+
+// convertFieldsToGdFields converts a field map into a list of fields, ordered like they were in the source file.
+// Fields expanded by godot.ExpandFields are flattened again.
+func convertFieldsToGdFields(fields map[string]interface{}) ([]*parser.GdField, error) {
+	fields = godot.FlattenFields(fields)
+
+	var gdFields []*parser.GdField
+	for _, key := range sortedFieldKeys(fields) {
+		gdField, err := newGdField(key, fields[key])
+		if err != nil {
+			return nil, err
+		}
+		gdFields = append(gdFields, gdField)
+	}
+	return gdFields, nil
+}
+
+func newGdField(key string, value interface{}) (*parser.GdField, error) {
+	gdValue, err := convertToGdValue(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not convert field %s", key)
+	}
+	return &parser.GdField{Key: key, Value: gdValue}, nil
+}
+
+// sortedFieldKeys returns the keys of a field map in the order they appeared in the source file, fields which were
+// added afterwards are sorted by name and placed at the end
+func sortedFieldKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return lessByPosition(positionOf(fields[keys[i]]), positionOf(fields[keys[j]]), keys[i], keys[j])
+	})
+
+	return keys
+}
+
+func positionOf(value interface{}) lexer.Position {
+	switch v := value.(type) {
+	case godot.Value:
+		return v.LexerPosition
+	case godot.Type:
+		return v.LexerPosition
+	case godot.Object:
+		return v.LexerPosition
+	case godot.KeyValuePair:
+		return v.LexerPosition
+	default:
+		return lexer.Position{}
+	}
+}
+
+func lessByPosition(a, b lexer.Position, aName, bName string) bool {
+	// entries without a position weren't parsed from a file, place them after all others
+	if (a.Line == 0) != (b.Line == 0) {
+		return b.Line == 0
+	}
+
+	if a.Offset != b.Offset {
+		return a.Offset < b.Offset
+	}
+
+	return compareIDs(aName, bName)
+}
+
+// compareIDs compares resource ids numerically if possible, Godot 3 uses integer ids
+func compareIDs(a, b string) bool {
+	aInt, aErr := strconv.ParseInt(a, 10, 64)
+	bInt, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr == nil && bErr == nil {
+		return aInt < bInt
+	}
+	return a < b
+}
+
+func convertIDToGdValue(id string, format int64) (*parser.GdValue, error) {
+	if format >= godot.FormatVersionGodot4 {
+		return &parser.GdValue{String: &id}, nil
+	}
+
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("resource id %s must be an integer in format %d", id, format)
+	}
+	return &parser.GdValue{Integer: &i}, nil
+}
+
+func newIntegerGdField(key string, value int64) *parser.GdField {
+	return &parser.GdField{Key: key, Value: &parser.GdValue{Integer: &value}}
+}
+
+func newStringGdField(key, value string) *parser.GdField {
+	return &parser.GdField{Key: key, Value: &parser.GdValue{String: &value}}
+}
+
+// newHeaderAttributes creates the attributes of the gd_scene/gd_resource header
+func newHeaderAttributes(resourceCount int, format int64, uid string) []*parser.GdField {
+	var attributes []*parser.GdField
+	if resourceCount > 0 {
+		attributes = append(attributes, newIntegerGdField("load_steps", int64(resourceCount+1)))
+	}
+	attributes = append(attributes, newIntegerGdField("format", format))
+	if uid != "" {
+		attributes = append(attributes, newStringGdField("uid", uid))
+	}
+	return attributes
+}
+
+// printOptionsForFormat returns the formatting Godot uses for the given format version
+func printOptionsForFormat(format int64) parser.PrintOptions {
+	return parser.PrintOptions{Godot3: format < godot.FormatVersionGodot4}
+}
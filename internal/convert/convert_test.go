@@ -48,6 +48,22 @@ func TestConvertGdValueForMap(t *testing.T) {
 	assert.Len(t, value, 3)
 }
 
+func TestConvertGdValueForMapWithStringNameKeys(t *testing.T) {
+	content := `value = {&"main": "res://icon.png", "main_string": 1, &"other": 3}`
+	tscn, _ := parser.Parse(strings.NewReader(content))
+	v, ok := convertGdValue(tscn.Fields[0].Value).(godot.Value)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]bool{"main": true, "other": true}, v.StringNameKeys)
+
+	gdValue, err := convertToGdValue(v)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"{\n&\"main\": \"res://icon.png\",\n\"main_string\": 1,\n&\"other\": 3\n}",
+		parser.FormatValue(gdValue, parser.PrintOptions{}),
+	)
+}
+
 func TestConvertGdValueForType(t *testing.T) {
 	content := `value = CustomType(1337, OtherType(12, 3))`
 	tscn, _ := parser.Parse(strings.NewReader(content))
@@ -75,7 +91,74 @@ func TestConvertGdValueForMapField(t *testing.T) {
 	assert.Equal(t, int64(37), bValue)
 }
 
+func TestConvertGdValueForObject(t *testing.T) {
+	content := `value = Object(InputEventKey,"device":0,"scancode":87)`
+	tscn, _ := parser.Parse(strings.NewReader(content))
+
+	object := convertGdValue(tscn.Fields[0].Value).(godot.Object)
+	assert.Equal(t, "InputEventKey", object.Class)
+	assert.Len(t, object.Properties, 2)
+	assert.Equal(t, int64(87), object.Properties["scancode"].(godot.Value).Value)
+}
+
+func TestConvertGdValueForObjectWithoutClass(t *testing.T) {
+	content := `value = Object("key":"value")`
+	tscn, _ := parser.Parse(strings.NewReader(content))
+
+	assert.IsType(t, godot.Type{}, convertGdValue(tscn.Fields[0].Value))
+}
+
+func TestConvertObjectToGdValueKeepsPropertyOrder(t *testing.T) {
+	content := `value = Object(InputEventKey,"resource_name":"","device":0,"alt":false)`
+	tscn, _ := parser.Parse(strings.NewReader(content))
+
+	object := convertGdValue(tscn.Fields[0].Value).(godot.Object)
+	object.Properties["alt"] = godot.Value{Value: true, MetaData: object.Properties["alt"].(godot.Value).MetaData}
+	object.Properties["unicode"] = int64(0)
+
+	gdValue, err := convertToGdValue(object)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`Object(InputEventKey,"resource_name":"","device":0,"alt":true,"unicode":0)`,
+		parser.FormatValue(gdValue, parser.PrintOptions{}),
+	)
+}
+
+func TestConvertToGdValue(t *testing.T) {
+	content := `value = [1, 2.5, "str", &"name", false, null, {"a": Vector2(1, 2)}, Object(InputEventKey,"device":0)]`
+	tscn, _ := parser.Parse(strings.NewReader(content))
+
+	gdValue, err := convertToGdValue(convertGdValue(tscn.Fields[0].Value))
+	assert.NoError(t, err)
+	options := parser.PrintOptions{}
+	assert.Equal(t, parser.FormatValue(tscn.Fields[0].Value, options), parser.FormatValue(gdValue, options))
+}
+
+func TestConvertToGdValueWithUnsupportedType(t *testing.T) {
+	_, err := convertToGdValue(struct{}{})
+	assert.Error(t, err)
+}
+
 // keep integration tests at the bottom please
+func TestConvertFieldsToGdFieldsFlattensExpandedFields(t *testing.T) {
+	fields := godot.ExpandFields(map[string]interface{}{
+		"length":        1.0,
+		"tracks/0/type": "value",
+		"tracks/1/type": "method",
+		"bones/0/name":  "Hip",
+	})
+
+	gdFields, err := convertFieldsToGdFields(fields)
+	assert.NoError(t, err)
+
+	var keys []string
+	for _, field := range gdFields {
+		keys = append(keys, field.Key)
+	}
+	assert.Equal(t, []string{"bones/0/name", "length", "tracks/0/type", "tracks/1/type"}, keys)
+}
+
 func TestIntegrationConvertFixtures(t *testing.T) {
 	cwd, err := os.Getwd()
 	if err != nil {
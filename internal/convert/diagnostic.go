@@ -0,0 +1,35 @@
+package convert
+
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+)
+
+// ConversionRuleID is the rule ID of diagnostics created for errors found during a conversion
+const ConversionRuleID = "conversion"
+
+// reporter decides what happens with errors found during a conversion, without a diagnostics list the conversion
+// stops at the first error, otherwise the error is collected and the conversion skips the broken part
+type reporter struct {
+	diagnostics *diagnostic.List
+}
+
+// report returns the error if the conversion has to stop, otherwise nil
+func (r reporter) report(err error, pos lexer.Position) error {
+	if r.diagnostics == nil {
+		return err
+	}
+
+	d := diagnostic.FromError(err)
+	d.RuleID = ConversionRuleID
+	// errors without a typed error in their chain don't know their location
+	if d.Position.Line == 0 {
+		d.Position = pos
+		d.Message = strings.TrimSuffix(d.Message, " "+pos.String())
+	}
+	r.diagnostics.Add(d)
+	return nil
+}
@@ -0,0 +1,59 @@
+package convert
+
+import (
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// ToGodotValue converts a single parser value into its representation in the godot package
+func ToGodotValue(value *parser.GdValue) interface{} {
+	return convertGdValue(value)
+}
+
+// FormatGodotValue converts a value from the godot package into its TSCN source
+func FormatGodotValue(value interface{}, options parser.PrintOptions) (string, error) {
+	gdValue, err := convertToGdValue(value)
+	if err != nil {
+		return "", err
+	}
+	return parser.FormatValue(gdValue, options), nil
+}
+
+// PrintOptionsForDocument returns the formatting Godot uses for the document, derived from its format version.
+// Documents without a gd_scene or gd_resource header are treated as ConfigFile (project.godot, *.import).
+func PrintOptionsForDocument(doc *parser.Document) parser.PrintOptions {
+	if header := doc.Header(); header != nil && isTscnFileType(header.ResourceType) {
+		format := int64(godot.FormatVersionGodot3)
+		if value := documentIntegerValue(header.Attribute("format")); value != nil {
+			format = *value
+		}
+		return printOptionsForFormat(format)
+	}
+
+	options := parser.PrintOptions{ConfigFile: true, Godot3: true}
+	for _, section := range doc.Sections {
+		// project.godot files of Godot 4 have config_version=5, import files of Godot 4 have a uid
+		if value := documentIntegerValue(section.Field("config_version")); value != nil {
+			options.Godot3 = *value < projectConfigVersionGodot4
+		}
+		if section.ResourceType == "remap" && section.Field("uid") != nil {
+			options.Godot3 = false
+		}
+	}
+	return options
+}
+
+func isTscnFileType(resourceType string) bool {
+	return resourceType == TscnTypeGodotScene || resourceType == TscnTypeGodotResource
+}
+
+func documentIntegerValue(entry *parser.Entry) *int64 {
+	if entry == nil {
+		return nil
+	}
+	value, err := entry.Value()
+	if err != nil {
+		return nil
+	}
+	return value.Integer
+}
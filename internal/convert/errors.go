@@ -0,0 +1,64 @@
+package convert
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+)
+
+// headerLocation returns the location of a problem found at pos within the file header
+func headerLocation(tscn *parser.TscnFile, pos lexer.Position) diagnostic.Location {
+	return diagnostic.Location{Position: pos, Section: tscn.Key, SectionPosition: tscn.Pos}
+}
+
+// invalidValue creates the error of an attribute or field with a value of the wrong type or format, the location is
+// extended to the end of the value
+func invalidValue(
+	location diagnostic.Location,
+	key string,
+	value *parser.GdValue,
+	format string,
+	args ...interface{},
+) error {
+	location.End = value.EndPos
+	return &diagnostic.InvalidValueError{Location: location, Key: key, Msg: fmt.Sprintf(format, args...)}
+}
+
+// requiredStringAttribute returns the value of an attribute which must exist and be a string
+func requiredStringAttribute(section *parser.GdResource, key string) (string, error) {
+	value, err := section.GetAttribute(key)
+	if err != nil {
+		return "", &diagnostic.MissingAttributeError{Location: section.Location(section.Pos), Attribute: key}
+	}
+	if value.String == nil {
+		location := section.Location(value.Pos)
+		return "", invalidValue(location, key, value, "%s attribute %s must be a string", section.ResourceType, key)
+	}
+	return *value.String, nil
+}
+
+// requiredIDAttribute returns the id attribute of a resource section, Godot 4 uses strings instead of integers
+func requiredIDAttribute(section *parser.GdResource) (string, error) {
+	value, err := section.GetAttribute("id")
+	if err != nil {
+		return "", &diagnostic.MissingAttributeError{Location: section.Location(section.Pos), Attribute: "id"}
+	}
+	id, ok := value.AsID()
+	if !ok {
+		location := section.Location(value.Pos)
+		return "", invalidValue(location, "id", value, "%s id must be an integer or a string", section.ResourceType)
+	}
+	return id, nil
+}
+
+// decodeError creates the error of a value which can't be decoded into the expected structure, e.g. by Unmarshal
+func decodeError(key string, pos lexer.Position, format string, args ...interface{}) error {
+	return &diagnostic.InvalidValueError{
+		Location: diagnostic.Location{Position: pos},
+		Key:      key,
+		Msg:      fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,192 @@
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// exportPresetSectionPattern matches the sections of a preset, e.g. preset.0 and preset.0.options
+var exportPresetSectionPattern = regexp.MustCompile(`^preset\.(\d+)(\.options)?$`)
+
+type exportPresetFields struct {
+	Name           string
+	Platform       string
+	Runnable       bool
+	CustomFeatures string
+	ExportFilter   string
+	IncludeFilter  string
+	ExcludeFilter  string
+	ExportPath     string
+}
+
+// ToGodotExportPresets tries to convert a TscnFile structure to godot.ExportPresets, presets are sorted by their index
+func ToGodotExportPresets(tscn *parser.TscnFile) (*godot.ExportPresets, error) {
+	sections, err := configSections(tscn)
+	if err != nil {
+		return nil, err
+	}
+
+	presets := &godot.ExportPresets{
+		Format: configFormatOf(tscn),
+		Rest:   make(map[string]map[string]interface{}),
+		MetaData: godot.MetaData{
+			LexerPosition: tscn.Pos,
+		},
+	}
+
+	presetsByIndex := make(map[int]*godot.ExportPreset)
+	var options []*parser.GdResource
+
+	for _, section := range sections {
+		if section.ResourceType == "" && len(section.Fields) == 0 {
+			continue
+		}
+
+		match := exportPresetSectionPattern.FindStringSubmatch(section.ResourceType)
+		if match == nil {
+			presets.Rest[section.ResourceType] = make(map[string]interface{})
+			insertFieldEntriesFromSection(section, presets.Rest[section.ResourceType])
+			continue
+		}
+
+		// options are assigned once all presets are known
+		if match[2] != "" {
+			options = append(options, section)
+			continue
+		}
+
+		index, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid preset index %s", section.Pos)
+		}
+
+		preset := &godot.ExportPreset{
+			Options:      make(map[string]interface{}),
+			Fields:       make(map[string]interface{}),
+			OptionFields: make(map[string]interface{}),
+			MetaData: godot.MetaData{
+				LexerPosition: section.Pos,
+			},
+		}
+		insertFieldEntriesFromSection(section, preset.Fields)
+		presetsByIndex[index] = preset
+	}
+
+	for _, section := range options {
+		index, _ := strconv.Atoi(exportPresetSectionPattern.FindStringSubmatch(section.ResourceType)[1])
+		preset, ok := presetsByIndex[index]
+		if !ok {
+			// Godot ignores options without preset, keep them so they aren't lost when the file is written
+			presets.Rest[section.ResourceType] = make(map[string]interface{})
+			insertFieldEntriesFromSection(section, presets.Rest[section.ResourceType])
+			continue
+		}
+		insertFieldEntriesFromSection(section, preset.OptionFields)
+	}
+
+	indices := make([]int, 0, len(presetsByIndex))
+	for index := range presetsByIndex {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	for _, index := range indices {
+		preset := presetsByIndex[index]
+		if err := decodeExportPresetFields(preset); err != nil {
+			return nil, errors.Wrapf(err, "could not decode section preset.%d", index)
+		}
+		presets.Presets = append(presets.Presets, preset)
+	}
+
+	return presets, nil
+}
+
+// decodeExportPresetFields fills the typed fields of the preset from its sections
+func decodeExportPresetFields(preset *godot.ExportPreset) error {
+	var fields exportPresetFields
+	if err := UnmarshalFields(preset.Fields, &fields); err != nil {
+		return err
+	}
+	preset.Name = fields.Name
+	preset.Platform = fields.Platform
+	preset.Runnable = fields.Runnable
+	preset.CustomFeatures = fields.CustomFeatures
+	preset.ExportFilter = fields.ExportFilter
+	preset.IncludeFilter = fields.IncludeFilter
+	preset.ExcludeFilter = fields.ExcludeFilter
+	preset.ExportPath = fields.ExportPath
+
+	for key, value := range preset.OptionFields {
+		preset.Options[key] = godot.Unwrap(value)
+	}
+
+	return nil
+}
+
+// FromGodotExportPresets converts godot.ExportPresets back into a TscnFile structure, the presets are numbered by their
+// position in the list
+func FromGodotExportPresets(presets *godot.ExportPresets) (*parser.TscnFile, parser.PrintOptions, error) {
+	var sections []*parser.GdResource
+
+	for index, preset := range presets.Presets {
+		fields := copyFields(preset.Fields)
+		setTypedField(fields, "name", preset.Name)
+		setTypedField(fields, "platform", preset.Platform)
+		setTypedField(fields, "runnable", preset.Runnable)
+		setTypedField(fields, "custom_features", preset.CustomFeatures)
+		setTypedField(fields, "export_filter", preset.ExportFilter)
+		setTypedField(fields, "include_filter", preset.IncludeFilter)
+		setTypedField(fields, "exclude_filter", preset.ExcludeFilter)
+		setTypedField(fields, "export_path", preset.ExportPath)
+
+		// options removed from Options are dropped, the parsed fields only provide the position of the remaining ones
+		options := make(map[string]interface{}, len(preset.Options))
+		for key, value := range preset.Options {
+			if existing, ok := preset.OptionFields[key]; ok {
+				options[key] = existing
+			}
+			setTypedField(options, key, value)
+		}
+
+		name := fmt.Sprintf("preset.%d", index)
+		for _, section := range []struct {
+			name   string
+			fields map[string]interface{}
+		}{{name, fields}, {name + ".options", options}} {
+			gdFields, err := convertFieldsToGdFields(section.fields)
+			if err != nil {
+				return nil, parser.PrintOptions{}, errors.Wrapf(err, "could not convert section %s", section.name)
+			}
+			sections = append(sections, &parser.GdResource{ResourceType: section.name, Fields: gdFields})
+		}
+	}
+
+	rest, err := convertSectionMapToSections(presets.Rest)
+	if err != nil {
+		return nil, parser.PrintOptions{}, err
+	}
+	sections = append(sections, rest...)
+
+	tscn := &parser.TscnFile{}
+	if len(sections) > 0 {
+		tscn.Key = sections[0].ResourceType
+		tscn.Fields = sections[0].Fields
+		tscn.Sections = sections[1:]
+	}
+
+	format := presets.Format
+	if format == 0 {
+		format = godot.FormatVersionGodot4
+	}
+
+	options := printOptionsForFormat(format)
+	options.ConfigFile = true
+	return tscn, options, nil
+}
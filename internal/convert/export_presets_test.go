@@ -0,0 +1,109 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestToGodotExportPresets(t *testing.T) {
+	content := `[preset.0]
+name="Windows Desktop"
+platform="Windows Desktop"
+runnable=true
+[preset.0.options]
+binary_format/64_bits=true
+[preset.3.options]
+orphan=1
+[custom]
+key="value"`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	presets, err := ToGodotExportPresets(tscnFile)
+	assert.NoError(t, err)
+
+	assert.Len(t, presets.Presets, 1)
+	preset := presets.Presets[0]
+	assert.Equal(t, "Windows Desktop", preset.Name)
+	assert.True(t, preset.Runnable)
+	assert.Equal(t, 1, preset.LexerPosition.Line)
+	assert.Equal(t, true, preset.Options["binary_format/64_bits"])
+	assert.Equal(t, true, preset.OptionFields["binary_format/64_bits"].(godot.Value).Value)
+
+	assert.Contains(t, presets.Rest, "preset.3.options")
+	assert.Equal(t, "value", presets.Rest["custom"]["key"].(godot.Value).Value)
+}
+
+func TestToGodotExportPresetsWithAndroidPreset(t *testing.T) {
+	content := `[preset.0]
+name="Android"
+platform="Android"
+runnable=true
+[preset.0.options]
+architectures/armeabi-v7a=false
+architectures/arm64-v8a=true
+package/unique_name="com.example.game"`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	presets, err := ToGodotExportPresets(tscnFile)
+	assert.NoError(t, err)
+
+	preset := presets.Presets[0]
+	assert.Equal(t, "Android", preset.Platform)
+	assert.Equal(t, false, preset.Options["architectures/armeabi-v7a"])
+	assert.Equal(t, true, preset.Options["architectures/arm64-v8a"])
+	assert.Equal(t, "com.example.game", preset.Options["package/unique_name"])
+}
+
+func TestFromGodotExportPresetsKeepsChangedFieldsInPlace(t *testing.T) {
+	content := `[preset.0]
+name="Web"
+export_path="build/index.html"
+runnable=false
+[preset.0.options]`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	presets, err := ToGodotExportPresets(tscnFile)
+	assert.NoError(t, err)
+	presets.Presets[0].ExportPath = "out/index.html"
+
+	result, options, err := FromGodotExportPresets(presets)
+	assert.NoError(t, err)
+	assert.True(t, options.ConfigFile)
+
+	assert.Equal(t, "preset.0", result.Key)
+	assert.Equal(t, "export_path", result.Fields[1].Key)
+	assert.Equal(t, "out/index.html", *result.Fields[1].Value.String)
+	assert.Equal(t, "preset.0.options", result.Sections[0].ResourceType)
+}
+
+func TestFromGodotExportPresetsRemovesDeletedOptions(t *testing.T) {
+	content := `[preset.0]
+name="Linux/X11"
+[preset.0.options]
+texture_format/bptc=false
+texture_format/s3tc=true
+texture_format/etc=false`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	presets, err := ToGodotExportPresets(tscnFile)
+	assert.NoError(t, err)
+	delete(presets.Presets[0].Options, "texture_format/s3tc")
+
+	result, _, err := FromGodotExportPresets(presets)
+	assert.NoError(t, err)
+
+	options := result.Sections[0]
+	assert.Equal(t, "preset.0.options", options.ResourceType)
+	assert.Len(t, options.Fields, 2)
+	assert.Equal(t, "texture_format/bptc", options.Fields[0].Key)
+	assert.Equal(t, "texture_format/etc", options.Fields[1].Key)
+}
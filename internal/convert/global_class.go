@@ -0,0 +1,117 @@
+package convert
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+const (
+	// autoloadSingletonPrefix marks autoloads which are available as global variable
+	autoloadSingletonPrefix = "*"
+	// globalScriptClassesField and globalScriptClassIconsField contain the global classes in Godot 3 projects
+	globalScriptClassesField    = "_global_script_classes"
+	globalScriptClassIconsField = "_global_script_class_icons"
+	// globalClassCacheField contains the global classes in .godot/global_script_class_cache.cfg
+	globalClassCacheField = "list"
+)
+
+type globalClassFields struct {
+	Class    string
+	Base     string
+	Path     string
+	Icon     string
+	Language string
+}
+
+// ToGodotAutoloads decodes the autoload section of a project in the order of the file
+func ToGodotAutoloads(project *godot.Project) ([]*godot.Autoload, error) {
+	var autoloads []*godot.Autoload
+	for _, name := range sortedFieldKeys(project.Autoload) {
+		raw, pos := unwrapValue(project.Autoload[name])
+		path, ok := raw.(string)
+		if !ok {
+			return nil, decodeError(name, pos, "autoload %s must be a path, got %T", name, raw)
+		}
+
+		autoloads = append(autoloads, &godot.Autoload{
+			Name:      name,
+			Path:      strings.TrimPrefix(path, autoloadSingletonPrefix),
+			Singleton: strings.HasPrefix(path, autoloadSingletonPrefix),
+			MetaData:  godot.MetaData{LexerPosition: pos},
+		})
+	}
+	return autoloads, nil
+}
+
+// ToGodotGlobalClasses decodes the global classes of a Godot 3 project, Godot 4 projects store them in a separate file
+// (see ToGodotGlobalClassCache) and result in an empty registry
+func ToGodotGlobalClasses(project *godot.Project) (*godot.GlobalClassRegistry, error) {
+	classes, err := decodeGlobalClasses(project.Fields, globalScriptClassesField)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields struct {
+		Icons map[string]string `godot:"_global_script_class_icons"`
+	}
+	if err := UnmarshalFields(project.Fields, &fields); err != nil {
+		return nil, errors.Wrapf(err, "could not decode %s", globalScriptClassIconsField)
+	}
+
+	for _, class := range classes {
+		class.Icon = fields.Icons[class.Name]
+	}
+
+	return godot.NewGlobalClassRegistry(classes...), nil
+}
+
+// ToGodotGlobalClassCache decodes the global classes of a Godot 4 project from .godot/global_script_class_cache.cfg
+func ToGodotGlobalClassCache(config *godot.ConfigFile) (*godot.GlobalClassRegistry, error) {
+	classes, err := decodeGlobalClasses(config.SectionFields(""), globalClassCacheField)
+	if err != nil {
+		return nil, err
+	}
+	return godot.NewGlobalClassRegistry(classes...), nil
+}
+
+// decodeGlobalClasses decodes a list of class dictionaries, which looks the same in Godot 3 and 4 except for the
+// StringNames used by Godot 4
+func decodeGlobalClasses(fields map[string]interface{}, key string) ([]*godot.GlobalClass, error) {
+	value, ok := fields[key]
+	if !ok {
+		return nil, nil
+	}
+
+	raw, pos := unwrapValue(value)
+	elements, ok := collectionOf(raw).([]interface{})
+	if !ok {
+		return nil, decodeError(key, pos, "%s must be an array, got %T", key, raw)
+	}
+
+	classes := make([]*godot.GlobalClass, len(elements))
+	for index, element := range elements {
+		raw, pos := unwrapValue(element)
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, decodeError(key, pos, "%s must contain dictionaries, got %T", key, raw)
+		}
+
+		var class globalClassFields
+		if err := UnmarshalFields(m, &class); err != nil {
+			return nil, errors.Wrapf(err, "could not decode %s", key)
+		}
+
+		classes[index] = &godot.GlobalClass{
+			Name:     class.Class,
+			Base:     class.Base,
+			Path:     class.Path,
+			Icon:     class.Icon,
+			Language: class.Language,
+			MetaData: godot.MetaData{LexerPosition: pos},
+		}
+	}
+	return classes, nil
+}
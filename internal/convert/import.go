@@ -1,17 +1,39 @@
 package convert
 
 import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
 	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
 )
 
+// importPlatformPathPrefix is the prefix of the remap fields of platform specific imports, e.g. path.s3tc
+const importPlatformPathPrefix = "path."
+
+type importRemapFields struct {
+	Importer string
+	Type     string
+	UID      string `godot:"uid"`
+	Path     string
+}
+
+type importDepsFields struct {
+	SourceFile string
+	DestFiles  []string
+}
+
 // ToGodotImport tries to convert a TscnFile structure to a godot.Import
 func ToGodotImport(tscn *parser.TscnFile) (*godot.Import, error) {
 	imp := &godot.Import{
-		Remap:  make(map[string]interface{}),
-		Deps:   make(map[string]interface{}),
-		Params: make(map[string]interface{}),
-		Rest:   make(map[string]map[string]interface{}),
+		PlatformPaths: make(map[string]string),
+		Parameters:    make(map[string]interface{}),
+		Remap:         make(map[string]interface{}),
+		Deps:          make(map[string]interface{}),
+		Params:        make(map[string]interface{}),
+		Rest:          make(map[string]map[string]interface{}),
 		MetaData: godot.MetaData{
 			LexerPosition: tscn.Pos,
 		},
@@ -42,5 +64,142 @@ func ToGodotImport(tscn *parser.TscnFile) (*godot.Import, error) {
 		insertFieldEntriesFromSection(section, imp.Rest[section.ResourceType])
 	}
 
+	if err := decodeImportFields(imp); err != nil {
+		return nil, err
+	}
+
 	return imp, nil
 }
+
+// decodeImportFields fills the typed fields of the import from its sections
+func decodeImportFields(imp *godot.Import) error {
+	var remap importRemapFields
+	if err := UnmarshalFields(imp.Remap, &remap); err != nil {
+		return errors.Wrap(err, "could not decode remap section")
+	}
+	imp.Importer = remap.Importer
+	imp.Type = remap.Type
+	imp.UID = remap.UID
+	imp.Path = remap.Path
+
+	for key, value := range imp.Remap {
+		platform := strings.TrimPrefix(key, importPlatformPathPrefix)
+		if platform == key {
+			continue
+		}
+
+		var path string
+		if err := unmarshalValue(value, reflect.ValueOf(&path).Elem(), key); err != nil {
+			return errors.Wrap(err, "could not decode remap section")
+		}
+		imp.PlatformPaths[platform] = path
+	}
+
+	var deps importDepsFields
+	if err := UnmarshalFields(imp.Deps, &deps); err != nil {
+		return errors.Wrap(err, "could not decode deps section")
+	}
+	imp.SourceFile = deps.SourceFile
+	imp.DestFiles = deps.DestFiles
+
+	for key, value := range imp.Params {
+		imp.Parameters[key] = godot.Unwrap(value)
+	}
+
+	return nil
+}
+
+// FromGodotImport converts a godot.Import back into a TscnFile structure and returns the formatting matching the
+// Godot version which wrote the file
+func FromGodotImport(imp *godot.Import) (*parser.TscnFile, parser.PrintOptions, error) {
+	remapFields := copyFields(imp.Remap)
+	setImportField(remapFields, "importer", imp.Importer)
+	setImportField(remapFields, "type", imp.Type)
+	setImportField(remapFields, "uid", imp.UID)
+	setImportField(remapFields, "path", imp.Path)
+	for key := range remapFields {
+		platform := strings.TrimPrefix(key, importPlatformPathPrefix)
+		if _, ok := imp.PlatformPaths[platform]; platform != key && !ok {
+			delete(remapFields, key)
+		}
+	}
+	for platform, path := range imp.PlatformPaths {
+		setImportField(remapFields, importPlatformPathPrefix+platform, path)
+	}
+
+	remap, err := convertFieldsToGdFields(remapFields)
+	if err != nil {
+		return nil, parser.PrintOptions{}, errors.Wrap(err, "could not convert remap section")
+	}
+
+	tscn := &parser.TscnFile{Key: "remap", Fields: remap}
+
+	deps := copyFields(imp.Deps)
+	setImportField(deps, "source_file", imp.SourceFile)
+	if imp.DestFiles != nil {
+		setImportField(deps, "dest_files", imp.DestFiles)
+	}
+
+	params := make(map[string]interface{}, len(imp.Parameters))
+	for key, value := range imp.Parameters {
+		if existing, ok := imp.Params[key]; ok {
+			params[key] = existing
+		}
+		setTypedField(params, key, value)
+	}
+
+	sections := make(map[string]map[string]interface{})
+	for name, section := range imp.Rest {
+		sections[name] = section
+	}
+	sections["deps"] = deps
+	sections["params"] = params
+
+	gdSections, err := convertSectionMapToSections(sections)
+	if err != nil {
+		return nil, parser.PrintOptions{}, err
+	}
+	tscn.Sections = gdSections
+
+	// only Godot 4 stores uids in import files
+	format := int64(godot.FormatVersionGodot3)
+	if _, ok := remapFields["uid"]; ok {
+		format = godot.FormatVersionGodot4
+	}
+
+	options := printOptionsForFormat(format)
+	options.ConfigFile = true
+	return tscn, options, nil
+}
+
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		result[key] = value
+	}
+	return result
+}
+
+// setImportField sets a field to the value of a typed field like setTypedField, empty values remove the field
+func setImportField(fields map[string]interface{}, key string, value interface{}) {
+	if s, ok := value.(string); ok && s == "" {
+		delete(fields, key)
+		return
+	}
+	setTypedField(fields, key, value)
+}
+
+// setTypedField sets a field to the value of a typed field. Fields which already exist keep their position, so they
+// stay in place when the file is written.
+func setTypedField(fields map[string]interface{}, key string, value interface{}) {
+	existing, ok := fields[key]
+	if !ok {
+		fields[key] = value
+		return
+	}
+
+	if reflect.DeepEqual(godot.Unwrap(existing), godot.Unwrap(value)) {
+		return
+	}
+	fields[key] = godot.Value{Value: value, MetaData: godot.MetaData{LexerPosition: positionOf(existing)}}
+}
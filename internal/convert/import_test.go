@@ -37,3 +37,115 @@ customfield=1337`
 	customFieldValue := customField.(godot.Value)
 	assert.Equal(t, int64(1337), customFieldValue.Value)
 }
+
+func TestFromGodotImport(t *testing.T) {
+	content := `[remap]
+importer="texture"
+type="CompressedTexture2D"
+uid="uid://b3k2p4ehb0xwy"
+[deps]
+source_file="res://icon.png"
+[params]
+compress/mode=0`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	imp, err := ToGodotImport(tscnFile)
+	assert.NoError(t, err)
+
+	tscnFile, options, err := FromGodotImport(imp)
+	assert.NoError(t, err)
+	assert.True(t, options.ConfigFile)
+	assert.False(t, options.Godot3)
+
+	assert.Equal(t, "remap", tscnFile.Key)
+	assert.Len(t, tscnFile.Fields, 3)
+	assert.Equal(t, "deps", tscnFile.Sections[0].ResourceType)
+	assert.Equal(t, "params", tscnFile.Sections[1].ResourceType)
+}
+
+func TestFromGodotImportWritesTypedFields(t *testing.T) {
+	content := `[remap]
+importer="texture"
+type="StreamTexture"
+path="res://.import/icon.png-487276ed1e3a0c39cad0279d744ee560.stex"
+[deps]
+source_file="res://icon.png"
+[params]
+compress/mode=0
+flags/filter=true`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	imp, err := ToGodotImport(tscnFile)
+	assert.NoError(t, err)
+
+	imp.Type = "Texture"
+	imp.PlatformPaths["s3tc"] = "res://.import/icon.png.s3tc.stex"
+	imp.Parameters["flags/filter"] = false
+	imp.Parameters["flags/mipmaps"] = true
+
+	tscnFile, _, err = FromGodotImport(imp)
+	assert.NoError(t, err)
+
+	var sb strings.Builder
+	assert.NoError(t, parser.Print(&sb, tscnFile, parser.PrintOptions{ConfigFile: true}))
+	assert.Equal(t, `[remap]
+
+importer="texture"
+type="Texture"
+path="res://.import/icon.png-487276ed1e3a0c39cad0279d744ee560.stex"
+path.s3tc="res://.import/icon.png.s3tc.stex"
+
+[deps]
+
+source_file="res://icon.png"
+
+[params]
+
+compress/mode=0
+flags/filter=false
+flags/mipmaps=true
+`, sb.String())
+}
+
+func TestFromGodotImportRemovesClearedFields(t *testing.T) {
+	content := `[remap]
+importer="texture"
+type="StreamTexture"
+path.s3tc="res://.import/icon.png.s3tc.stex"
+path.etc2="res://.import/icon.png.etc2.stex"
+[deps]
+source_file="res://icon.png"
+[params]
+compress/mode=0
+flags/filter=true`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	imp, err := ToGodotImport(tscnFile)
+	assert.NoError(t, err)
+
+	imp.Type = ""
+	delete(imp.PlatformPaths, "etc2")
+	delete(imp.Parameters, "flags/filter")
+
+	tscnFile, _, err = FromGodotImport(imp)
+	assert.NoError(t, err)
+
+	var sb strings.Builder
+	assert.NoError(t, parser.Print(&sb, tscnFile, parser.PrintOptions{ConfigFile: true}))
+	assert.Equal(t, `[remap]
+
+importer="texture"
+path.s3tc="res://.import/icon.png.s3tc.stex"
+
+[deps]
+
+source_file="res://icon.png"
+
+[params]
+
+compress/mode=0
+`, sb.String())
+}
@@ -0,0 +1,207 @@
+package convert
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// defaultInputDeadzone is the deadzone Godot uses for actions without one
+const defaultInputDeadzone = 0.5
+
+// Input event classes with a typed model
+const (
+	inputEventKeyClass          = "InputEventKey"
+	inputEventMouseButtonClass  = "InputEventMouseButton"
+	inputEventJoypadButtonClass = "InputEventJoypadButton"
+	inputEventJoypadMotionClass = "InputEventJoypadMotion"
+)
+
+type inputActionFields struct {
+	Deadzone *float64
+	Events   []interface{}
+}
+
+// inputModifierFields contains the Godot 3 and the Godot 4 names of the modifiers, e.g. control and ctrl_pressed
+type inputModifierFields struct {
+	Alt          bool
+	AltPressed   bool
+	Shift        bool
+	ShiftPressed bool
+	Control      bool
+	CtrlPressed  bool
+	Meta         bool
+	MetaPressed  bool
+	Command      bool
+}
+
+// decodeInputModifiers decodes the modifiers of key and mouse button events
+func decodeInputModifiers(object godot.Object) (godot.InputModifiers, error) {
+	var m inputModifierFields
+	if err := UnmarshalFields(object.Properties, &m); err != nil {
+		return godot.InputModifiers{}, err
+	}
+	return godot.InputModifiers{
+		Alt:     m.Alt || m.AltPressed,
+		Shift:   m.Shift || m.ShiftPressed,
+		Ctrl:    m.Control || m.CtrlPressed,
+		Meta:    m.Meta || m.MetaPressed,
+		Command: m.Command,
+	}, nil
+}
+
+type inputEventKeyFields struct {
+	Device           int64
+	Scancode         int64
+	Keycode          int64
+	PhysicalScancode int64
+	PhysicalKeycode  int64
+	Unicode          int64
+}
+
+type inputEventMouseButtonFields struct {
+	Device      int64
+	ButtonIndex int64
+	Doubleclick bool
+	DoubleClick bool
+}
+
+type inputEventJoypadButtonFields struct {
+	Device      int64
+	ButtonIndex int64
+	Pressure    float64
+}
+
+type inputEventJoypadMotionFields struct {
+	Device    int64
+	Axis      int64
+	AxisValue float64
+}
+
+// ToGodotInputMap decodes the input section of a project, keycodes are named according to the format of the project
+func ToGodotInputMap(project *godot.Project) (*godot.InputMap, error) {
+	format := projectFormatOf(project)
+	inputMap := &godot.InputMap{}
+
+	for _, name := range sortedFieldKeys(project.Input) {
+		action, err := toGodotInputAction(name, project.Input[name], format)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode input action %s", name)
+		}
+		inputMap.Actions = append(inputMap.Actions, action)
+	}
+
+	return inputMap, nil
+}
+
+func toGodotInputAction(name string, value interface{}, format int64) (*godot.InputAction, error) {
+	raw, pos := unwrapValue(value)
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, decodeError(name, pos, "input action %s must be a dictionary, got %T", name, raw)
+	}
+
+	var fields inputActionFields
+	if err := UnmarshalFields(m, &fields); err != nil {
+		return nil, err
+	}
+
+	action := &godot.InputAction{
+		Name:     name,
+		Deadzone: defaultInputDeadzone,
+		MetaData: godot.MetaData{LexerPosition: pos},
+	}
+	if fields.Deadzone != nil {
+		action.Deadzone = *fields.Deadzone
+	}
+
+	for _, value := range fields.Events {
+		raw, pos := unwrapValue(value)
+		object, ok := raw.(godot.Object)
+		if !ok {
+			return nil, decodeError("events", pos, "input events must be objects, got %T", raw)
+		}
+
+		event, err := toGodotInputEvent(object, format)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode %s", object.Class)
+		}
+		action.Events = append(action.Events, event)
+	}
+
+	return action, nil
+}
+
+func toGodotInputEvent(object godot.Object, format int64) (godot.InputEvent, error) {
+	switch object.Class {
+	case inputEventKeyClass:
+		var fields inputEventKeyFields
+		if err := UnmarshalFields(object.Properties, &fields); err != nil {
+			return nil, err
+		}
+		modifiers, err := decodeInputModifiers(object)
+		if err != nil {
+			return nil, err
+		}
+
+		event := &godot.InputEventKey{
+			Device:          fields.Device,
+			InputModifiers:  modifiers,
+			Keycode:         fields.Keycode,
+			PhysicalKeycode: fields.PhysicalKeycode,
+			Unicode:         fields.Unicode,
+			Object:          object,
+		}
+		// Godot 3 calls keycodes scancodes
+		if format < godot.FormatVersionGodot4 {
+			event.Keycode = fields.Scancode
+			event.PhysicalKeycode = fields.PhysicalScancode
+		}
+
+		event.Key = godot.KeycodeName(event.Keycode, format)
+		if event.Keycode == 0 && event.PhysicalKeycode != 0 {
+			event.Key = godot.KeycodeName(event.PhysicalKeycode, format)
+		}
+		return event, nil
+	case inputEventMouseButtonClass:
+		var fields inputEventMouseButtonFields
+		if err := UnmarshalFields(object.Properties, &fields); err != nil {
+			return nil, err
+		}
+		modifiers, err := decodeInputModifiers(object)
+		if err != nil {
+			return nil, err
+		}
+		return &godot.InputEventMouseButton{
+			Device:         fields.Device,
+			InputModifiers: modifiers,
+			ButtonIndex:    fields.ButtonIndex,
+			DoubleClick:    fields.Doubleclick || fields.DoubleClick,
+			Object:         object,
+		}, nil
+	case inputEventJoypadButtonClass:
+		var fields inputEventJoypadButtonFields
+		if err := UnmarshalFields(object.Properties, &fields); err != nil {
+			return nil, err
+		}
+		return &godot.InputEventJoypadButton{
+			Device:      fields.Device,
+			ButtonIndex: fields.ButtonIndex,
+			Pressure:    fields.Pressure,
+			Object:      object,
+		}, nil
+	case inputEventJoypadMotionClass:
+		var fields inputEventJoypadMotionFields
+		if err := UnmarshalFields(object.Properties, &fields); err != nil {
+			return nil, err
+		}
+		return &godot.InputEventJoypadMotion{
+			Device:    fields.Device,
+			Axis:      fields.Axis,
+			AxisValue: fields.AxisValue,
+			Object:    object,
+		}, nil
+	default:
+		return &godot.InputEventObject{Object: object}, nil
+	}
+}
@@ -0,0 +1,62 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestToGodotInputMap(t *testing.T) {
+	content := `config_version=4
+[input]
+b={
+"events": [ Object(InputEventKey,"scancode":16777217,"physical_scancode":0,"shift":true)
+, Object(InputEventAction,"action":"a")
+ ]
+}
+a={
+"deadzone": 0.2,
+"events": [ ]
+}`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+	project, err := ToGodotProject(tscnFile)
+	assert.NoError(t, err)
+
+	inputMap, err := ToGodotInputMap(project)
+	assert.NoError(t, err)
+	assert.Len(t, inputMap.Actions, 2)
+
+	b := inputMap.Actions[0]
+	assert.Equal(t, "b", b.Name)
+	assert.Equal(t, defaultInputDeadzone, b.Deadzone)
+	assert.Equal(t, 3, b.LexerPosition.Line)
+
+	key := b.Events[0].(*godot.InputEventKey)
+	assert.Equal(t, int64(16777217), key.Keycode)
+	assert.Equal(t, "Escape", key.Key)
+	assert.True(t, key.Shift)
+
+	action := b.Events[1].(*godot.InputEventObject)
+	assert.Equal(t, "a", action.Property("action"))
+
+	assert.Equal(t, 0.2, inputMap.Actions[1].Deadzone)
+	assert.Empty(t, inputMap.Actions[1].Events)
+}
+
+func TestToGodotInputMapWithInvalidEvent(t *testing.T) {
+	project := &godot.Project{
+		Fields: map[string]interface{}{},
+		Input: map[string]interface{}{
+			"jump": godot.Value{Value: map[string]interface{}{"events": []interface{}{int64(1)}}},
+		},
+	}
+
+	_, err := ToGodotInputMap(project)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "input events must be objects")
+}
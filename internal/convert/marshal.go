@@ -0,0 +1,139 @@
+package convert
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// MarshalFields converts a struct into a field map for nodes and resources, it is the counterpart of UnmarshalFields
+func MarshalFields(v interface{}) (map[string]interface{}, error) {
+	source := reflect.ValueOf(v)
+	for source.Kind() == reflect.Ptr && !source.IsNil() {
+		source = source.Elem()
+	}
+
+	if source.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can only marshal structs, got %T", v)
+	}
+
+	return marshalStruct(source)
+}
+
+func marshalStruct(source reflect.Value) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	sourceType := source.Type()
+
+	for index := 0; index < sourceType.NumField(); index++ {
+		key, omitEmpty, ok := structFieldKey(sourceType.Field(index))
+		if !ok {
+			continue
+		}
+
+		field := source.Field(index)
+		if omitEmpty && field.IsZero() {
+			continue
+		}
+
+		value, err := marshalValue(field, key)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = value
+	}
+
+	return fields, nil
+}
+
+func marshalValue(source reflect.Value, key string) (interface{}, error) {
+	switch source.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if source.IsNil() {
+			return nil, nil
+		}
+		return marshalValue(source.Elem(), key)
+	}
+
+	if source.Type() == referenceType {
+		return marshalReference(source.Interface().(godot.Reference)), nil
+	}
+
+	switch source.Kind() {
+	case reflect.Bool:
+		return source.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return source.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(source.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return source.Float(), nil
+	case reflect.String:
+		if source.Type() == reflect.TypeOf(godot.StringName("")) {
+			return source.Interface(), nil
+		}
+		return source.String(), nil
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, source.Len())
+		for index := range values {
+			value, err := marshalValue(source.Index(index), fmt.Sprintf("%s[%d]", key, index))
+			if err != nil {
+				return nil, err
+			}
+			values[index] = value
+		}
+		return values, nil
+	case reflect.Map:
+		if source.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("can't marshal %s, dictionary keys must be strings", key)
+		}
+		m := make(map[string]interface{}, source.Len())
+		iter := source.MapRange()
+		for iter.Next() {
+			mapKey := iter.Key().String()
+			value, err := marshalValue(iter.Value(), key+"."+mapKey)
+			if err != nil {
+				return nil, err
+			}
+			m[mapKey] = value
+		}
+		return m, nil
+	case reflect.Struct:
+		// values of the godot package like godot.Vector2 or godot.Type are written as they are
+		if _, ok := convertMathTypeToGdType(source.Interface()); ok || isGodotValueType(source.Type()) {
+			return source.Interface(), nil
+		}
+		return marshalStruct(source)
+	default:
+		return nil, fmt.Errorf("can't marshal %s of type %s", key, source.Type())
+	}
+}
+
+func isGodotValueType(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeOf(godot.Value{}),
+		reflect.TypeOf(godot.Type{}),
+		reflect.TypeOf(godot.KeyValuePair{}),
+		reflect.TypeOf(godot.Object{}):
+		return true
+	default:
+		return false
+	}
+}
+
+// marshalReference converts a reference into ExtResource( 1 ) or SubResource( 1 ), numeric ids are written as
+// integers like Godot 3 does
+func marshalReference(ref godot.Reference) godot.Type {
+	identifier := "SubResource"
+	if ref.External {
+		identifier = "ExtResource"
+	}
+
+	var id interface{} = ref.ID
+	if i, err := strconv.ParseInt(ref.ID, 10, 64); err == nil {
+		id = i
+	}
+
+	return godot.Type{Identifier: identifier, Parameters: []interface{}{id}}
+}
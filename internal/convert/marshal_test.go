@@ -0,0 +1,73 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestMarshalFields(t *testing.T) {
+	type sprite struct {
+		Texture  godot.Reference
+		Hframes  int `godot:"hframes"`
+		Offset   *godot.Vector2
+		Flip     bool   `godot:"flip_h,omitempty"`
+		Comment  string `godot:"-"`
+		Frames   []int
+		Metadata map[string]float64
+	}
+
+	fields, err := MarshalFields(&sprite{
+		Texture:  godot.Reference{External: true, ID: "2"},
+		Hframes:  6,
+		Frames:   []int{1, 2},
+		Metadata: map[string]float64{"weight": 0.5},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"texture":  godot.Type{Identifier: "ExtResource", Parameters: []interface{}{int64(2)}},
+		"hframes":  int64(6),
+		"offset":   nil,
+		"frames":   []interface{}{int64(1), int64(2)},
+		"metadata": map[string]interface{}{"weight": 0.5},
+	}, fields)
+
+	gdFields, err := convertFieldsToGdFields(fields)
+	assert.NoError(t, err)
+	assert.Len(t, gdFields, 5)
+}
+
+func TestMarshalFieldsRoundTrip(t *testing.T) {
+	player := testPlayer{
+		CollisionLayer: 3,
+		Name:           "Hero",
+		Tag:            "player",
+		Position:       godot.Vector2{X: 1, Y: 2},
+		Script:         godot.Reference{External: true, ID: "1_x7k2p"},
+		Items:          []string{"sword"},
+		Points:         []godot.Vector2{},
+		Scores:         map[string]int{"level1": 10},
+		Hitbox:         testHitbox{Shape: godot.Reference{ID: "3"}},
+	}
+
+	fields, err := MarshalFields(player)
+	assert.NoError(t, err)
+
+	var decoded testPlayer
+	assert.NoError(t, UnmarshalFields(fields, &decoded))
+	assert.Equal(t, player, decoded)
+}
+
+func TestMarshalFieldsWithInvalidInput(t *testing.T) {
+	_, err := MarshalFields(42)
+	assert.Error(t, err)
+
+	_, err = MarshalFields(struct{ Callback func() }{})
+	assert.Error(t, err)
+
+	_, err = MarshalFields(struct{ Lookup map[int]string }{Lookup: map[int]string{}})
+	assert.Error(t, err)
+}
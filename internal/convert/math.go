@@ -0,0 +1,196 @@
+package convert
+
+import (
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// convertGdTypeToMathType converts types like Vector2( 1, 2 ) into their counterpart in the godot package. Returns
+// false if the type isn't a math type or its parameters don't match the type.
+func convertGdTypeToMathType(t *parser.GdType) (interface{}, bool) {
+	switch t.Key {
+	case "Vector2i", "Vector3i", "Rect2i":
+		return convertIntegerMathType(t)
+	}
+
+	f, ok := floatParameters(t)
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case t.Key == "Vector2" && len(f) == 2:
+		return godot.Vector2{X: f[0], Y: f[1]}, true
+	case t.Key == "Vector3" && len(f) == 3:
+		return godot.Vector3{X: f[0], Y: f[1], Z: f[2]}, true
+	case t.Key == "Vector4" && len(f) == 4:
+		return godot.Vector4{X: f[0], Y: f[1], Z: f[2], W: f[3]}, true
+	case t.Key == "Rect2" && len(f) == 4:
+		return godot.Rect2{Position: godot.Vector2{X: f[0], Y: f[1]}, Size: godot.Vector2{X: f[2], Y: f[3]}}, true
+	case t.Key == "Transform2D" && len(f) == 6:
+		return godot.Transform2D{
+			X:      godot.Vector2{X: f[0], Y: f[1]},
+			Y:      godot.Vector2{X: f[2], Y: f[3]},
+			Origin: godot.Vector2{X: f[4], Y: f[5]},
+		}, true
+	case t.Key == "Basis" && len(f) == 9:
+		return basisFromRowMajor(f), true
+	case (t.Key == "Transform" || t.Key == "Transform3D") && len(f) == 12:
+		return godot.Transform3D{
+			Basis:  basisFromRowMajor(f[:9]),
+			Origin: godot.Vector3{X: f[9], Y: f[10], Z: f[11]},
+		}, true
+	case (t.Key == "Quat" || t.Key == "Quaternion") && len(f) == 4:
+		return godot.Quaternion{X: f[0], Y: f[1], Z: f[2], W: f[3]}, true
+	case t.Key == "AABB" && len(f) == 6:
+		return godot.AABB{
+			Position: godot.Vector3{X: f[0], Y: f[1], Z: f[2]},
+			Size:     godot.Vector3{X: f[3], Y: f[4], Z: f[5]},
+		}, true
+	case t.Key == "Plane" && len(f) == 4:
+		return godot.Plane{Normal: godot.Vector3{X: f[0], Y: f[1], Z: f[2]}, D: f[3]}, true
+	case t.Key == "Color" && len(f) == 4:
+		return godot.Color{R: f[0], G: f[1], B: f[2], A: f[3]}, true
+	case t.Key == "Projection" && len(f) == 16:
+		return godot.Projection{
+			X: godot.Vector4{X: f[0], Y: f[1], Z: f[2], W: f[3]},
+			Y: godot.Vector4{X: f[4], Y: f[5], Z: f[6], W: f[7]},
+			Z: godot.Vector4{X: f[8], Y: f[9], Z: f[10], W: f[11]},
+			W: godot.Vector4{X: f[12], Y: f[13], Z: f[14], W: f[15]},
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+func convertIntegerMathType(t *parser.GdType) (interface{}, bool) {
+	i, ok := integerParameters(t)
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case t.Key == "Vector2i" && len(i) == 2:
+		return godot.Vector2i{X: i[0], Y: i[1]}, true
+	case t.Key == "Vector3i" && len(i) == 3:
+		return godot.Vector3i{X: i[0], Y: i[1], Z: i[2]}, true
+	case t.Key == "Rect2i" && len(i) == 4:
+		return godot.Rect2i{Position: godot.Vector2i{X: i[0], Y: i[1]}, Size: godot.Vector2i{X: i[2], Y: i[3]}}, true
+	default:
+		return nil, false
+	}
+}
+
+func floatParameters(t *parser.GdType) ([]float64, bool) {
+	if len(t.TypeParameters) > 0 {
+		return nil, false
+	}
+
+	params := make([]float64, len(t.Parameters))
+	for index, param := range t.Parameters {
+		switch {
+		case param.Float != nil:
+			params[index] = *param.Float
+		case param.Integer != nil:
+			params[index] = float64(*param.Integer)
+		default:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func integerParameters(t *parser.GdType) ([]int64, bool) {
+	if len(t.TypeParameters) > 0 {
+		return nil, false
+	}
+
+	params := make([]int64, len(t.Parameters))
+	for index, param := range t.Parameters {
+		if param.Integer == nil {
+			return nil, false
+		}
+		params[index] = *param.Integer
+	}
+	return params, true
+}
+
+// basisFromRowMajor creates a basis from the row by row representation Godot uses in files
+func basisFromRowMajor(f []float64) godot.Basis {
+	return godot.Basis{
+		X: godot.Vector3{X: f[0], Y: f[3], Z: f[6]},
+		Y: godot.Vector3{X: f[1], Y: f[4], Z: f[7]},
+		Z: godot.Vector3{X: f[2], Y: f[5], Z: f[8]},
+	}
+}
+
+func basisToRowMajor(b godot.Basis) []float64 {
+	var f []float64
+	for _, row := range b.Rows() {
+		f = append(f, row.X, row.Y, row.Z)
+	}
+	return f
+}
+
+// convertMathTypeToGdType converts a math type or a node path of the godot package into its parser representation, the
+// Godot 4 type names are used (e.g. Transform3D), the printer renames them for Godot 3 files
+func convertMathTypeToGdType(value interface{}) (*parser.GdType, bool) {
+	switch v := value.(type) {
+	case godot.NodePath:
+		path := v.String()
+		return &parser.GdType{Key: "NodePath", HasParameterList: true, Parameters: []*parser.GdValue{{String: &path}}}, true
+	case godot.Vector2:
+		return newFloatGdType("Vector2", v.X, v.Y), true
+	case godot.Vector2i:
+		return newIntegerGdType("Vector2i", v.X, v.Y), true
+	case godot.Vector3:
+		return newFloatGdType("Vector3", v.X, v.Y, v.Z), true
+	case godot.Vector3i:
+		return newIntegerGdType("Vector3i", v.X, v.Y, v.Z), true
+	case godot.Vector4:
+		return newFloatGdType("Vector4", v.X, v.Y, v.Z, v.W), true
+	case godot.Rect2:
+		return newFloatGdType("Rect2", v.Position.X, v.Position.Y, v.Size.X, v.Size.Y), true
+	case godot.Rect2i:
+		return newIntegerGdType("Rect2i", v.Position.X, v.Position.Y, v.Size.X, v.Size.Y), true
+	case godot.Transform2D:
+		return newFloatGdType("Transform2D", v.X.X, v.X.Y, v.Y.X, v.Y.Y, v.Origin.X, v.Origin.Y), true
+	case godot.Basis:
+		return newFloatGdType("Basis", basisToRowMajor(v)...), true
+	case godot.Transform3D:
+		f := append(basisToRowMajor(v.Basis), v.Origin.X, v.Origin.Y, v.Origin.Z)
+		return newFloatGdType("Transform3D", f...), true
+	case godot.Quaternion:
+		return newFloatGdType("Quaternion", v.X, v.Y, v.Z, v.W), true
+	case godot.AABB:
+		return newFloatGdType("AABB", v.Position.X, v.Position.Y, v.Position.Z, v.Size.X, v.Size.Y, v.Size.Z), true
+	case godot.Plane:
+		return newFloatGdType("Plane", v.Normal.X, v.Normal.Y, v.Normal.Z, v.D), true
+	case godot.Color:
+		return newFloatGdType("Color", v.R, v.G, v.B, v.A), true
+	case godot.Projection:
+		var f []float64
+		for _, column := range []godot.Vector4{v.X, v.Y, v.Z, v.W} {
+			f = append(f, column.X, column.Y, column.Z, column.W)
+		}
+		return newFloatGdType("Projection", f...), true
+	default:
+		return nil, false
+	}
+}
+
+func newFloatGdType(key string, values ...float64) *parser.GdType {
+	t := &parser.GdType{Key: key, HasParameterList: true}
+	for index := range values {
+		t.Parameters = append(t.Parameters, &parser.GdValue{Float: &values[index]})
+	}
+	return t
+}
+
+func newIntegerGdType(key string, values ...int64) *parser.GdType {
+	t := &parser.GdType{Key: key, HasParameterList: true}
+	for index := range values {
+		t.Parameters = append(t.Parameters, &parser.GdValue{Integer: &values[index]})
+	}
+	return t
+}
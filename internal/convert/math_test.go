@@ -0,0 +1,111 @@
+package convert
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestConvertGdValueToMathTypes(t *testing.T) {
+	content := `vector2 = Vector2( 10, 20.5 )
+vector2i = Vector2i(1, 2)
+vector3 = Vector3(1, 2, 3)
+vector3i = Vector3i(1, 2, 3)
+vector4 = Vector4(1, 2, 3, 4)
+rect2 = Rect2( 0, 0, 896, 512 )
+rect2i = Rect2i(1, 2, 3, 4)
+transform2d = Transform2D( 1, 0, 0, 1, 10, 20 )
+basis = Basis(1, 2, 3, 4, 5, 6, 7, 8, 9)
+transform = Transform( 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12 )
+transform3d = Transform3D(1, 0, 0, 0, 1, 0, 0, 0, 1, 4, 5, 6)
+quat = Quat( 0, 0, 0, 1 )
+quaternion = Quaternion(0, 0, 0, 1)
+aabb = AABB(1, 2, 3, 4, 5, 6)
+plane = Plane(0, 1, 0, 2)
+color = Color( 1, 0.5, 0, 1 )
+projection = Projection(1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1)
+vector2_infinite = Vector2(inf_neg, inf)
+vector2_float_int = Vector2i(1.5, 2)
+vector2_wrong_count = Vector2(1, 2, 3)`
+	tscn, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	values := map[string]interface{}{}
+	for _, field := range tscn.Fields {
+		values[field.Key] = convertGdValue(field.Value)
+	}
+
+	expected := map[string]interface{}{
+		"vector2":     godot.Vector2{X: 10, Y: 20.5},
+		"vector2i":    godot.Vector2i{X: 1, Y: 2},
+		"vector3":     godot.Vector3{X: 1, Y: 2, Z: 3},
+		"vector3i":    godot.Vector3i{X: 1, Y: 2, Z: 3},
+		"vector4":     godot.Vector4{X: 1, Y: 2, Z: 3, W: 4},
+		"rect2":       godot.Rect2{Size: godot.Vector2{X: 896, Y: 512}},
+		"rect2i":      godot.Rect2i{Position: godot.Vector2i{X: 1, Y: 2}, Size: godot.Vector2i{X: 3, Y: 4}},
+		"transform2d": godot.Transform2D{X: godot.Vector2{X: 1}, Y: godot.Vector2{Y: 1}, Origin: godot.Vector2{X: 10, Y: 20}},
+		"basis": godot.Basis{
+			X: godot.Vector3{X: 1, Y: 4, Z: 7},
+			Y: godot.Vector3{X: 2, Y: 5, Z: 8},
+			Z: godot.Vector3{X: 3, Y: 6, Z: 9},
+		},
+		"transform": godot.Transform3D{
+			Basis: godot.Basis{
+				X: godot.Vector3{X: 1, Y: 4, Z: 7},
+				Y: godot.Vector3{X: 2, Y: 5, Z: 8},
+				Z: godot.Vector3{X: 3, Y: 6, Z: 9},
+			},
+			Origin: godot.Vector3{X: 10, Y: 11, Z: 12},
+		},
+		"transform3d":      godot.Transform3D{Basis: godot.BasisIdentity, Origin: godot.Vector3{X: 4, Y: 5, Z: 6}},
+		"quat":             godot.QuaternionIdentity,
+		"quaternion":       godot.QuaternionIdentity,
+		"aabb":             godot.AABB{Position: godot.Vector3{X: 1, Y: 2, Z: 3}, Size: godot.Vector3{X: 4, Y: 5, Z: 6}},
+		"plane":            godot.Plane{Normal: godot.Vector3{Y: 1}, D: 2},
+		"color":            godot.Color{R: 1, G: 0.5, A: 1},
+		"projection":       godot.ProjectionIdentity,
+		"vector2_infinite": godot.Vector2{X: math.Inf(-1), Y: math.Inf(1)},
+	}
+
+	for key, value := range expected {
+		assert.Equal(t, value, values[key].(godot.Value).Value, key)
+	}
+
+	// types with parameters that don't match are kept as they are
+	assert.IsType(t, godot.Type{}, values["vector2_float_int"])
+	assert.IsType(t, godot.Type{}, values["vector2_wrong_count"])
+}
+
+func TestFormatMathTypes(t *testing.T) {
+	transform := godot.Transform3D{Basis: godot.BasisIdentity, Origin: godot.Vector3{X: 1.5, Y: 2, Z: 3}}
+
+	godot3, err := FormatGodotValue(transform, parser.PrintOptions{Godot3: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "Transform( 1, 0, 0, 0, 1, 0, 0, 0, 1, 1.5, 2, 3 )", godot3)
+
+	godot4, err := FormatGodotValue(transform, parser.PrintOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Transform3D(1, 0, 0, 0, 1, 0, 0, 0, 1, 1.5, 2, 3)", godot4)
+
+	basis := godot.Basis{X: godot.Vector3{X: 1, Y: 4, Z: 7}, Y: godot.Vector3{X: 2, Y: 5, Z: 8}, Z: godot.Vector3{X: 3, Y: 6, Z: 9}}
+	formatted, err := FormatGodotValue(basis, parser.PrintOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Basis(1, 2, 3, 4, 5, 6, 7, 8, 9)", formatted)
+
+	formatted, err = FormatGodotValue(godot.Quaternion{W: 1}, parser.PrintOptions{Godot3: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "Quat( 0, 0, 0, 1 )", formatted)
+
+	formatted, err = FormatGodotValue(godot.Vector2i{X: 3, Y: -4}, parser.PrintOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Vector2i(3, -4)", formatted)
+
+	formatted, err = FormatGodotValue(godot.ParseNodePath("../Sprite:frame"), parser.PrintOptions{Godot3: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `NodePath("../Sprite:frame")`, formatted)
+}
@@ -1,10 +1,17 @@
 package convert
 
 import (
+	"sort"
+
+	"github.com/pkg/errors"
+
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
 	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
 )
 
+// projectConfigVersionGodot4 is the config_version of project.godot files written by Godot 4
+const projectConfigVersionGodot4 = 5
+
 // ToGodotProject tries to convert a TscnFile structure to a godot.Project
 func ToGodotProject(tscn *parser.TscnFile) (*godot.Project, error) {
 	project := &godot.Project{
@@ -35,30 +42,8 @@ func ToGodotProject(tscn *parser.TscnFile) (*godot.Project, error) {
 
 	insertFieldEntriesFromSection(&parser.GdResource{Fields: tscn.Fields}, project.Fields)
 
-	sectionMap := map[string]map[string]interface{}{
-		"android":     project.Android,
-		"audio":       project.Audio,
-		"application": project.Application,
-		"autoload":    project.Autoload,
-		"compression": project.Compression,
-		"display":     project.Display,
-		"editor":      project.Editor,
-		"filesystem":  project.Filesystem,
-		"gui":         project.GUI,
-		"input":       project.Input,
-		"layernames":  project.LayerNames,
-		"locale":      project.Locale,
-		"logging":     project.Logging,
-		"memory":      project.Memory,
-		"network":     project.Network,
-		"node":        project.Node,
-		"rendering":   project.Rendering,
-		"world":       project.World,
-	}
-
 	for _, section := range tscn.Sections {
-		m, ok := sectionMap[section.ResourceType]
-		if ok {
+		if m := project.Section(section.ResourceType); m != nil {
 			insertFieldEntriesFromSection(section, m)
 			continue
 		}
@@ -68,3 +53,64 @@ func ToGodotProject(tscn *parser.TscnFile) (*godot.Project, error) {
 
 	return project, nil
 }
+
+// FromGodotProject converts a godot.Project back into a TscnFile structure and returns the formatting matching the
+// Godot version which wrote the project
+func FromGodotProject(project *godot.Project) (*parser.TscnFile, parser.PrintOptions, error) {
+	fields, err := convertFieldsToGdFields(project.Fields)
+	if err != nil {
+		return nil, parser.PrintOptions{}, errors.Wrap(err, "could not convert project")
+	}
+
+	tscn := &parser.TscnFile{Fields: fields}
+
+	sections := make(map[string]map[string]interface{})
+	for name, section := range project.Rest {
+		sections[name] = section
+	}
+	for _, name := range godot.ProjectSections {
+		if section := project.Section(name); len(section) > 0 {
+			sections[name] = section
+		}
+	}
+
+	gdSections, err := convertSectionMapToSections(sections)
+	if err != nil {
+		return nil, parser.PrintOptions{}, errors.Wrap(err, "could not convert project")
+	}
+	tscn.Sections = gdSections
+
+	options := printOptionsForFormat(projectFormatOf(project))
+	options.ConfigFile = true
+	return tscn, options, nil
+}
+
+// projectFormatOf returns the format version matching the config_version of the project
+func projectFormatOf(project *godot.Project) int64 {
+	if configVersion, ok := project.Fields["config_version"].(godot.Value); ok {
+		if v, ok := configVersion.Value.(int64); ok && v >= projectConfigVersionGodot4 {
+			return godot.FormatVersionGodot4
+		}
+	}
+	return godot.FormatVersionGodot3
+}
+
+// convertSectionMapToSections converts named field maps into sections, sorted by their name like Godot does
+func convertSectionMapToSections(sections map[string]map[string]interface{}) ([]*parser.GdResource, error) {
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gdSections := make([]*parser.GdResource, 0, len(names))
+	for _, name := range names {
+		fields, err := convertFieldsToGdFields(sections[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not convert section %s", name)
+		}
+		gdSections = append(gdSections, &parser.GdResource{ResourceType: name, Fields: fields})
+	}
+
+	return gdSections, nil
+}
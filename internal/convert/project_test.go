@@ -37,3 +37,34 @@ customfield=1337`
 	customFieldValue := customField.(godot.Value)
 	assert.Equal(t, int64(1337), customFieldValue.Value)
 }
+
+func TestFromGodotProject(t *testing.T) {
+	content := `config_version=4
+[rendering]
+environment/default_environment="res://default_env.tres"
+[application]
+config/name="Your first Godot Game"
+run/main_scene="res://World.tscn"
+[layer_names]
+2d_physics/layer_1="World"`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	project, err := ToGodotProject(tscnFile)
+	assert.NoError(t, err)
+
+	tscnFile, options, err := FromGodotProject(project)
+	assert.NoError(t, err)
+	assert.True(t, options.ConfigFile)
+	assert.True(t, options.Godot3)
+
+	assert.Equal(t, "config_version", tscnFile.Fields[0].Key)
+
+	var sections []string
+	for _, section := range tscnFile.Sections {
+		sections = append(sections, section.ResourceType)
+	}
+	assert.Equal(t, []string{"application", "layer_names", "rendering"}, sections)
+	assert.Equal(t, "config/name", tscnFile.Sections[0].Fields[0].Key)
+	assert.Equal(t, "run/main_scene", tscnFile.Sections[0].Fields[1].Key)
+}
@@ -6,18 +6,31 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
 )
 
 // ToGodotResource tries to convert a TscnFile structure to a .tres file
 func ToGodotResource(tscn *parser.TscnFile) (*godot.Resource, error) {
+	return toGodotResource(tscn, reporter{})
+}
+
+// ToGodotResourcePartially converts as much of the TscnFile as possible, broken sections are skipped and added to
+// the diagnostics
+func ToGodotResourcePartially(tscn *parser.TscnFile, diagnostics *diagnostic.List) (*godot.Resource, error) {
+	return toGodotResource(tscn, reporter{diagnostics: diagnostics})
+}
+
+func toGodotResource(tscn *parser.TscnFile, r reporter) (*godot.Resource, error) {
 	if tscn.Key != TscnTypeGodotResource {
 		return nil, fmt.Errorf("can't convert %s to gd_resource", tscn.Key)
 	}
 
 	res := &godot.Resource{
-		ExtResources: make(map[int64]*godot.ExtResource),
-		SubResources: make(map[int64]*godot.SubResource),
+		Format:       formatVersionOf(tscn),
+		UID:          uidOf(tscn),
+		ExtResources: make(map[string]*godot.ExtResource),
+		SubResources: make(map[string]*godot.SubResource),
 		Fields:       make(map[string]interface{}),
 		MetaData: godot.MetaData{
 			LexerPosition: tscn.Pos,
@@ -26,10 +39,10 @@ func ToGodotResource(tscn *parser.TscnFile) (*godot.Resource, error) {
 
 	t, err := tscn.GetAttribute("type")
 	if err != nil {
-		return nil, errors.Wrap(err, "gd_resource doesn't contain required attribute type")
+		return nil, &diagnostic.MissingAttributeError{Location: headerLocation(tscn, tscn.Pos), Attribute: "type"}
 	}
 	if t.String == nil {
-		return nil, errors.New("gd_resource attribute type must be a string")
+		return nil, invalidValue(headerLocation(tscn, t.Pos), "type", t, "gd_resource attribute type must be a string")
 	}
 
 	res.Type = *t.String
@@ -38,21 +51,27 @@ func ToGodotResource(tscn *parser.TscnFile) (*godot.Resource, error) {
 	for _, section := range tscn.Sections {
 		// External resources
 		if section.ResourceType == parser.ResourceTypeExtResource {
-			r, err := convertSectionToExtResource(section)
+			extResource, err := convertSectionToExtResource(section)
 			if err != nil {
-				return nil, err
+				if err := r.report(err, section.Pos); err != nil {
+					return nil, err
+				}
+				continue
 			}
-			res.ExtResources[r.ID] = r
+			res.ExtResources[extResource.ID] = extResource
 			continue
 		}
 
 		// Internal resources
 		if section.ResourceType == parser.ResourceTypeSubResource {
-			r, err := convertSectionToSubResource(section)
+			subResource, err := convertSectionToSubResource(section)
 			if err != nil {
-				return nil, err
+				if err := r.report(err, section.Pos); err != nil {
+					return nil, err
+				}
+				continue
 			}
-			res.SubResources[r.ID] = r
+			res.SubResources[subResource.ID] = subResource
 			continue
 		}
 
@@ -65,8 +84,51 @@ func ToGodotResource(tscn *parser.TscnFile) (*godot.Resource, error) {
 		}
 
 		// something else found? Whoops, throw error
-		return nil, fmt.Errorf("invalid resource type found: %s [%s]", section.ResourceType, section.Pos)
+		err := &diagnostic.UnknownSectionError{Location: section.Location(section.Pos)}
+		if err := r.report(err, section.Pos); err != nil {
+			return nil, err
+		}
 	}
 
 	return res, nil
 }
+
+// FromGodotResource converts a godot.Resource back into a TscnFile structure and returns the formatting matching its
+// format
+func FromGodotResource(res *godot.Resource) (*parser.TscnFile, parser.PrintOptions, error) {
+	format := res.Format
+	if format == 0 {
+		format = godot.FormatVersionGodot4
+	}
+
+	tscn := &parser.TscnFile{
+		Key: TscnTypeGodotResource,
+		Attributes: append(
+			[]*parser.GdField{newStringGdField("type", res.Type)},
+			newHeaderAttributes(len(res.ExtResources)+len(res.SubResources), format, res.UID)...,
+		),
+	}
+
+	extResources, err := convertExtResourcesToSections(res.ExtResources, format)
+	if err != nil {
+		return nil, parser.PrintOptions{}, err
+	}
+	tscn.Sections = append(tscn.Sections, extResources...)
+
+	subResources, err := convertSubResourcesToSections(res.SubResources, format)
+	if err != nil {
+		return nil, parser.PrintOptions{}, err
+	}
+	tscn.Sections = append(tscn.Sections, subResources...)
+
+	fields, err := convertFieldsToGdFields(res.Fields)
+	if err != nil {
+		return nil, parser.PrintOptions{}, errors.Wrap(err, "could not convert resource")
+	}
+	tscn.Sections = append(tscn.Sections, &parser.GdResource{
+		ResourceType: parser.ResourceTypeResource,
+		Fields:       fields,
+	})
+
+	return tscn, printOptionsForFormat(format), nil
+}
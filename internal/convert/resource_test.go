@@ -25,8 +25,9 @@ background_sky = SubResource( 1 )`
 	assert.NoError(t, err)
 
 	assert.Equal(t, "Environment", res.Type)
+	assert.Equal(t, int64(godot.FormatVersionGodot3), res.Format)
 	assert.Len(t, res.SubResources, 1)
-	assert.Equal(t, "ProceduralSky", res.SubResources[1].Type)
+	assert.Equal(t, "ProceduralSky", res.SubResources["1"].Type)
 	assert.Len(t, res.Fields, 2)
 
 	backgroundMode := res.Fields["background_mode"].(godot.Value)
@@ -55,3 +56,31 @@ func TestConvertToGodotResourceWithSomeInvalidValues(t *testing.T) {
 	_, err = ToGodotResource(tscnFile)
 	assert.Error(t, err)
 }
+
+func TestFromGodotResource(t *testing.T) {
+	content := `[gd_resource type="Environment" load_steps=2 format=3 uid="uid://b5k2j0ffbn8sx"]
+[sub_resource type="Sky" id="Sky_x3m1c"]
+[resource]
+background_mode = 2
+sky = SubResource("Sky_x3m1c")`
+	tscn, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	res, err := ToGodotResource(tscn)
+	assert.NoError(t, err)
+
+	tscn, options, err := FromGodotResource(res)
+	assert.NoError(t, err)
+	assert.False(t, options.Godot3)
+
+	assert.Equal(t, TscnTypeGodotResource, tscn.Key)
+	uid, err := tscn.GetAttribute("uid")
+	assert.NoError(t, err)
+	assert.Equal(t, "uid://b5k2j0ffbn8sx", *uid.String)
+
+	assert.Len(t, tscn.Sections, 2)
+	resource := tscn.Sections[1]
+	assert.Equal(t, parser.ResourceTypeResource, resource.ResourceType)
+	assert.Equal(t, "background_mode", resource.Fields[0].Key)
+	assert.Equal(t, "sky", resource.Fields[1].Key)
+}
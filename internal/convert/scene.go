@@ -3,11 +3,13 @@ package convert
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
 
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
 )
 
@@ -23,13 +25,25 @@ const (
 
 // ToGodotScene tries to convert a TscnFile structure to an actual Godot Scene with a node tree
 func ToGodotScene(tscn *parser.TscnFile) (*godot.Scene, error) {
+	return toGodotScene(tscn, reporter{})
+}
+
+// ToGodotScenePartially converts as much of the TscnFile as possible, broken sections are skipped and added to the
+// diagnostics. The node tree is nil if it couldn't be built.
+func ToGodotScenePartially(tscn *parser.TscnFile, diagnostics *diagnostic.List) (*godot.Scene, error) {
+	return toGodotScene(tscn, reporter{diagnostics: diagnostics})
+}
+
+func toGodotScene(tscn *parser.TscnFile, r reporter) (*godot.Scene, error) {
 	if tscn.Key != TscnTypeGodotScene {
 		return nil, fmt.Errorf("can't convert %s to gd_scene", tscn.Key)
 	}
 
 	scene := &godot.Scene{
-		ExtResources: make(map[int64]*godot.ExtResource),
-		SubResources: make(map[int64]*godot.SubResource),
+		Format:       formatVersionOf(tscn),
+		UID:          uidOf(tscn),
+		ExtResources: make(map[string]*godot.ExtResource),
+		SubResources: make(map[string]*godot.SubResource),
 		MetaData: godot.MetaData{
 			LexerPosition: tscn.Pos,
 		},
@@ -41,7 +55,10 @@ func ToGodotScene(tscn *parser.TscnFile) (*godot.Scene, error) {
 		if section.ResourceType == parser.ResourceTypeExtResource {
 			res, err := convertSectionToExtResource(section)
 			if err != nil {
-				return nil, err
+				if err := r.report(err, section.Pos); err != nil {
+					return nil, err
+				}
+				continue
 			}
 			scene.ExtResources[res.ID] = res
 			continue
@@ -51,7 +68,10 @@ func ToGodotScene(tscn *parser.TscnFile) (*godot.Scene, error) {
 		if section.ResourceType == parser.ResourceTypeSubResource {
 			res, err := convertSectionToSubResource(section)
 			if err != nil {
-				return nil, err
+				if err := r.report(err, section.Pos); err != nil {
+					return nil, err
+				}
+				continue
 			}
 			scene.SubResources[res.ID] = res
 			continue
@@ -61,7 +81,10 @@ func ToGodotScene(tscn *parser.TscnFile) (*godot.Scene, error) {
 		if section.ResourceType == parser.ResourceTypeEditable {
 			editable, err := convertSectionToEditable(section)
 			if err != nil {
-				return nil, err
+				if err := r.report(err, section.Pos); err != nil {
+					return nil, err
+				}
+				continue
 			}
 			scene.Editables = append(scene.Editables, editable)
 			continue
@@ -71,7 +94,10 @@ func ToGodotScene(tscn *parser.TscnFile) (*godot.Scene, error) {
 		if section.ResourceType == parser.ResourceTypeConnection {
 			connection, err := convertSectionToConnection(section)
 			if err != nil {
-				return nil, err
+				if err := r.report(err, section.Pos); err != nil {
+					return nil, err
+				}
+				continue
 			}
 			scene.Connections = append(scene.Connections, connection)
 			continue
@@ -83,17 +109,24 @@ func ToGodotScene(tscn *parser.TscnFile) (*godot.Scene, error) {
 		}
 
 		// something else found? Whoops, throw error
-		return nil, fmt.Errorf("invalid resource type found: %s [%s]", section.ResourceType, section.Pos)
+		err := &diagnostic.UnknownSectionError{Location: section.Location(section.Pos)}
+		if err := r.report(err, section.Pos); err != nil {
+			return nil, err
+		}
 	}
 
 	rootNode, err := buildNodeTree(tscn)
 	if err != nil {
-		return nil, err
+		if err := r.report(err, tscn.Pos); err != nil {
+			return nil, err
+		}
+		// without a node tree there is nothing left to convert
+		return scene, nil
 	}
 
 	scene.Node = rootNode
 
-	err = postProcessAndCleanSceneFromInternals(scene)
+	err = postProcessAndCleanSceneFromInternals(scene, r)
 	if err != nil {
 		return nil, err
 	}
@@ -101,8 +134,8 @@ func ToGodotScene(tscn *parser.TscnFile) (*godot.Scene, error) {
 	return scene, nil
 }
 
-func postProcessAndCleanSceneFromInternals(scene *godot.Scene) error {
-	err := createVolatileNodes(scene)
+func postProcessAndCleanSceneFromInternals(scene *godot.Scene, r reporter) error {
+	err := createVolatileNodes(scene, r)
 	if err != nil {
 		return err
 	}
@@ -112,8 +145,21 @@ func postProcessAndCleanSceneFromInternals(scene *godot.Scene) error {
 		return err
 	}
 
-	if len(unassignableNodes.Children) > 0 {
-		return fmt.Errorf("node tree contains an invalid tree")
+	for _, node := range unassignableNodes.Children() {
+		parent, _ := node.Fields[internalNodeParentPathField].(string)
+		err := &diagnostic.InvalidTreeError{
+			Location: diagnostic.Location{
+				Position:        node.LexerPosition,
+				Section:         parser.ResourceTypeNode,
+				SectionPosition: node.LexerPosition,
+			},
+			Node:   node.Name,
+			Parent: parent,
+			Msg:    fmt.Sprintf("could not find parent %s of node %s", parent, node.Name),
+		}
+		if err := r.report(err, node.LexerPosition); err != nil {
+			return err
+		}
 	}
 
 	err = scene.RemoveNode(internalNodeUnassignableNodes)
@@ -124,76 +170,74 @@ func postProcessAndCleanSceneFromInternals(scene *godot.Scene) error {
 	return nil
 }
 
-func createVolatileNodes(scene *godot.Scene) error {
+func createVolatileNodes(scene *godot.Scene, r reporter) error {
 	unassignableNodes, err := scene.GetNode(internalNodeUnassignableNodes)
 	if err != nil {
 		return err
 	}
 
-	var nodesToBeDeleted []string
-
-	// convert map into slice
-	children := convertNodeMapIntoSortedSlice(unassignableNodes.Children)
+	children := sortNodesByParentPathLength(unassignableNodes.Children())
 
 	// create the volatile node structure
 	for _, node := range children {
-		nodeIdent := node.Name
 		parentPath, ok := node.Fields[internalNodeParentPathField]
 		if !ok {
 			continue
 		}
 
 		p := parentPath.(string)
-		for _, editable := range scene.Editables {
-			// doesn't match the editable, ignore
-			if !strings.HasPrefix(p, editable.Path) {
-				continue
-			}
-
-			// for every path segment check if a child node exists with the given name
-			parts := strings.Split(p, "/")
-			parentNode := scene.Node
-			for _, pathPart := range parts {
-				// parent node has the path part? Dig deeper
-				if n, ok := parentNode.Children[pathPart]; ok {
-					parentNode = n
-					continue
-				}
-
-				// if not, create a volatile node here
-				volatileNode := &godot.Node{
-					Name:     pathPart,
-					Type:     volatileNodeType,
-					Children: make(map[string]*godot.Node),
-				}
-				parentNode.AddNode(volatileNode)
-				parentNode = volatileNode
-			}
+		// nodes of inherited scenes can be children of any node of the base scene
+		if scene.IsInherited() || matchesEditable(p, scene.Editables) {
+			createVolatileNodePath(scene.Node, p)
 		}
 
-		// try to add the un-assignable node into the tree
+		// try to add the un-assignable node into the tree, this detaches it from the un-assignable nodes
 		parentNode, err := scene.GetNode(p)
 		if err != nil {
-			return err
+			// the node stays un-assignable and is reported later on
+			continue
 		}
-		parentNode.AddNode(node)
 		delete(node.Fields, internalNodeParentPathField)
-		nodesToBeDeleted = append(nodesToBeDeleted, nodeIdent)
-	}
-
-	for _, nodeIdent := range nodesToBeDeleted {
-		delete(unassignableNodes.Children, nodeIdent)
+		if err := addNodeAtInheritedIndex(parentNode, node); err != nil {
+			if err := r.report(err, node.LexerPosition); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
-func convertNodeMapIntoSortedSlice(nodeMap map[string]*godot.Node) []*godot.Node {
-	var children []*godot.Node
-	for _, node := range nodeMap {
-		children = append(children, node)
+func matchesEditable(path string, editables []*godot.Editable) bool {
+	for _, editable := range editables {
+		if strings.HasPrefix(path, editable.Path) {
+			return true
+		}
 	}
+	return false
+}
 
+// createVolatileNodePath creates volatile nodes for every segment of the path which doesn't exist yet
+func createVolatileNodePath(root *godot.Node, path string) {
+	parentNode := root
+	for _, pathPart := range strings.Split(path, "/") {
+		// parent node has the path part? Dig deeper
+		if n, ok := parentNode.Child(pathPart); ok {
+			parentNode = n
+			continue
+		}
+
+		// if not, create a volatile node here
+		volatileNode := &godot.Node{
+			Name: pathPart,
+			Type: volatileNodeType,
+		}
+		parentNode.AddNode(volatileNode)
+		parentNode = volatileNode
+	}
+}
+
+func sortNodesByParentPathLength(children []*godot.Node) []*godot.Node {
 	parentPathLen := func(n *godot.Node) int {
 		path := n.Fields[internalNodeParentPathField]
 		p := path.(string)
@@ -213,38 +257,35 @@ func convertSectionToExtResource(section *parser.GdResource) (*godot.ExtResource
 		return nil, fmt.Errorf("you can't convert a %s to ext_resource", section.ResourceType)
 	}
 
-	path, err := section.GetAttribute("path")
+	path, err := requiredStringAttribute(section, "path")
 	if err != nil {
-		return nil, errors.Wrap(
-			err,
-			"could not convert, because ext_resource doesn't have required field path",
-		)
+		return nil, err
 	}
 
-	resType, err := section.GetAttribute("type")
+	resType, err := requiredStringAttribute(section, "type")
 	if err != nil {
-		return nil, errors.Wrap(
-			err,
-			"could not convert, because ext_resource doesn't have required field type",
-		)
+		return nil, err
 	}
 
-	id, err := section.GetAttribute("id")
+	resID, err := requiredIDAttribute(section)
 	if err != nil {
-		return nil, errors.Wrap(
-			err,
-			"could not convert, because ext_resource doesn't have required field id",
-		)
+		return nil, err
 	}
 
-	return &godot.ExtResource{
-		Path: *path.String,
-		Type: *resType.String,
-		ID:   *id.Integer,
+	res := &godot.ExtResource{
+		Path: path,
+		Type: resType,
+		ID:   resID,
 		MetaData: godot.MetaData{
 			LexerPosition: section.Pos,
 		},
-	}, nil
+	}
+
+	if uid, err := section.GetAttribute("uid"); err == nil && uid.String != nil {
+		res.UID = *uid.String
+	}
+
+	return res, nil
 }
 
 func convertSectionToSubResource(section *parser.GdResource) (*godot.SubResource, error) {
@@ -252,25 +293,19 @@ func convertSectionToSubResource(section *parser.GdResource) (*godot.SubResource
 		return nil, fmt.Errorf("you can't convert a %s to sub_resource", section.ResourceType)
 	}
 
-	resType, err := section.GetAttribute("type")
+	resType, err := requiredStringAttribute(section, "type")
 	if err != nil {
-		return nil, errors.Wrap(
-			err,
-			"could not convert, because sub_resource doesn't have required field type",
-		)
+		return nil, err
 	}
 
-	id, err := section.GetAttribute("id")
+	resID, err := requiredIDAttribute(section)
 	if err != nil {
-		return nil, errors.Wrap(
-			err,
-			"could not convert, because sub_resource doesn't have required field id",
-		)
+		return nil, err
 	}
 
 	subResource := godot.SubResource{
-		Type:   *resType.String,
-		ID:     *id.Integer,
+		Type:   resType,
+		ID:     resID,
 		Fields: make(map[string]interface{}),
 		MetaData: godot.MetaData{
 			LexerPosition: section.Pos,
@@ -284,6 +319,12 @@ func convertSectionToSubResource(section *parser.GdResource) (*godot.SubResource
 
 func buildNodeTree(tscn *parser.TscnFile) (*godot.Node, error) {
 	root, otherNodes := findNodes(tscn)
+	if root == nil {
+		return nil, &diagnostic.InvalidTreeError{
+			Location: headerLocation(tscn, tscn.Pos),
+			Msg:      "scene doesn't contain a root node",
+		}
+	}
 
 	rootNode, err := convertSectionToUnattachedNode(root)
 	if err != nil {
@@ -292,10 +333,9 @@ func buildNodeTree(tscn *parser.TscnFile) (*godot.Node, error) {
 
 	// add a node to allow us to gather un-assignable nodes, they might have volatile ancestors
 	unassignableNodeList := &godot.Node{
-		Name:     internalNodeUnassignableNodes,
-		Type:     internalNodeType,
-		Fields:   make(map[string]interface{}),
-		Children: make(map[string]*godot.Node),
+		Name:   internalNodeUnassignableNodes,
+		Type:   internalNodeType,
+		Fields: make(map[string]interface{}),
 	}
 	rootNode.AddNode(unassignableNodeList)
 
@@ -313,7 +353,9 @@ func buildNodeTree(tscn *parser.TscnFile) (*godot.Node, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "could not parse node")
 		}
-		parent.AddNode(node)
+		if err := addNodeAtInheritedIndex(parent, node); err != nil {
+			return nil, err
+		}
 		processedNodes = append(processedNodes, index)
 		return node, nil
 	}
@@ -329,7 +371,12 @@ func buildNodeTree(tscn *parser.TscnFile) (*godot.Node, error) {
 			parentAttribute, _ := sectionNode.GetAttribute("parent")
 			parentNodePath, ok := parentAttribute.Raw().(string)
 			if !ok {
-				return nil, fmt.Errorf("section attribute parent is not a string: %v", parentAttribute.Raw())
+				return nil, invalidValue(
+					sectionNode.Location(parentAttribute.Pos),
+					"parent",
+					parentAttribute,
+					"node attribute parent must be a string",
+				)
 			}
 
 			parentNode, err := rootNode.GetNode(parentNodePath)
@@ -368,9 +415,11 @@ func findNodes(tscn *parser.TscnFile) (rootNode *parser.GdResource, nodes []*par
 		}
 
 		parent, _ := section.GetAttribute("parent")
-		// node without parent field is the root node
+		// node without parent field is the root node, further root nodes are reported by the validation
 		if parent == nil {
-			rootNode = section
+			if rootNode == nil {
+				rootNode = section
+			}
 			continue
 		}
 
@@ -389,15 +438,14 @@ func convertSectionToUnattachedNode(section *parser.GdResource) (*godot.Node, er
 		return nil, fmt.Errorf("you can't convert a %s to node", section.ResourceType)
 	}
 
-	name, err := section.GetAttribute("name")
+	name, err := requiredStringAttribute(section, "name")
 	if err != nil {
-		return nil, errors.Wrap(err, "node without name")
+		return nil, err
 	}
 
 	node := godot.Node{
-		Name:     *name.String,
-		Fields:   make(map[string]interface{}),
-		Children: make(map[string]*godot.Node),
+		Name:   name,
+		Fields: make(map[string]interface{}),
 		MetaData: godot.MetaData{
 			LexerPosition: section.Pos,
 		},
@@ -408,20 +456,66 @@ func convertSectionToUnattachedNode(section *parser.GdResource) (*godot.Node, er
 		return nil, err
 	}
 
+	if groups, err := section.GetAttribute("groups"); err == nil {
+		for _, group := range groups.Array {
+			if group.String == nil {
+				return nil, invalidValue(section.Location(group.Pos), "groups", group, "node groups must be strings")
+			}
+			node.Groups = append(node.Groups, *group.String)
+		}
+	}
+
+	if index, err := section.GetAttribute("index"); err == nil {
+		i, err := convertIndexAttribute(section, index)
+		if err != nil {
+			return nil, err
+		}
+		node.InheritedIndex = &i
+	}
+
 	insertFieldEntriesFromSection(section, node.Fields)
 
 	return &node, nil
 }
 
-func attachTypeToNode(node *godot.Node, section *parser.GdResource) error {
-	if nodeType, err := section.GetAttribute("type"); err == nil {
-		node.Type = *nodeType.String
+// convertIndexAttribute converts the index attribute of a node, Godot writes it as string (index="0")
+func convertIndexAttribute(section *parser.GdResource, value *parser.GdValue) (int, error) {
+	switch {
+	case value.Integer != nil:
+		return int(*value.Integer), nil
+	case value.String != nil:
+		index, err := strconv.Atoi(*value.String)
+		if err == nil {
+			return index, nil
+		}
+	}
+	return 0, invalidValue(section.Location(value.Pos), "index", value, "node index is not a number")
+}
+
+// addNodeAtInheritedIndex adds the node as last child of the parent, or at its index if the node has one
+func addNodeAtInheritedIndex(parent, node *godot.Node) error {
+	parent.AddNode(node)
+	if node.InheritedIndex == nil || *node.InheritedIndex >= parent.ChildCount() {
 		return nil
 	}
+	return parent.MoveChild(node, *node.InheritedIndex)
+}
+
+func attachTypeToNode(node *godot.Node, section *parser.GdResource) error {
+	if _, err := section.GetAttribute("type"); err == nil {
+		node.Type, err = requiredStringAttribute(section, "type")
+		return err
+	}
 
 	if instance, err := section.GetAttribute("instance"); err == nil {
-		if len(instance.Type.Parameters) != 1 {
-			return fmt.Errorf("node instance parameter does not contain a valid reference %v", instance.Raw())
+		if instance.Type == nil || len(instance.Type.Parameters) != 1 {
+			return invalidValue(
+				section.Location(instance.Pos),
+				"instance",
+				instance,
+				"node instance parameter does not contain a valid reference %s",
+				instance.ToString(),
+			)
 		}
 
 		node.Instance = convertGdValue(instance).(godot.Type)
@@ -437,13 +531,13 @@ func convertSectionToEditable(section *parser.GdResource) (*godot.Editable, erro
 		return nil, fmt.Errorf("you can't convert a %s to editable", section.ResourceType)
 	}
 
-	path, err := section.GetAttribute("path")
+	path, err := requiredStringAttribute(section, "path")
 	if err != nil {
-		return nil, errors.Wrap(err, "editable without path")
+		return nil, err
 	}
 
 	editable := godot.Editable{
-		Path: *path.String,
+		Path: path,
 		MetaData: godot.MetaData{
 			LexerPosition: section.Pos,
 		},
@@ -457,31 +551,31 @@ func convertSectionToConnection(section *parser.GdResource) (*godot.Connection,
 		return nil, fmt.Errorf("you can't convert a %s to connection", section.ResourceType)
 	}
 
-	from, err := section.GetAttribute("from")
+	from, err := requiredStringAttribute(section, "from")
 	if err != nil {
-		return nil, errors.Wrap(err, "editable without from")
+		return nil, err
 	}
 
-	to, err := section.GetAttribute("to")
+	to, err := requiredStringAttribute(section, "to")
 	if err != nil {
-		return nil, errors.Wrap(err, "editable without to")
+		return nil, err
 	}
 
-	signal, err := section.GetAttribute("signal")
+	signal, err := requiredStringAttribute(section, "signal")
 	if err != nil {
-		return nil, errors.Wrap(err, "editable without signal")
+		return nil, err
 	}
 
-	method, err := section.GetAttribute("method")
+	method, err := requiredStringAttribute(section, "method")
 	if err != nil {
-		return nil, errors.Wrap(err, "editable without method")
+		return nil, err
 	}
 
 	conn := godot.Connection{
-		From:   *from.String,
-		To:     *to.String,
-		Signal: *signal.String,
-		Method: *method.String,
+		From:   from,
+		To:     to,
+		Signal: signal,
+		Method: method,
 		MetaData: godot.MetaData{
 			LexerPosition: section.Pos,
 		},
@@ -498,3 +592,323 @@ func convertSectionToConnection(section *parser.GdResource) (*godot.Connection,
 
 	return &conn, nil
 }
+
+// FromGodotScene converts a godot.Scene back into a TscnFile structure and returns the formatting matching its format
+func FromGodotScene(scene *godot.Scene) (*parser.TscnFile, parser.PrintOptions, error) {
+	format := scene.Format
+	if format == 0 {
+		format = godot.FormatVersionGodot4
+	}
+
+	tscn := &parser.TscnFile{
+		Key:        TscnTypeGodotScene,
+		Attributes: newHeaderAttributes(len(scene.ExtResources)+len(scene.SubResources), format, scene.UID),
+	}
+
+	extResources, err := convertExtResourcesToSections(scene.ExtResources, format)
+	if err != nil {
+		return nil, parser.PrintOptions{}, err
+	}
+	tscn.Sections = append(tscn.Sections, extResources...)
+
+	subResources, err := convertSubResourcesToSections(scene.SubResources, format)
+	if err != nil {
+		return nil, parser.PrintOptions{}, err
+	}
+	tscn.Sections = append(tscn.Sections, subResources...)
+
+	if scene.Node != nil {
+		nodes, err := convertNodeTreeToSections(scene.Node)
+		if err != nil {
+			return nil, parser.PrintOptions{}, err
+		}
+		tscn.Sections = append(tscn.Sections, nodes...)
+	}
+
+	for _, conn := range scene.Connections {
+		section, err := convertConnectionToSection(conn)
+		if err != nil {
+			return nil, parser.PrintOptions{}, err
+		}
+		tscn.Sections = append(tscn.Sections, section)
+	}
+
+	for _, editable := range scene.Editables {
+		tscn.Sections = append(tscn.Sections, &parser.GdResource{
+			ResourceType: parser.ResourceTypeEditable,
+			Attributes:   []*parser.GdField{newStringGdField("path", editable.Path)},
+		})
+	}
+
+	return tscn, printOptionsForFormat(format), nil
+}
+
+func convertExtResourcesToSections(
+	extResources map[string]*godot.ExtResource,
+	format int64,
+) ([]*parser.GdResource, error) {
+	resources := make([]*godot.ExtResource, 0, len(extResources))
+	for _, res := range extResources {
+		resources = append(resources, res)
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return lessByPosition(resources[i].LexerPosition, resources[j].LexerPosition, resources[i].ID, resources[j].ID)
+	})
+
+	sections := make([]*parser.GdResource, 0, len(resources))
+	for _, res := range resources {
+		id, err := convertIDToGdValue(res.ID, format)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not convert ext_resource")
+		}
+
+		section := &parser.GdResource{ResourceType: parser.ResourceTypeExtResource}
+
+		// Godot 4 changed the order of the attributes
+		if format >= godot.FormatVersionGodot4 {
+			section.Attributes = append(section.Attributes, newStringGdField("type", res.Type))
+			if res.UID != "" {
+				section.Attributes = append(section.Attributes, newStringGdField("uid", res.UID))
+			}
+			section.Attributes = append(section.Attributes, newStringGdField("path", res.Path))
+		} else {
+			section.Attributes = append(
+				section.Attributes,
+				newStringGdField("path", res.Path),
+				newStringGdField("type", res.Type),
+			)
+		}
+
+		section.Attributes = append(section.Attributes, &parser.GdField{Key: "id", Value: id})
+		sections = append(sections, section)
+	}
+
+	return sections, nil
+}
+
+func convertSubResourcesToSections(
+	subResources map[string]*godot.SubResource,
+	format int64,
+) ([]*parser.GdResource, error) {
+	var sections []*parser.GdResource
+	for _, res := range sortSubResourcesByDependencies(subResources) {
+		id, err := convertIDToGdValue(res.ID, format)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not convert sub_resource")
+		}
+
+		fields, err := convertFieldsToGdFields(res.Fields)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not convert sub_resource %s", res.ID)
+		}
+
+		sections = append(sections, &parser.GdResource{
+			ResourceType: parser.ResourceTypeSubResource,
+			Attributes: []*parser.GdField{
+				newStringGdField("type", res.Type),
+				{Key: "id", Value: id},
+			},
+			Fields: fields,
+		})
+	}
+
+	return sections, nil
+}
+
+// sortSubResourcesByDependencies orders sub resources like they were in the source file, but makes sure every sub
+// resource is placed after the sub resources it references since Godot can only reference already loaded ones
+func sortSubResourcesByDependencies(subResources map[string]*godot.SubResource) []*godot.SubResource {
+	resources := make([]*godot.SubResource, 0, len(subResources))
+	for _, res := range subResources {
+		resources = append(resources, res)
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return lessByPosition(resources[i].LexerPosition, resources[j].LexerPosition, resources[i].ID, resources[j].ID)
+	})
+
+	visited := make(map[string]bool)
+	sorted := make([]*godot.SubResource, 0, len(resources))
+
+	var visit func(res *godot.SubResource)
+	visit = func(res *godot.SubResource) {
+		if visited[res.ID] {
+			return
+		}
+		visited[res.ID] = true
+
+		for _, key := range sortedFieldKeys(res.Fields) {
+			for _, id := range findResourceReferences(res.Fields[key], "SubResource") {
+				if dependency, ok := subResources[id]; ok {
+					visit(dependency)
+				}
+			}
+		}
+
+		sorted = append(sorted, res)
+	}
+
+	for _, res := range resources {
+		visit(res)
+	}
+
+	return sorted
+}
+
+// findResourceReferences returns the ids of all references like SubResource(id) within a value
+func findResourceReferences(value interface{}, identifier string) []string {
+	var ids []string
+
+	switch v := value.(type) {
+	case godot.Value:
+		return findResourceReferences(v.Value, identifier)
+	case godot.KeyValuePair:
+		return findResourceReferences(v.Value, identifier)
+	case godot.Type:
+		if v.Identifier == identifier && len(v.Parameters) == 1 {
+			if id, ok := resourceIDOf(v.Parameters[0]); ok {
+				ids = append(ids, id)
+			}
+		}
+		for _, param := range v.Parameters {
+			ids = append(ids, findResourceReferences(param, identifier)...)
+		}
+	case godot.Object:
+		for _, key := range sortedFieldKeys(v.Properties) {
+			ids = append(ids, findResourceReferences(v.Properties[key], identifier)...)
+		}
+	case []interface{}:
+		for _, elem := range v {
+			ids = append(ids, findResourceReferences(elem, identifier)...)
+		}
+	case map[string]interface{}:
+		for _, key := range sortedFieldKeys(v) {
+			ids = append(ids, findResourceReferences(v[key], identifier)...)
+		}
+	}
+
+	return ids
+}
+
+// resourceIDOf returns the id of a reference parameter, Godot 3 uses integer ids while Godot 4 uses strings
+func resourceIDOf(param interface{}) (string, bool) {
+	if v, ok := param.(godot.Value); ok {
+		param = v.Value
+	}
+
+	switch id := param.(type) {
+	case int64:
+		return strconv.FormatInt(id, 10), true
+	case string:
+		return id, true
+	default:
+		return "", false
+	}
+}
+
+func convertNodeTreeToSections(root *godot.Node) ([]*parser.GdResource, error) {
+	var sections []*parser.GdResource
+
+	var walk func(node *godot.Node, parentPath string) error
+	walk = func(node *godot.Node, parentPath string) error {
+		path := node.Name
+		if parentPath == "" {
+			path = "."
+		} else if parentPath != "." {
+			path = parentPath + "/" + node.Name
+		}
+
+		// volatile nodes are just placeholders for nodes of instanced scenes, only their children are stored
+		if node.Type != volatileNodeType {
+			section, err := convertNodeToSection(node, parentPath)
+			if err != nil {
+				return err
+			}
+			sections = append(sections, section)
+		}
+
+		for _, child := range node.Children() {
+			if err := walk(child, path); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(root, ""); err != nil {
+		return nil, err
+	}
+
+	return sections, nil
+}
+
+func convertNodeToSection(node *godot.Node, parentPath string) (*parser.GdResource, error) {
+	section := &parser.GdResource{
+		ResourceType: parser.ResourceTypeNode,
+		Attributes:   []*parser.GdField{newStringGdField("name", node.Name)},
+	}
+
+	if node.Type != "" {
+		section.Attributes = append(section.Attributes, newStringGdField("type", node.Type))
+	}
+
+	if parentPath != "" {
+		section.Attributes = append(section.Attributes, newStringGdField("parent", parentPath))
+	}
+
+	if node.InheritedIndex != nil {
+		section.Attributes = append(section.Attributes, newStringGdField("index", strconv.Itoa(*node.InheritedIndex)))
+	}
+
+	if node.Instance.Identifier != "" {
+		instance, err := convertToGdValue(node.Instance)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not convert instance of node %s", node.Name)
+		}
+		section.Attributes = append(section.Attributes, &parser.GdField{Key: "instance", Value: instance})
+	}
+
+	if len(node.Groups) > 0 {
+		groups, err := convertToGdValue(node.Groups)
+		if err != nil {
+			return nil, err
+		}
+		section.Attributes = append(section.Attributes, &parser.GdField{Key: "groups", Value: groups})
+	}
+
+	fields, err := convertFieldsToGdFields(node.Fields)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not convert node %s", node.Name)
+	}
+	section.Fields = fields
+
+	return section, nil
+}
+
+func convertConnectionToSection(conn *godot.Connection) (*parser.GdResource, error) {
+	section := &parser.GdResource{
+		ResourceType: parser.ResourceTypeConnection,
+		Attributes: []*parser.GdField{
+			newStringGdField("signal", conn.Signal),
+			newStringGdField("from", conn.From),
+			newStringGdField("to", conn.To),
+			newStringGdField("method", conn.Method),
+		},
+	}
+
+	if conn.Flags != 0 {
+		section.Attributes = append(section.Attributes, newIntegerGdField("flags", conn.Flags))
+	}
+
+	if conn.Binds.Value != nil {
+		binds, err := convertToGdValue(conn.Binds)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not convert binds of connection %s", conn.Signal)
+		}
+		section.Attributes = append(section.Attributes, &parser.GdField{Key: "binds", Value: binds})
+	}
+
+	return section, nil
+}
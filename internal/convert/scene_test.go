@@ -4,9 +4,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
 )
 
 func TestConvertToGodotScene(t *testing.T) {
@@ -46,6 +49,87 @@ position = Vector2( 811.747, -209.178 )
 	assert.Equal(t, "Hazards", node.Name)
 }
 
+func TestConvertToGodotSceneWithFormat3(t *testing.T) {
+	content := `[gd_scene load_steps=3 format=3 uid="uid://cecaux1sm7mo0"]
+[ext_resource type="Script" path="res://player.gd" id="1_x7k2p"]
+[ext_resource type="PackedScene" uid="uid://dh1x0t8pnwr3l" path="res://hitbox.tscn" id="2_kq1ra"]
+
+[sub_resource type="CapsuleShape2D" id="CapsuleShape2D_8ukcg"]
+radius = 4.0
+
+[node name="Player" type="CharacterBody2D"]
+script = ExtResource("1_x7k2p")
+tags = Array[StringName]([&"hero"])
+
+[node name="CollisionShape2D" type="CollisionShape2D" parent="."]
+shape = SubResource("CapsuleShape2D_8ukcg")
+
+[node name="Hitbox" parent="." instance=ExtResource("2_kq1ra")]`
+
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	scene, err := ToGodotScene(tscnFile)
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(godot.FormatVersionGodot4), scene.Format)
+	assert.Equal(t, "uid://cecaux1sm7mo0", scene.UID)
+
+	assert.Len(t, scene.ExtResources, 2)
+	assert.Equal(t, "res://player.gd", scene.ExtResources["1_x7k2p"].Path)
+	assert.Equal(t, "uid://dh1x0t8pnwr3l", scene.ExtResources["2_kq1ra"].UID)
+	assert.Equal(t, "CapsuleShape2D", scene.SubResources["CapsuleShape2D_8ukcg"].Type)
+
+	hitbox, err := scene.GetNode("Hitbox")
+	assert.NoError(t, err)
+	assert.Equal(t, "ExtResource", hitbox.Instance.Identifier)
+
+	tags := scene.Fields["tags"].(godot.Type)
+	assert.Equal(t, "Array", tags.Identifier)
+	assert.Equal(t, "StringName", tags.TypeParameters[0].Identifier)
+	hero := tags.Parameters[0].(godot.Value).Value.([]interface{})[0].(godot.Value)
+	assert.Equal(t, godot.StringName("hero"), hero.Value)
+}
+
+func TestConvertToGodotSceneKeepsNodeOrder(t *testing.T) {
+	content := `[gd_scene format=2]
+[ext_resource path="res://Base.tscn" type="PackedScene" id=1]
+
+[node name="Root" instance=ExtResource( 1 )]
+
+[node name="Zebra" type="Node2D" parent="."]
+
+[node name="Apple" type="Node2D" parent="."]
+
+[node name="Mango" type="Node2D" parent="."]
+
+[node name="Banana" type="Node2D" parent="." index="0"]`
+
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	scene, err := ToGodotScene(tscnFile)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, child := range scene.Node.Children() {
+		names = append(names, child.Name)
+	}
+	assert.Equal(t, []string{"Banana", "Zebra", "Apple", "Mango"}, names)
+
+	banana, err := scene.GetNode("Banana")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *banana.InheritedIndex)
+	assert.Equal(t, 0, banana.Index())
+
+	out, _, err := FromGodotScene(scene)
+	assert.NoError(t, err)
+	assert.Equal(t, "Banana", *out.Sections[2].Attributes[0].Value.String)
+	index, err := out.Sections[2].GetAttribute("index")
+	assert.NoError(t, err)
+	assert.Equal(t, "0", *index.String)
+}
+
 func TestConvertToGodotSceneWithResource(t *testing.T) {
 	content := `[gd_resource]`
 	tscnFile, err := parser.Parse(strings.NewReader(content))
@@ -115,6 +199,138 @@ func TestConvertToGodotSceneWithInvalidNodeTree(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestConvertToGodotScenePartially(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=2]
+[ext_resource path="res://Player.gd" type="Script" id=1]
+[ext_resource path="res://Broken.gd" type="Script"]
+[connection]
+[this_does_not_exist]
+[node name="Root" type="Node2D"]
+script = ExtResource( 1 )
+[node name="Child" type="Node2D" parent="."]
+[node name="Orphan" type="Node2D" parent="Missing"]`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	var diagnostics diagnostic.List
+	scene, err := ToGodotScenePartially(tscnFile, &diagnostics)
+	assert.NoError(t, err)
+	assert.Len(t, scene.ExtResources, 1)
+	assert.Empty(t, scene.Connections)
+	assert.Equal(t, 1, scene.ChildCount())
+
+	var lines []int
+	for _, d := range diagnostics {
+		assert.Equal(t, ConversionRuleID, d.RuleID)
+		lines = append(lines, d.Position.Line)
+	}
+	assert.Equal(t, []int{3, 4, 5, 9}, lines)
+	assert.Equal(t, "could not find parent Missing of node Orphan", diagnostics[3].Message)
+}
+
+func TestConvertToGodotScenePartiallyWithInvalidNodeTree(t *testing.T) {
+	content := `[gd_scene]
+[ext_resource path="res://Player.gd" type="Script" id=1]
+[node parent="." type="Node2D"]`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	var diagnostics diagnostic.List
+	scene, err := ToGodotScenePartially(tscnFile, &diagnostics)
+	assert.NoError(t, err)
+	assert.Nil(t, scene.Node)
+	assert.Len(t, scene.ExtResources, 1)
+	assert.Len(t, diagnostics, 1)
+}
+
+func TestFromGodotScene(t *testing.T) {
+	content := `[gd_scene load_steps=4 format=2]
+[ext_resource path="res://World/tile_set.svg" type="Texture" id=2]
+[ext_resource path="res://World/Hazard.tscn" type="PackedScene" id=1]
+[sub_resource type="ConvexPolygonShape2D" id=1]
+points = PoolVector2Array( 16, 64, 128, 64 )
+[node name="RootNode" type="Node2D"]
+[node name="Hazards" type="Area2D" parent="." groups=["hazards"]]
+[node name="TrapFloorSpikes" parent="Hazards" instance=ExtResource( 1 )]
+position = Vector2( 687.645, -209.178 )
+[node name="Sprite" parent="Hazards/TrapFloorSpikes/A"]
+visible = false
+[editable path="Hazards/TrapFloorSpikes"]
+[connection signal="area_entered" from="Hazards" to="." method="_on_Hazards_area_entered" binds=[ 1 ]]`
+
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	scene, err := ToGodotScene(tscnFile)
+	assert.NoError(t, err)
+
+	tscnFile, options, err := FromGodotScene(scene)
+	assert.NoError(t, err)
+	assert.True(t, options.Godot3)
+
+	var types []string
+	for _, section := range tscnFile.Sections {
+		types = append(types, section.ResourceType)
+	}
+	assert.Equal(t, []string{
+		"ext_resource", "ext_resource", "sub_resource", "node", "node", "node", "node", "connection", "editable",
+	}, types)
+
+	loadSteps, err := tscnFile.GetAttribute("load_steps")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), *loadSteps.Integer)
+
+	// ext resources keep the order of the source file
+	id, _ := tscnFile.Sections[0].GetAttribute("id")
+	assert.Equal(t, int64(2), *id.Integer)
+
+	// the volatile node A is not written, but its children are
+	parent, err := tscnFile.Sections[6].GetAttribute("parent")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hazards/TrapFloorSpikes/A", *parent.String)
+
+	groups, err := tscnFile.Sections[4].GetAttribute("groups")
+	assert.NoError(t, err)
+	assert.Equal(t, "hazards", *groups.Array[0].String)
+}
+
+func TestFromGodotSceneOrdersSubResourcesByDependencies(t *testing.T) {
+	scene := &godot.Scene{
+		Format: godot.FormatVersionGodot4,
+		SubResources: map[string]*godot.SubResource{
+			"AnimationLibrary_1": {
+				Type: "AnimationLibrary",
+				ID:   "AnimationLibrary_1",
+				Fields: map[string]interface{}{
+					"_data": map[string]interface{}{
+						"attack": godot.Type{Identifier: "SubResource", Parameters: []interface{}{"Animation_2"}},
+					},
+				},
+			},
+			"Animation_2": {Type: "Animation", ID: "Animation_2"},
+		},
+	}
+
+	tscnFile, _, err := FromGodotScene(scene)
+	assert.NoError(t, err)
+	assert.Len(t, tscnFile.Sections, 2)
+
+	first, _ := tscnFile.Sections[0].GetAttribute("id")
+	assert.Equal(t, "Animation_2", *first.String)
+}
+
+func TestFromGodotSceneWithInvalidID(t *testing.T) {
+	scene := &godot.Scene{
+		Format: godot.FormatVersionGodot3,
+		ExtResources: map[string]*godot.ExtResource{
+			"1_x7k2p": {Path: "res://player.gd", Type: "Script", ID: "1_x7k2p"},
+		},
+	}
+
+	_, _, err := FromGodotScene(scene)
+	assert.Error(t, err)
+}
+
 func testErrorWithConvertSection(
 	t *testing.T,
 	content string,
@@ -257,7 +473,7 @@ func TestBuildNodeTreeWithInvalidTree(t *testing.T) {
 	unassignableNodes, err := tree.GetNode(internalNodeUnassignableNodes)
 	assert.NoError(t, err)
 
-	assert.NotEmpty(t, unassignableNodes.Children, "tree should contain nodes that we can't assign")
+	assert.NotEmpty(t, unassignableNodes.Children(), "tree should contain nodes that we can't assign")
 }
 
 func TestBuildNodeTreeWithInvalidParentParameter(t *testing.T) {
@@ -282,6 +498,52 @@ func TestBuildNodeTreeWithInvalidChildNode(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestConvertToGodotSceneReturnsMissingAttributeError(t *testing.T) {
+	content := `[gd_scene load_steps=2]
+[ext_resource type="Texture" id=1]
+[node name="Root" type="Node2D"]`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	_, err = ToGodotScene(tscnFile)
+
+	var missing *diagnostic.MissingAttributeError
+	assert.True(t, errors.As(err, &missing))
+	assert.Equal(t, "path", missing.Attribute)
+	assert.Equal(t, "ext_resource", missing.Section)
+	assert.Equal(t, 2, missing.Position.Line)
+}
+
+func TestConvertToGodotSceneReturnsInvalidTreeError(t *testing.T) {
+	content := `[gd_scene]
+[node name="Root" type="Node2D"]
+[node name="Orphan" type="Node2D" parent="Missing"]`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	_, err = ToGodotScene(tscnFile)
+
+	var treeError *diagnostic.InvalidTreeError
+	assert.True(t, errors.As(err, &treeError))
+	assert.Equal(t, "Orphan", treeError.Node)
+	assert.Equal(t, "Missing", treeError.Parent)
+	assert.Equal(t, 3, treeError.Position.Line)
+}
+
+func TestConvertToGodotSceneWithNonStringAttribute(t *testing.T) {
+	content := `[gd_scene]
+[node name="Root" type=12]`
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	_, err = ToGodotScene(tscnFile)
+
+	var invalid *diagnostic.InvalidValueError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "type", invalid.Key)
+	assert.Equal(t, "node attribute type must be a string", invalid.Message())
+}
+
 func TestRegressionConvertToGdSceneWithEditableNodeWithMissingChildren(t *testing.T) {
 	content := `[gd_scene]
 [ext_resource path="res://TestNode.tscn" type="PackedScene" id=3]
@@ -333,3 +595,29 @@ position = Vector2(13, 37)
 	assert.Equal(t, "ChildNodeWeAreOverwriting", node.Name)
 	assert.Len(t, node.Fields, 1)
 }
+
+func TestRegressionConvertToGdSceneInheritedSceneWithoutEditables(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://UI/BaseDialog.tscn" type="PackedScene" id=1]
+
+[node name="QuitDialog" instance=ExtResource( 1 )]
+
+[node name="Title" parent="Panel/Header" index="0"]
+text = "Quit?"`
+
+	tscnFile, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	scene, err := ToGodotScene(tscnFile)
+	assert.NoError(t, err)
+	assert.True(t, scene.IsInherited())
+
+	header, err := scene.GetNode("Panel/Header")
+	assert.NoError(t, err)
+	assert.Equal(t, volatileNodeType, header.Type)
+
+	title, err := scene.GetNode("Panel/Header/Title")
+	assert.NoError(t, err)
+	assert.Equal(t, "Panel/Header/Title", title.Path())
+}
@@ -0,0 +1,372 @@
+package convert
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+const (
+	// structTagName is the name of the struct tag used by UnmarshalFields and MarshalFields, e.g. `godot:"position"`
+	structTagName = "godot"
+	// structTagOmitEmpty skips zero values when marshalling, e.g. `godot:"position,omitempty"`
+	structTagOmitEmpty = "omitempty"
+)
+
+var (
+	referenceType = reflect.TypeOf(godot.Reference{})
+	nodePathType  = reflect.TypeOf(godot.NodePath{})
+)
+
+// UnmarshalFields decodes the fields of a node or resource into the struct v points to. Struct fields are matched by
+// their godot tag or by their name in snake case, fields missing in the map are left untouched.
+func UnmarshalFields(fields map[string]interface{}, v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("can only unmarshal into a pointer to a struct, got %T", v)
+	}
+
+	return unmarshalStruct(fields, target.Elem())
+}
+
+func unmarshalStruct(fields map[string]interface{}, target reflect.Value) error {
+	targetType := target.Type()
+
+	for index := 0; index < targetType.NumField(); index++ {
+		structField := targetType.Field(index)
+		key, _, ok := structFieldKey(structField)
+		if !ok {
+			continue
+		}
+
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+
+		if err := unmarshalValue(value, target.Field(index), key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// structFieldKey returns the field key of a struct field and whether it should be omitted when empty, returns false
+// for unexported or ignored fields
+func structFieldKey(field reflect.StructField) (string, bool, bool) {
+	if field.PkgPath != "" {
+		return "", false, false
+	}
+
+	tag := field.Tag.Get(structTagName)
+	if tag == "-" {
+		return "", false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	key := parts[0]
+	if key == "" {
+		key = toSnakeCase(field.Name)
+	}
+
+	omitEmpty := false
+	for _, option := range parts[1:] {
+		if option == structTagOmitEmpty {
+			omitEmpty = true
+		}
+	}
+
+	return key, omitEmpty, true
+}
+
+// toSnakeCase converts a Go identifier like CollisionLayer into the Godot property name collision_layer
+func toSnakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for index, r := range runes {
+		if unicode.IsUpper(r) && index > 0 {
+			previousIsLower := unicode.IsLower(runes[index-1]) || unicode.IsDigit(runes[index-1])
+			nextIsLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+			if previousIsLower || (nextIsLower && unicode.IsUpper(runes[index-1])) {
+				sb.WriteRune('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// unwrapValue removes the godot.Value wrapper and returns the value with its position
+func unwrapValue(value interface{}) (interface{}, lexer.Position) {
+	if v, ok := value.(godot.Value); ok {
+		return v.Value, v.LexerPosition
+	}
+	return value, positionOf(value)
+}
+
+func unmarshalValue(value interface{}, target reflect.Value, key string) error {
+	raw, pos := unwrapValue(value)
+
+	unmarshalError := func() error {
+		return decodeError(key, pos, "can't unmarshal %s of type %T into %s", key, raw, target.Type())
+	}
+
+	if target.Kind() == reflect.Ptr {
+		if raw == nil {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return unmarshalValue(value, target.Elem(), key)
+	}
+
+	if target.Type() == referenceType {
+		ref, ok := ReferenceOf(raw)
+		if !ok {
+			return unmarshalError()
+		}
+		ref.LexerPosition = pos
+		target.Set(reflect.ValueOf(ref))
+		return nil
+	}
+
+	if target.Type() == nodePathType {
+		path, ok := godot.NodePathOf(raw)
+		if !ok {
+			return unmarshalError()
+		}
+		target.Set(reflect.ValueOf(path))
+		return nil
+	}
+
+	if raw != nil && target.Kind() != reflect.Interface && reflect.TypeOf(raw).AssignableTo(target.Type()) {
+		target.Set(reflect.ValueOf(raw))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Interface:
+		if raw == nil {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		if !reflect.TypeOf(raw).AssignableTo(target.Type()) {
+			return unmarshalError()
+		}
+		target.Set(reflect.ValueOf(raw))
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return unmarshalError()
+		}
+		target.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := raw.(int64)
+		if !ok || target.OverflowInt(i) {
+			return unmarshalError()
+		}
+		target.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, ok := raw.(int64)
+		if !ok || i < 0 || target.OverflowUint(uint64(i)) {
+			return unmarshalError()
+		}
+		target.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		switch f := raw.(type) {
+		case float64:
+			target.SetFloat(f)
+		case int64:
+			target.SetFloat(float64(f))
+		default:
+			return unmarshalError()
+		}
+	case reflect.String:
+		switch s := raw.(type) {
+		case string:
+			target.SetString(s)
+		case godot.StringName:
+			target.SetString(string(s))
+		default:
+			return unmarshalError()
+		}
+	case reflect.Slice, reflect.Array:
+		return unmarshalSlice(raw, pos, target, key, unmarshalError)
+	case reflect.Map:
+		m, ok := collectionOf(raw).(map[string]interface{})
+		if !ok || target.Type().Key().Kind() != reflect.String {
+			return unmarshalError()
+		}
+		result := reflect.MakeMapWithSize(target.Type(), len(m))
+		for mapKey, mapValue := range m {
+			elem := reflect.New(target.Type().Elem()).Elem()
+			if err := unmarshalValue(mapValue, elem, key+"."+mapKey); err != nil {
+				return err
+			}
+			result.SetMapIndex(reflect.ValueOf(mapKey).Convert(target.Type().Key()), elem)
+		}
+		target.Set(result)
+	case reflect.Struct:
+		m, ok := collectionOf(raw).(map[string]interface{})
+		if !ok {
+			return unmarshalError()
+		}
+		return unmarshalStruct(m, target)
+	default:
+		return unmarshalError()
+	}
+
+	return nil
+}
+
+// unmarshalSlice unmarshals an array into a slice or an array of the same length
+func unmarshalSlice(
+	raw interface{},
+	pos lexer.Position,
+	target reflect.Value,
+	key string,
+	unmarshalError func() error,
+) error {
+	elements, ok := collectionOf(raw).([]interface{})
+	if !ok {
+		return unmarshalError()
+	}
+
+	// packed arrays like PoolVector2Array( 1, 2, 3, 4 ) store the components of their elements one after another
+	if t, isType := raw.(godot.Type); isType && isPackedArray(t.Identifier) {
+		grouped, ok := groupPackedArrayComponents(elements, target.Type().Elem())
+		if !ok {
+			return unmarshalError()
+		}
+		elements = grouped
+	}
+
+	var result reflect.Value
+	if target.Kind() == reflect.Array {
+		if len(elements) != target.Len() {
+			return decodeError(key, pos, "can't unmarshal %d elements into %s", len(elements), target.Type())
+		}
+		result = reflect.New(target.Type()).Elem()
+	} else {
+		result = reflect.MakeSlice(target.Type(), len(elements), len(elements))
+	}
+
+	for index, elem := range elements {
+		if err := unmarshalValue(elem, result.Index(index), fmt.Sprintf("%s[%d]", key, index)); err != nil {
+			return err
+		}
+	}
+	target.Set(result)
+	return nil
+}
+
+// collectionOf returns the array or dictionary of typed collections like Array[int]([1, 2]), packed arrays and fields
+// expanded by godot.ExpandFields, the properties of objects are returned as dictionary
+func collectionOf(raw interface{}) interface{} {
+	switch v := raw.(type) {
+	case godot.FieldGroup:
+		return map[string]interface{}(v)
+	case godot.FieldList:
+		return []interface{}(v)
+	case godot.Object:
+		return v.Properties
+	}
+
+	t, ok := raw.(godot.Type)
+	if !ok {
+		return raw
+	}
+
+	if isPackedArray(t.Identifier) {
+		return t.Parameters
+	}
+
+	if (t.Identifier == "Array" || t.Identifier == "Dictionary") && len(t.Parameters) == 1 {
+		collection, _ := unwrapValue(t.Parameters[0])
+		return collection
+	}
+
+	return raw
+}
+
+func isPackedArray(identifier string) bool {
+	return (strings.HasPrefix(identifier, "Pool") || strings.HasPrefix(identifier, "Packed")) &&
+		strings.HasSuffix(identifier, "Array")
+}
+
+// groupPackedArrayComponents groups the components of packed vectors and colors into their math types
+func groupPackedArrayComponents(components []interface{}, elemType reflect.Type) ([]interface{}, bool) {
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	var size int
+	var create func(f []float64) interface{}
+
+	switch elemType {
+	case reflect.TypeOf(godot.Vector2{}):
+		size = 2
+		create = func(f []float64) interface{} { return godot.Vector2{X: f[0], Y: f[1]} }
+	case reflect.TypeOf(godot.Vector3{}):
+		size = 3
+		create = func(f []float64) interface{} { return godot.Vector3{X: f[0], Y: f[1], Z: f[2]} }
+	case reflect.TypeOf(godot.Vector4{}):
+		size = 4
+		create = func(f []float64) interface{} { return godot.Vector4{X: f[0], Y: f[1], Z: f[2], W: f[3]} }
+	case reflect.TypeOf(godot.Color{}):
+		size = 4
+		create = func(f []float64) interface{} { return godot.Color{R: f[0], G: f[1], B: f[2], A: f[3]} }
+	default:
+		return components, true
+	}
+
+	if len(components)%size != 0 {
+		return nil, false
+	}
+
+	var grouped []interface{}
+	for start := 0; start < len(components); start += size {
+		f := make([]float64, size)
+		for offset := range f {
+			switch c := unwrapComponent(components[start+offset]).(type) {
+			case float64:
+				f[offset] = c
+			case int64:
+				f[offset] = float64(c)
+			default:
+				return nil, false
+			}
+		}
+		grouped = append(grouped, create(f))
+	}
+	return grouped, true
+}
+
+func unwrapComponent(value interface{}) interface{} {
+	raw, _ := unwrapValue(value)
+	return raw
+}
+
+// ReferenceOf converts ExtResource( 1 ) and SubResource( 1 ) into a reference
+func ReferenceOf(value interface{}) (godot.Reference, bool) {
+	raw, _ := unwrapValue(value)
+	t, ok := raw.(godot.Type)
+	if !ok || len(t.Parameters) != 1 || (t.Identifier != "ExtResource" && t.Identifier != "SubResource") {
+		return godot.Reference{}, false
+	}
+
+	id, ok := resourceIDOf(t.Parameters[0])
+	if !ok {
+		return godot.Reference{}, false
+	}
+
+	return godot.Reference{External: t.Identifier == "ExtResource", ID: id}, true
+}
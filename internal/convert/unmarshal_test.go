@@ -0,0 +1,212 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+type testHitbox struct {
+	Shape godot.Reference
+	Size  *godot.Vector2 `godot:"extents"`
+}
+
+type testPlayer struct {
+	CollisionLayer uint32 `godot:"collision_layer"`
+	Speed          float64
+	Lives          int
+	Name           string
+	Tag            godot.StringName
+	Visible        bool
+	Position       godot.Vector2
+	Script         godot.Reference
+	Items          []string
+	Points         []godot.Vector2
+	Scores         map[string]int
+	Hitbox         testHitbox
+	Metadata       interface{}
+	Ignored        string `godot:"-"`
+	unexported     string
+}
+
+func parseFieldsForTest(t *testing.T, content string) map[string]interface{} {
+	tscn, err := parser.Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	fields := make(map[string]interface{})
+	insertFieldEntriesFromSection(&parser.GdResource{Fields: tscn.Fields}, fields)
+	return fields
+}
+
+func TestUnmarshalFields(t *testing.T) {
+	fields := parseFieldsForTest(t, `collision_layer = 5
+speed = 120
+lives = 3
+name = "Hero"
+tag = &"player"
+visible = false
+position = Vector2( 10, 20 )
+script = ExtResource( 1 )
+items = Array[String](["sword", "shield"])
+points = PoolVector2Array( 1, 2, 3, 4 )
+scores = {
+"level1": 10,
+"level2": 20
+}
+hitbox = {
+"shape": SubResource("CircleShape2D_x1"),
+"extents": Vector2(4, 8)
+}
+metadata = 1.5
+ignored = "nope"`)
+
+	player := testPlayer{Ignored: "keep"}
+	assert.NoError(t, UnmarshalFields(fields, &player))
+
+	assert.Equal(t, testPlayer{
+		CollisionLayer: 5,
+		Speed:          120,
+		Lives:          3,
+		Name:           "Hero",
+		Tag:            godot.StringName("player"),
+		Position:       godot.Vector2{X: 10, Y: 20},
+		Script:         godot.Reference{External: true, ID: "1", MetaData: player.Script.MetaData},
+		Items:          []string{"sword", "shield"},
+		Points:         []godot.Vector2{{X: 1, Y: 2}, {X: 3, Y: 4}},
+		Scores:         map[string]int{"level1": 10, "level2": 20},
+		Hitbox: testHitbox{
+			Shape: godot.Reference{ID: "CircleShape2D_x1", MetaData: player.Hitbox.Shape.MetaData},
+			Size:  &godot.Vector2{X: 4, Y: 8},
+		},
+		Metadata: 1.5,
+		Ignored:  "keep",
+	}, player)
+	assert.Equal(t, 8, player.Script.LexerPosition.Line)
+}
+
+func TestUnmarshalFieldsWithInvalidValue(t *testing.T) {
+	fields := parseFieldsForTest(t, `lives = 3
+speed = "fast"`)
+
+	var player testPlayer
+	err := UnmarshalFields(fields, &player)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "speed")
+	assert.Contains(t, err.Error(), "2:9")
+}
+
+func TestUnmarshalFieldsWithOverflow(t *testing.T) {
+	var target struct {
+		Value int8
+	}
+	assert.Error(t, UnmarshalFields(map[string]interface{}{"value": int64(300)}, &target))
+}
+
+func TestUnmarshalFieldsRequiresStructPointer(t *testing.T) {
+	var player testPlayer
+	assert.Error(t, UnmarshalFields(map[string]interface{}{}, player))
+	assert.Error(t, UnmarshalFields(map[string]interface{}{}, nil))
+}
+
+func TestUnmarshalExpandedFields(t *testing.T) {
+	fields := parseFieldsForTest(t, `length = 1.0
+tracks/0/type = "value"
+tracks/0/path = NodePath("Sprite:frame")
+tracks/0/keys = { "times": PoolRealArray( 0, 0.5 ) }
+tracks/1/type = "method"
+tracks/1/path = NodePath(".")`)
+
+	var animation struct {
+		Length float64
+		Tracks []struct {
+			Type string
+			Path godot.Type
+			Keys map[string]interface{}
+		}
+	}
+	assert.NoError(t, UnmarshalFields(godot.ExpandFields(fields), &animation))
+
+	assert.Equal(t, 1.0, animation.Length)
+	assert.Len(t, animation.Tracks, 2)
+	assert.Equal(t, "value", animation.Tracks[0].Type)
+	assert.Equal(t, "NodePath", animation.Tracks[0].Path.Identifier)
+	assert.Contains(t, animation.Tracks[0].Keys, "times")
+	assert.Equal(t, "method", animation.Tracks[1].Type)
+	assert.Nil(t, animation.Tracks[1].Keys)
+}
+
+func TestUnmarshalArray(t *testing.T) {
+	fields := parseFieldsForTest(t, `color = [ 1.0, 0.5, 0 ]
+size = PoolIntArray( 1, 2 )`)
+
+	var target struct {
+		Color [3]float64
+		Size  [2]int
+	}
+	assert.NoError(t, UnmarshalFields(fields, &target))
+	assert.Equal(t, [3]float64{1, 0.5, 0}, target.Color)
+	assert.Equal(t, [2]int{1, 2}, target.Size)
+
+	marshalled, err := MarshalFields(&target)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1.0, 0.5, 0.0}, marshalled["color"])
+
+	var tooShort struct {
+		Color [4]float64
+	}
+	err = UnmarshalFields(fields, &tooShort)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "can't unmarshal 3 elements into [4]float64")
+}
+
+func TestUnmarshalObject(t *testing.T) {
+	fields := parseFieldsForTest(t, `event = Object(InputEventKey,"device":0,"scancode":87)`)
+
+	var target struct {
+		Event godot.Object
+	}
+	assert.NoError(t, UnmarshalFields(fields, &target))
+	assert.Equal(t, "InputEventKey", target.Event.Class)
+
+	marshalled, err := MarshalFields(&target)
+	assert.NoError(t, err)
+
+	gdValue, err := convertToGdValue(marshalled["event"])
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`Object(InputEventKey,"device":0,"scancode":87)`,
+		parser.FormatValue(gdValue, parser.PrintOptions{}),
+	)
+}
+
+func TestUnmarshalNodePath(t *testing.T) {
+	fields := parseFieldsForTest(t, `target = NodePath("../Player:position:x")
+camera = "Camera2D"`)
+
+	var target struct {
+		Target godot.NodePath
+		Camera godot.NodePath
+	}
+	assert.NoError(t, UnmarshalFields(fields, &target))
+	assert.Equal(t, []string{"..", "Player"}, target.Target.Names)
+	assert.Equal(t, []string{"position", "x"}, target.Target.SubNames)
+	assert.Equal(t, []string{"Camera2D"}, target.Camera.Names)
+
+	marshalled, err := MarshalFields(&target)
+	assert.NoError(t, err)
+	assert.Equal(t, godot.ParseNodePath("../Player:position:x"), marshalled["target"])
+}
+
+func TestToSnakeCase(t *testing.T) {
+	assert.Equal(t, "collision_layer", toSnakeCase("CollisionLayer"))
+	assert.Equal(t, "speed", toSnakeCase("Speed"))
+	assert.Equal(t, "aabb", toSnakeCase("AABB"))
+	assert.Equal(t, "custom_aabb", toSnakeCase("CustomAABB"))
+	assert.Equal(t, "http_request", toSnakeCase("HTTPRequest"))
+	assert.Equal(t, "layer2_mask", toSnakeCase("Layer2Mask"))
+}
@@ -0,0 +1,490 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Document is a lossless concrete syntax tree of a TSCN file. Unlike TscnFile it keeps comments, whitespace and the
+// original spelling of all values, printing an unmodified Document reproduces its source byte by byte.
+type Document struct {
+	// Sections of the file, the first section has no header if the file starts with fields (e.g. project.godot)
+	Sections []*Section
+	// Trailing contains the whitespace and comments at the end of the file
+	Trailing string
+}
+
+// Section is a section of a Document, e.g. [node name="Player"] and its fields
+type Section struct {
+	// Leading contains the whitespace and comments in front of the section
+	Leading string
+	// HasHeader is false for the fields at the start of a file which don't belong to any section
+	HasHeader bool
+	// ResourceType is the identifier of the section header, e.g. node
+	ResourceType string
+	Attributes   []*Entry
+	// Closing contains the whitespace in front of the closing bracket of the header
+	Closing string
+	// Trailing contains a comment on the same line after the header
+	Trailing string
+	Fields   []*Entry
+	Pos      lexer.Position
+}
+
+// Entry is a key value pair of a Document, either a field or an attribute of a section header
+type Entry struct {
+	// Leading contains the whitespace and comments in front of the entry
+	Leading string
+	Key     string
+	// Separator is the assignment including its surrounding whitespace, e.g. " = "
+	Separator string
+	// RawValue is the value exactly as it was written in the source
+	RawValue string
+	// Trailing contains a comment on the same line after the value
+	Trailing string
+	Pos      lexer.Position
+}
+
+// ParseDocument parses the content into a lossless Document
+func ParseDocument(r io.Reader) (*Document, error) {
+	return ParseDocumentFile("", r)
+}
+
+// ParseDocumentFile works like ParseDocument, the filename is added to the positions of the document
+func ParseDocumentFile(filename string, r io.Reader) (*Document, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := lexTokens(filename, string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	p := documentParser{tokens: tokens}
+	return p.parse()
+}
+
+// String returns the source of the document
+func (doc *Document) String() string {
+	var sb strings.Builder
+	for _, section := range doc.Sections {
+		section.writeTo(&sb)
+	}
+	sb.WriteString(doc.Trailing)
+	return sb.String()
+}
+
+// WriteTo writes the source of the document to w
+func (doc *Document) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, doc.String())
+	return int64(n), err
+}
+
+// File parses the current state of the document into a TscnFile
+func (doc *Document) File() (*TscnFile, error) {
+	return Parse(strings.NewReader(doc.String()))
+}
+
+// Header returns the first section if it is the file descriptor (e.g. gd_scene), otherwise nil
+func (doc *Document) Header() *Section {
+	if len(doc.Sections) == 0 || !doc.Sections[0].HasHeader {
+		return nil
+	}
+	return doc.Sections[0]
+}
+
+// AddSection appends a new section with the given resource type at the end of the document
+func (doc *Document) AddSection(resourceType string) *Section {
+	section := &Section{HasHeader: true, ResourceType: resourceType}
+	if len(doc.Sections) > 0 {
+		section.Leading = "\n\n"
+	}
+	doc.Sections = append(doc.Sections, section)
+	return section
+}
+
+// RemoveSection removes a section including the comments in front of it, returns false if it isn't part of the document
+func (doc *Document) RemoveSection(section *Section) bool {
+	for index, s := range doc.Sections {
+		if s == section {
+			doc.Sections = append(doc.Sections[:index], doc.Sections[index+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (section *Section) writeTo(sb *strings.Builder) {
+	sb.WriteString(section.Leading)
+	if section.HasHeader {
+		sb.WriteString("[")
+		sb.WriteString(section.ResourceType)
+		for _, attr := range section.Attributes {
+			attr.writeTo(sb)
+		}
+		sb.WriteString(section.Closing)
+		sb.WriteString("]")
+		sb.WriteString(section.Trailing)
+	}
+	for _, field := range section.Fields {
+		field.writeTo(sb)
+	}
+}
+
+// Attribute returns the header attribute with the given key or nil
+func (section *Section) Attribute(key string) *Entry {
+	return findEntry(section.Attributes, key)
+}
+
+// Field returns the field with the given key or nil
+func (section *Section) Field(key string) *Entry {
+	return findEntry(section.Fields, key)
+}
+
+// SetAttribute changes the raw value of an attribute, or adds the attribute at the end of the header
+func (section *Section) SetAttribute(key, rawValue string) *Entry {
+	if attr := section.Attribute(key); attr != nil {
+		attr.RawValue = rawValue
+		return attr
+	}
+
+	attr := &Entry{Leading: " ", Key: key, Separator: "=", RawValue: rawValue}
+	section.Attributes = append(section.Attributes, attr)
+	return attr
+}
+
+// SetField changes the raw value of a field, or appends the field on a new line after the last field of the section.
+// The separator is only used for new fields.
+func (section *Section) SetField(key, rawValue, separator string) *Entry {
+	if field := section.Field(key); field != nil {
+		field.RawValue = rawValue
+		return field
+	}
+
+	field := &Entry{Leading: "\n", Key: key, Separator: separator, RawValue: rawValue}
+	if !section.HasHeader && len(section.Fields) == 0 {
+		field.Leading = ""
+	}
+	section.Fields = append(section.Fields, field)
+	return field
+}
+
+// RemoveAttribute removes an attribute from the header, returns false if it doesn't exist
+func (section *Section) RemoveAttribute(key string) bool {
+	attributes, removed := removeEntry(section.Attributes, key)
+	section.Attributes = attributes
+	return removed
+}
+
+// RemoveField removes a field including the comments in front of it, returns false if it doesn't exist
+func (section *Section) RemoveField(key string) bool {
+	fields, removed := removeEntry(section.Fields, key)
+	section.Fields = fields
+	return removed
+}
+
+func findEntry(entries []*Entry, key string) *Entry {
+	for _, entry := range entries {
+		if entry.Key == key {
+			return entry
+		}
+	}
+	return nil
+}
+
+func removeEntry(entries []*Entry, key string) ([]*Entry, bool) {
+	for index, entry := range entries {
+		if entry.Key == key {
+			return append(entries[:index], entries[index+1:]...), true
+		}
+	}
+	return entries, false
+}
+
+func (entry *Entry) writeTo(sb *strings.Builder) {
+	sb.WriteString(entry.Leading)
+	sb.WriteString(entry.Key)
+	sb.WriteString(entry.Separator)
+	sb.WriteString(entry.RawValue)
+	sb.WriteString(entry.Trailing)
+}
+
+// Value parses the raw value of the entry
+func (entry *Entry) Value() (*GdValue, error) {
+	tscn, err := Parse(strings.NewReader(fmt.Sprintf("value = %s", entry.RawValue)))
+	if err != nil {
+		return nil, syntaxError(entry.Pos, "invalid value of %s: %v", entry.Key, err)
+	}
+	if len(tscn.Fields) != 1 {
+		return nil, syntaxError(entry.Pos, "invalid value of %s", entry.Key)
+	}
+	return tscn.Fields[0].Value, nil
+}
+
+// documentLexer uses the same rules as tscnLexer but keeps comments and whitespace, which are elided by rules
+// starting with a lowercase letter
+var documentLexer = lexer.MustSimple(triviaPreservingRules(tscnRules))
+
+var tokenTypes = documentLexer.Symbols()
+
+func triviaPreservingRules(rules []lexer.Rule) []lexer.Rule {
+	result := make([]lexer.Rule, len(rules))
+	for index, rule := range rules {
+		rule.Name = strings.ToUpper(rule.Name[:1]) + rule.Name[1:]
+		result[index] = rule
+	}
+	return result
+}
+
+func lexTokens(filename, content string) ([]lexer.Token, error) {
+	lex, err := documentLexer.LexString(filename, content)
+	if err != nil {
+		return nil, toParseError(err)
+	}
+	tokens, err := lexer.ConsumeAll(lex)
+	if err != nil {
+		return nil, toParseError(err)
+	}
+	return tokens, nil
+}
+
+type documentParser struct {
+	tokens []lexer.Token
+	cursor int
+}
+
+func (p *documentParser) parse() (*Document, error) {
+	doc := &Document{}
+
+	for {
+		leading := p.trivia()
+		token := p.peek()
+
+		switch {
+		case token.EOF():
+			doc.Trailing = leading
+			return doc, nil
+		case p.is(token, "Punct", "["):
+			section, err := p.parseSectionHeader(leading)
+			if err != nil {
+				return nil, err
+			}
+			doc.Sections = append(doc.Sections, section)
+		default:
+			// fields in front of the first section header
+			if len(doc.Sections) == 0 {
+				doc.Sections = append(doc.Sections, &Section{Pos: token.Pos})
+			}
+			section := doc.Sections[len(doc.Sections)-1]
+
+			field, err := p.parseEntry(leading)
+			if err != nil {
+				return nil, err
+			}
+			section.Fields = append(section.Fields, field)
+		}
+	}
+}
+
+func (p *documentParser) parseSectionHeader(leading string) (*Section, error) {
+	open := p.next()
+	section := &Section{Leading: leading, HasHeader: true, Pos: open.Pos}
+
+	if trivia := p.trivia(); trivia != "" {
+		return nil, syntaxError(open.Pos, "unexpected whitespace in section header")
+	}
+
+	resourceType := p.next()
+	if resourceType.Type != tokenTypes["Ident"] {
+		return nil, syntaxError(resourceType.Pos, "expected section type but found %q", resourceType.Value)
+	}
+	section.ResourceType = resourceType.Value
+
+	for {
+		trivia := p.trivia()
+		token := p.peek()
+
+		if p.is(token, "Punct", "]") {
+			p.next()
+			section.Closing = trivia
+			section.Trailing = p.lineComment()
+			return section, nil
+		}
+
+		if token.EOF() {
+			return nil, syntaxError(section.Pos, "unexpected end of file in section header")
+		}
+
+		attr, err := p.parseEntry(trivia)
+		if err != nil {
+			return nil, err
+		}
+		section.Attributes = append(section.Attributes, attr)
+	}
+}
+
+func (p *documentParser) parseEntry(leading string) (*Entry, error) {
+	key := p.next()
+	if key.Type != tokenTypes["Ident"] {
+		return nil, syntaxError(key.Pos, "expected key but found %q", key.Value)
+	}
+
+	entry := &Entry{Leading: leading, Key: key.Value, Pos: key.Pos}
+
+	separator := p.trivia()
+	assign := p.next()
+	if !p.is(assign, "Punct", "=") {
+		return nil, syntaxError(assign.Pos, "expected = after %s but found %q", key.Value, assign.Value)
+	}
+	entry.Separator = separator + assign.Value + p.trivia()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	entry.RawValue = value
+	entry.Trailing = p.lineComment()
+
+	return entry, nil
+}
+
+// parseValue consumes a single value and returns its source
+func (p *documentParser) parseValue() (string, error) {
+	var sb strings.Builder
+
+	token := p.next()
+	sb.WriteString(token.Value)
+
+	switch {
+	case token.EOF():
+		return "", syntaxError(token.Pos, "unexpected end of file, expected value")
+	case p.is(token, "Punct", "{"), p.is(token, "Punct", "["), token.Type == tokenTypes["Generic"]:
+		group, err := p.parseGroup(token)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(group)
+		if token.Type == tokenTypes["Generic"] {
+			arguments, err := p.parseArguments()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(arguments)
+		}
+	case p.is(token, "Punct", "&"):
+		name := p.next()
+		if name.Type != tokenTypes["String"] {
+			return "", syntaxError(name.Pos, "expected string after & but found %q", name.Value)
+		}
+		sb.WriteString(name.Value)
+	case token.Type == tokenTypes["Ident"]:
+		arguments, err := p.parseArguments()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(arguments)
+	case token.Type == tokenTypes["Punct"]:
+		return "", syntaxError(token.Pos, "unexpected %q, expected value", token.Value)
+	}
+
+	return sb.String(), nil
+}
+
+// parseArguments consumes the optional argument list of a type like Vector2(1, 2)
+func (p *documentParser) parseArguments() (string, error) {
+	if !p.is(p.peek(), "Punct", "(") {
+		return "", nil
+	}
+	open := p.next()
+	group, err := p.parseGroup(open)
+	if err != nil {
+		return "", err
+	}
+	return open.Value + group, nil
+}
+
+// parseGroup consumes everything up to and including the bracket closing the already consumed opening bracket
+func (p *documentParser) parseGroup(open lexer.Token) (string, error) {
+	var sb strings.Builder
+	depth := 1
+
+	for depth > 0 {
+		token := p.next()
+		if token.EOF() {
+			return "", syntaxError(open.Pos, "unexpected end of file, unclosed %q", open.Value)
+		}
+
+		switch {
+		case token.Type == tokenTypes["Generic"]:
+			depth++
+		case token.Type == tokenTypes["Punct"] && strings.ContainsAny(token.Value, "[({"):
+			depth++
+		case token.Type == tokenTypes["Punct"] && strings.ContainsAny(token.Value, "])}"):
+			depth--
+		}
+
+		sb.WriteString(token.Value)
+	}
+
+	return sb.String(), nil
+}
+
+// trivia consumes whitespace and comments and returns their source
+func (p *documentParser) trivia() string {
+	var sb strings.Builder
+	for {
+		token := p.peek()
+		if token.Type != tokenTypes["Whitespace"] && token.Type != tokenTypes["Comment"] {
+			return sb.String()
+		}
+		sb.WriteString(token.Value)
+		p.cursor++
+	}
+}
+
+// lineComment consumes a comment on the current line including the whitespace in front of it
+func (p *documentParser) lineComment() string {
+	cursor := p.cursor
+	whitespace := ""
+
+	if token := p.peek(); token.Type == tokenTypes["Whitespace"] {
+		if strings.Contains(token.Value, "\n") {
+			return ""
+		}
+		whitespace = token.Value
+		p.cursor++
+	}
+
+	if token := p.peek(); token.Type == tokenTypes["Comment"] {
+		p.cursor++
+		return whitespace + token.Value
+	}
+
+	p.cursor = cursor
+	return ""
+}
+
+func (p *documentParser) peek() lexer.Token {
+	if p.cursor >= len(p.tokens) {
+		return lexer.EOFToken(lexer.Position{})
+	}
+	return p.tokens[p.cursor]
+}
+
+func (p *documentParser) next() lexer.Token {
+	token := p.peek()
+	if p.cursor < len(p.tokens) {
+		p.cursor++
+	}
+	return token
+}
+
+func (p *documentParser) is(token lexer.Token, tokenType, value string) bool {
+	return token.Type == tokenTypes[tokenType] && token.Value == value
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+)
+
+const documentContent = `; the player scene
+[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Player.gd" type="Script" id=1]
+
+[node name="Player" type="KinematicBody2D"]
+script = ExtResource( 1 )
+; movement speed in pixels
+speed   =   13.370 ; fast
+tags = [ "a",
+  "b" ]
+`
+
+func TestParseDocumentRoundTrip(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(documentContent))
+	assert.NoError(t, err)
+	assert.Equal(t, documentContent, doc.String())
+
+	assert.Len(t, doc.Sections, 3)
+	assert.Equal(t, "gd_scene", doc.Header().ResourceType)
+
+	node := doc.Sections[2]
+	assert.Equal(t, "node", node.ResourceType)
+	assert.Equal(t, `"Player"`, node.Attribute("name").RawValue)
+	assert.Equal(t, "13.370", node.Field("speed").RawValue)
+	assert.Equal(t, "   =   ", node.Field("speed").Separator)
+	assert.Equal(t, "[ \"a\",\n  \"b\" ]", node.Field("tags").RawValue)
+}
+
+func TestParseDocumentWithoutHeader(t *testing.T) {
+	content := "config_version=4\n\n[application]\n\nconfig/name=\"Test\"\n"
+	doc, err := ParseDocument(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, content, doc.String())
+	assert.Nil(t, doc.Header())
+	assert.False(t, doc.Sections[0].HasHeader)
+	assert.Equal(t, "4", doc.Sections[0].Field("config_version").RawValue)
+}
+
+func TestParseDocumentFail(t *testing.T) {
+	for _, content := range []string{
+		"[node name=\"Test\"",
+		"field = ",
+		"field = Vector2( 1, 2",
+		"= 12",
+		"[ node]",
+	} {
+		_, err := ParseDocument(strings.NewReader(content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestParseDocumentFailReturnsParseError(t *testing.T) {
+	_, err := ParseDocumentFile("Broken.tscn", strings.NewReader("[gd_scene]\nfield = Vector2( 1, 2"))
+
+	var parseError *diagnostic.ParseError
+	assert.True(t, errors.As(err, &parseError))
+	assert.Equal(t, `unexpected end of file, unclosed "("`, parseError.Message())
+	assert.Equal(t, lexer.Position{Filename: "Broken.tscn", Offset: 26, Line: 2, Column: 16}, parseError.Position)
+}
+
+func TestDocumentSetFieldOnlyChangesOneLine(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(documentContent))
+	assert.NoError(t, err)
+
+	doc.Sections[2].SetField("speed", "42.0", " = ")
+
+	expected := strings.Replace(documentContent, "speed   =   13.370 ; fast", "speed   =   42.0 ; fast", 1)
+	assert.Equal(t, expected, doc.String())
+}
+
+func TestDocumentAddAndRemove(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(documentContent))
+	assert.NoError(t, err)
+
+	node := doc.Sections[2]
+	node.SetField("visible", "false", " = ")
+	assert.True(t, node.RemoveField("speed"))
+	assert.False(t, node.RemoveField("speed"))
+	node.SetAttribute("parent", `"."`)
+
+	section := doc.AddSection("node")
+	section.SetAttribute("name", `"Sprite"`)
+	section.SetField("scale", "Vector2( 2, 2 )", " = ")
+
+	assert.Equal(t, `; the player scene
+[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Player.gd" type="Script" id=1]
+
+[node name="Player" type="KinematicBody2D" parent="."]
+script = ExtResource( 1 )
+tags = [ "a",
+  "b" ]
+visible = false
+
+[node name="Sprite"]
+scale = Vector2( 2, 2 )
+`, doc.String())
+
+	assert.True(t, doc.RemoveSection(section))
+	assert.False(t, doc.RemoveSection(section))
+}
+
+func TestDocumentEntryValue(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(documentContent))
+	assert.NoError(t, err)
+
+	value, err := doc.Sections[2].Field("speed").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, 13.37, value.Raw())
+
+	file, err := doc.File()
+	assert.NoError(t, err)
+	assert.Len(t, file.Sections, 2)
+}
+
+// keep integration tests at the bottom please
+func TestIntegrationParseDocumentFixturesRoundTrip(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(cwd, "..", "..", "test", "fixtures", "*"))
+	if err != nil {
+		panic(err)
+	}
+
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		// ignore the README.md file
+		if filepath.Base(file) == "README.md" {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			panic(err)
+		}
+
+		doc, err := ParseDocument(strings.NewReader(string(content)))
+		if !assert.NoError(t, errors.Wrapf(err, "error with fixture: '%s'", file)) {
+			continue
+		}
+
+		assert.Equal(t, string(content), doc.String(), file)
+	}
+}
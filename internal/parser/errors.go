@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+)
+
+// syntaxError creates a diagnostic.ParseError at the position
+func syntaxError(pos lexer.Position, format string, args ...interface{}) error {
+	return &diagnostic.ParseError{
+		Location: diagnostic.Location{Position: pos},
+		Msg:      fmt.Sprintf(format, args...),
+	}
+}
+
+// toParseError converts the errors of participle and its lexer into a diagnostic.ParseError
+func toParseError(err error) error {
+	var participleError participle.Error
+	if !errors.As(err, &participleError) {
+		return err
+	}
+	return syntaxError(participleError.Position(), "%s", participleError.Message())
+}
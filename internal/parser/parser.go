@@ -3,25 +3,39 @@ package parser
 
 import (
 	"io"
+	"strings"
 
 	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
-var tscnLexer = lexer.MustSimple([]lexer.Rule{
+// negativeInfinityGodot4 is how Godot 4 writes negative infinity, Godot 3 writes -inf
+const negativeInfinityGodot4 = "inf_neg"
+
+var tscnRules = []lexer.Rule{
 	{
+		// Godot 4 writes negative infinity as inf_neg
 		Name:    "Float",
-		Pattern: `-?\d+(\.\d+|e-?\d+)(e-?\d+)?\b`,
+		Pattern: `-?(\d+(\.\d+|e[-+]?\d+)(e[-+]?\d+)?|inf_neg|inf|nan)\b`,
+		Action:  nil,
+	},
+	{
+		// typed collections introduced with Godot 4, e.g. Array[int]([1, 2, 3])
+		Name:    "Generic",
+		Pattern: `(Array|Dictionary)\[`,
 		Action:  nil,
 	},
 	{
+		// dots are used by keys of platform specific variants, e.g. path.s3tc in .import files, dashes by keys like
+		// architectures/arm64-v8a in export_presets.cfg. Godot 4 TileSet atlas sources use keys starting with the atlas
+		// coordinates of a tile like 0:0/0/terrain.
 		Name:    "Ident",
-		Pattern: `([0-9]+)?/?[a-zA-Z_][a-zA-Z_\d/]*`,
+		Pattern: `[0-9]+:[0-9]+/[a-zA-Z_\d/.-]+|([0-9]+)?/?[a-zA-Z_][a-zA-Z_\d/.-]*`,
 		Action:  nil,
 	},
 	{
 		Name:    "String",
-		Pattern: `"[^"]*"`,
+		Pattern: `"(\\.|[^"\\])*"`,
 		Action:  nil,
 	},
 	{
@@ -31,7 +45,7 @@ var tscnLexer = lexer.MustSimple([]lexer.Rule{
 	},
 	{
 		Name:    "Punct",
-		Pattern: `[][=():,{}]`,
+		Pattern: `[][=():,{}&]`,
 		Action:  nil,
 	},
 	{
@@ -44,20 +58,43 @@ var tscnLexer = lexer.MustSimple([]lexer.Rule{
 		Pattern: `\s+`,
 		Action:  nil,
 	},
-})
+}
+
+var tscnLexer = lexer.MustSimple(tscnRules)
 
 var tscnParser = participle.MustBuild(
 	&TscnFile{},
 	participle.Lexer(tscnLexer),
 	participle.Unquote("String"),
+	participle.Map(trimGenericBracket, "Generic"),
+	participle.Map(mapNegativeInfinity, "Float"),
+	participle.UseLookahead(2),
 )
 
+func trimGenericBracket(token lexer.Token) (lexer.Token, error) {
+	token.Value = strings.TrimSuffix(token.Value, "[")
+	return token, nil
+}
+
+// mapNegativeInfinity maps the inf_neg of Godot 4 to -inf which can be parsed as float
+func mapNegativeInfinity(token lexer.Token) (lexer.Token, error) {
+	if token.Value == negativeInfinityGodot4 {
+		token.Value = "-inf"
+	}
+	return token, nil
+}
+
 // Parse content and return a simple representation of the file format.
 func Parse(r io.Reader) (*TscnFile, error) {
+	return ParseFile("", r)
+}
+
+// ParseFile works like Parse, the filename is added to the positions of the file
+func ParseFile(filename string, r io.Reader) (*TscnFile, error) {
 	ast := &TscnFile{}
-	err := tscnParser.Parse("", r, ast)
+	err := tscnParser.Parse(filename, r, ast)
 	if err != nil {
-		return nil, err
+		return nil, toParseError(err)
 	}
 	return ast, nil
 }
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 )
 
 func TestParseFail(t *testing.T) {
@@ -16,6 +19,26 @@ func TestParseFail(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParseFailReturnsParseError(t *testing.T) {
+	_, err := ParseFile("Broken.tscn", strings.NewReader("[gd_scene]\n[node name=]"))
+
+	var parseError *diagnostic.ParseError
+	assert.True(t, errors.As(err, &parseError))
+	assert.Equal(t, "Broken.tscn", parseError.Position.Filename)
+	assert.Equal(t, 2, parseError.Position.Line)
+	assert.NotEmpty(t, parseError.Message())
+}
+
+func TestParseValueEndPosition(t *testing.T) {
+	tscn, err := Parse(strings.NewReader(`texture = ExtResource( 4 )`))
+	assert.NoError(t, err)
+
+	value := tscn.Fields[0].Value
+	assert.Equal(t, 11, value.Pos.Column)
+	assert.Equal(t, 27, value.EndPos.Column)
+	assert.Equal(t, 27, value.Type.EndPos.Column)
+}
+
 func TestParseFileDescriptorWithAttributes(t *testing.T) {
 	content := "[gd_scene load_steps=0 format=2]"
 
@@ -230,6 +253,54 @@ reference_type = Object(InputEventKey,"resource_local_to_scene":false,"resource_
 	assert.NoError(t, err)
 }
 
+func TestParseFormat3Values(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=3 uid="uid://cecaux1sm7mo0"]
+string_name = &"attack"
+typed_array = Array[StringName]([&"hero", &"controllable"])
+typed_dictionary = Dictionary[String, int]({})
+escaped_string = "The \"Hero\""
+infinity = inf
+negative_infinity = -inf
+negative_infinity_godot4 = inf_neg
+exponent = 1.5e+06
+reference = ExtResource("1_x7k2p")
+string_name_keys = {
+&"attack": SubResource("Animation_qa5iw")
+}`
+	scene, err := Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	fields := map[string]*GdValue{}
+	for _, field := range scene.Fields {
+		fields[field.Key] = field.Value
+	}
+
+	assert.Equal(t, StringName("attack"), fields["string_name"].Raw())
+
+	typedArray := fields["typed_array"].Type
+	assert.Equal(t, "Array", typedArray.Key)
+	assert.Len(t, typedArray.TypeParameters, 1)
+	assert.Equal(t, "StringName", typedArray.TypeParameters[0].Key)
+	assert.Len(t, typedArray.Parameters[0].Array, 2)
+
+	typedDictionary := fields["typed_dictionary"].Type
+	assert.Equal(t, "Dictionary", typedDictionary.Key)
+	assert.Len(t, typedDictionary.TypeParameters, 2)
+
+	assert.Equal(t, `The "Hero"`, fields["escaped_string"].Raw())
+	assert.True(t, math.IsInf(*fields["infinity"].Float, 1))
+	assert.True(t, math.IsInf(*fields["negative_infinity"].Float, -1))
+	assert.True(t, math.IsInf(*fields["negative_infinity_godot4"].Float, -1))
+	assert.Equal(t, 1.5e+06, fields["exponent"].Raw())
+
+	id, ok := fields["reference"].Type.Parameters[0].AsID()
+	assert.True(t, ok)
+	assert.Equal(t, "1_x7k2p", id)
+
+	assert.True(t, fields["string_name_keys"].Map[0].IsStringNameKey)
+	assert.Equal(t, "attack", fields["string_name_keys"].Map[0].Key)
+}
+
 // keep regression tests at the bottom please (above integration tests though)
 func TestRegressionFieldNamesStartingWithNumbers(t *testing.T) {
 	content := `[gd_scene format=2]
@@ -253,6 +324,13 @@ field_name = "value"`
 	assert.NoError(t, err)
 }
 
+func TestRegressionFalseIsNotNull(t *testing.T) {
+	scene, err := Parse(strings.NewReader(`flag = false`))
+	assert.NoError(t, err)
+	assert.Equal(t, false, scene.Fields[0].Value.Raw())
+	assert.Equal(t, "false", scene.Fields[0].Value.ToString())
+}
+
 // keep integration tests at the bottom please
 func TestIntegrationParseFixtures(t *testing.T) {
 	cwd, err := os.Getwd()
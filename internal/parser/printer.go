@@ -0,0 +1,312 @@
+package parser
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PrintOptions controls how Print formats a TscnFile
+type PrintOptions struct {
+	// Godot3 formats values like Godot 3.x does, e.g. Vector2( 1, 2 ) instead of Vector2(1, 2)
+	Godot3 bool
+	// ConfigFile formats the file like Godots ConfigFile (project.godot, *.import), which writes key=value pairs and
+	// separates section headers from their fields with a blank line
+	ConfigFile bool
+}
+
+// Print writes a TscnFile in the TSCN file format
+func Print(w io.Writer, tscn *TscnFile, options PrintOptions) error {
+	p := printer{options: options}
+	p.printFile(tscn)
+	_, err := io.WriteString(w, p.String())
+	return err
+}
+
+// FormatValue returns the TSCN representation of a single value
+func FormatValue(value *GdValue, options PrintOptions) string {
+	p := printer{options: options}
+	p.printValue(value)
+	return p.String()
+}
+
+type printer struct {
+	strings.Builder
+	options PrintOptions
+}
+
+func (p *printer) printFile(tscn *TscnFile) {
+	hasContent := false
+
+	if tscn.Key != "" {
+		p.printSectionHeader(tscn.Key, tscn.Attributes)
+		hasContent = true
+	}
+
+	if len(tscn.Fields) > 0 {
+		if hasContent {
+			p.WriteString("\n")
+		}
+		p.printFields(tscn.Fields)
+		hasContent = true
+	}
+
+	var previous *GdResource
+	for _, section := range tscn.Sections {
+		if hasContent && !continuesSectionBlock(previous, section) {
+			p.WriteString("\n")
+		}
+		p.printSectionHeader(section.ResourceType, section.Attributes)
+		if len(section.Fields) > 0 {
+			if p.options.ConfigFile {
+				p.WriteString("\n")
+			}
+			p.printFields(section.Fields)
+		}
+		previous = section
+		hasContent = true
+	}
+}
+
+// continuesSectionBlock checks if a section belongs to the same block as the previous one, Godot writes consecutive
+// ext_resource and connection sections without blank lines between them
+func continuesSectionBlock(previous, current *GdResource) bool {
+	if previous == nil || previous.ResourceType != current.ResourceType || len(previous.Fields) > 0 {
+		return false
+	}
+
+	switch current.ResourceType {
+	case ResourceTypeExtResource, ResourceTypeConnection:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *printer) printSectionHeader(key string, attributes []*GdField) {
+	p.WriteString("[")
+	p.WriteString(key)
+	for _, attr := range attributes {
+		p.WriteString(" ")
+		p.WriteString(attr.Key)
+		p.WriteString("=")
+		if attr.Key == "groups" && p.options.Godot3 && len(attr.Value.Array) > 0 {
+			p.printMultiLineArray(attr.Value.Array)
+			continue
+		}
+		p.printValue(attr.Value)
+	}
+	p.WriteString("]\n")
+}
+
+func (p *printer) printFields(fields []*GdField) {
+	assignment := " = "
+	if p.options.ConfigFile {
+		assignment = "="
+	}
+
+	for _, field := range fields {
+		p.WriteString(field.Key)
+		p.WriteString(assignment)
+		p.printValue(field.Value)
+		p.WriteString("\n")
+	}
+}
+
+func (p *printer) printValue(v *GdValue) {
+	switch value := v.Raw().(type) {
+	case []*GdMapField:
+		p.printMap(value)
+	case GdMapField:
+		p.printMapField(&value, ": ")
+	case []*GdValue:
+		p.printArray(value)
+	case StringName:
+		p.WriteString("&")
+		p.WriteString(quote(string(value)))
+	case string:
+		p.WriteString(quote(value))
+	case int64:
+		p.WriteString(strconv.FormatInt(value, 10))
+	case float64:
+		p.WriteString(p.formatFloat(value))
+	case bool:
+		p.WriteString(strconv.FormatBool(value))
+	case GdType:
+		p.printType(&value)
+	default:
+		p.WriteString("null")
+	}
+}
+
+func (p *printer) printMap(fields []*GdMapField) {
+	if len(fields) == 0 {
+		if p.options.Godot3 {
+			p.WriteString("{\n\n}")
+			return
+		}
+		p.WriteString("{}")
+		return
+	}
+
+	p.WriteString("{\n")
+	for index, field := range fields {
+		p.printMapField(field, ": ")
+		if index < len(fields)-1 {
+			p.WriteString(",")
+		}
+		p.WriteString("\n")
+	}
+	p.WriteString("}")
+}
+
+func (p *printer) printMapField(field *GdMapField, separator string) {
+	if field.IsStringNameKey {
+		p.WriteString("&")
+	}
+	p.WriteString(quote(field.Key))
+	p.WriteString(separator)
+	p.printValue(field.Value)
+}
+
+func (p *printer) printArray(values []*GdValue) {
+	if p.options.Godot3 {
+		p.WriteString("[ ")
+		p.printValueList(values, ", ")
+		p.WriteString(" ]")
+		return
+	}
+
+	p.WriteString("[")
+	p.printValueList(values, ", ")
+	p.WriteString("]")
+}
+
+// printMultiLineArray prints an array like Godot 3 prints node groups, every value on its own line
+func (p *printer) printMultiLineArray(values []*GdValue) {
+	p.WriteString("[\n")
+	for _, v := range values {
+		p.printValue(v)
+		p.WriteString(",\n")
+	}
+	p.WriteString("]")
+}
+
+func (p *printer) printValueList(values []*GdValue, separator string) {
+	for index, v := range values {
+		if index > 0 {
+			p.WriteString(separator)
+		}
+		p.printValue(v)
+	}
+}
+
+// godot3TypeNames maps types which have been renamed in Godot 4 to their Godot 3 names
+var godot3TypeNames = map[string]string{
+	"Transform3D": "Transform",
+	"Quaternion":  "Quat",
+}
+
+func (p *printer) printType(t *GdType) {
+	key := t.Key
+	if name, ok := godot3TypeNames[key]; ok && p.options.Godot3 {
+		key = name
+	}
+	p.WriteString(key)
+	if len(t.TypeParameters) > 0 {
+		p.WriteString("[")
+		for index, param := range t.TypeParameters {
+			if index > 0 {
+				p.WriteString(", ")
+			}
+			p.printType(param)
+		}
+		p.WriteString("]")
+	}
+
+	if !t.HasParameterList && len(t.Parameters) == 0 {
+		return
+	}
+
+	// objects are written without any whitespace, e.g. Object(InputEventKey,"device":0,"alt":false)
+	if t.Key == "Object" {
+		p.WriteString("(")
+		for index, param := range t.Parameters {
+			if index > 0 {
+				p.WriteString(",")
+			}
+			if param.KeyValuePair != nil {
+				p.printMapField(param.KeyValuePair, ":")
+				continue
+			}
+			p.printTypeArgument(param)
+		}
+		p.WriteString(")")
+		// Godot 3 terminates objects with a line break
+		if p.options.Godot3 {
+			p.WriteString("\n")
+		}
+		return
+	}
+
+	// Godot 3 pads parameters with spaces, except for node paths
+	padded := p.options.Godot3 && t.Key != "NodePath"
+
+	if padded {
+		p.WriteString("( ")
+	} else {
+		p.WriteString("(")
+	}
+
+	for index, param := range t.Parameters {
+		if index > 0 {
+			p.WriteString(", ")
+		}
+		p.printTypeArgument(param)
+	}
+
+	if padded {
+		p.WriteString(" )")
+	} else {
+		p.WriteString(")")
+	}
+}
+
+// printTypeArgument prints an argument of a type constructor, Godot writes floats without a trailing .0 here
+func (p *printer) printTypeArgument(v *GdValue) {
+	if v.Float != nil {
+		p.WriteString(p.formatNumber(*v.Float))
+		return
+	}
+	p.printValue(v)
+}
+
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// formatFloat formats a float value, whole numbers keep a trailing .0 to stay floats
+func (p *printer) formatFloat(f float64) string {
+	s := p.formatNumber(f)
+	if !strings.ContainsAny(s, ".en") {
+		s += ".0"
+	}
+	return s
+}
+
+func (p *printer) formatNumber(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1) && p.options.Godot3:
+		return "-inf"
+	case math.IsInf(f, -1):
+		return negativeInfinityGodot4
+	case math.IsNaN(f):
+		return "nan"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func printString(t *testing.T, content string, options PrintOptions) string {
+	tscn, err := Parse(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	var sb strings.Builder
+	assert.NoError(t, Print(&sb, tscn, options))
+	return sb.String()
+}
+
+func TestPrintGodot3Scene(t *testing.T) {
+	content := `[gd_scene load_steps=3 format=2]
+
+[ext_resource path="res://Player.png" type="Texture" id=1]
+[ext_resource path="res://Player.gd" type="Script" id=2]
+
+[sub_resource type="CapsuleShape2D" id=1]
+radius = 4.0
+height = 1e-05
+
+[node name="Player" type="KinematicBody2D" groups=[
+"player",
+]]
+script = ExtResource( 2 )
+
+[node name="Sprite" type="Sprite" parent="."]
+position = Vector2( 0, -9.5 )
+texture = ExtResource( 1 )
+frames = [  ]
+path = NodePath("Sprite:frame")
+flag = false
+
+[connection signal="area_entered" from="Hurtbox" to="." method="_on_Hurtbox_area_entered"]
+[connection signal="died" from="Hurtbox" to="." method="_on_Hurtbox_died"]
+
+[editable path="Hurtbox"]
+`
+	assert.Equal(t, content, printString(t, content, PrintOptions{Godot3: true}))
+}
+
+func TestPrintGodot4Scene(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=3 uid="uid://cecaux1sm7mo0"]
+
+[ext_resource type="Script" path="res://player.gd" id="1_x7k2p"]
+
+[node name="Player" type="CharacterBody2D" groups=["player"]]
+script = ExtResource("1_x7k2p")
+tags = Array[StringName]([&"hero", &"controllable"])
+title = "The \"Hero\""
+max_speed = inf
+min_speed = inf_neg
+keys = {
+&"attack": [],
+"times": PackedFloat32Array(0, 0.1)
+}
+`
+	assert.Equal(t, content, printString(t, content, PrintOptions{}))
+}
+
+func TestPrintNegativeInfinity(t *testing.T) {
+	content := "speed = Vector2(inf_neg, -inf)\n"
+	assert.Equal(t, "speed = Vector2(inf_neg, inf_neg)\n", printString(t, content, PrintOptions{}))
+	assert.Equal(t, "speed = Vector2( -inf, -inf )\n", printString(t, content, PrintOptions{Godot3: true}))
+}
+
+func TestPrintConfigFile(t *testing.T) {
+	content := `config_version=4
+
+[application]
+
+config/name="Test Game"
+
+[input]
+
+ui_accept={
+"deadzone": 0.5,
+"events": [ Object(InputEventKey,"device":0,"alt":false,"scancode":32)
+ ]
+}
+`
+	assert.Equal(t, content, printString(t, content, PrintOptions{Godot3: true, ConfigFile: true}))
+}
+
+func TestFormatValue(t *testing.T) {
+	tscn, err := Parse(strings.NewReader(`value = [1.0, Vector2(1.5, 2), {}]`))
+	assert.NoError(t, err)
+
+	value := tscn.Fields[0].Value
+	assert.Equal(t, `[1.0, Vector2(1.5, 2), {}]`, FormatValue(value, PrintOptions{}))
+	assert.Equal(t, "[ 1.0, Vector2( 1.5, 2 ), {\n\n} ]", FormatValue(value, PrintOptions{Godot3: true}))
+}
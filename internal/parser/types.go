@@ -2,9 +2,12 @@ package parser
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 )
 
 // Supported resource types
@@ -54,6 +57,11 @@ func (res *GdResource) GetAttribute(name string) (*GdValue, error) {
 	return nil, fmt.Errorf("unknown attribute in %s: %s", res.Pos, name)
 }
 
+// Location returns the location of a problem found at pos within the section
+func (res *GdResource) Location(pos lexer.Position) diagnostic.Location {
+	return diagnostic.Location{Position: pos, Section: res.ResourceType, SectionPosition: res.Pos}
+}
+
 // GetField finds an attribute by name, returns error if not found
 func (res *GdResource) GetField(name string) (*GdValue, error) {
 	for _, field := range res.Fields {
@@ -64,11 +72,51 @@ func (res *GdResource) GetField(name string) (*GdValue, error) {
 	return nil, fmt.Errorf("unknown field in %s: %s", res.Pos, name)
 }
 
+// AsID returns the value as a resource id, Godot 3 uses integer ids while Godot 4 uses strings
+func (v *GdValue) AsID() (string, bool) {
+	if v.Integer != nil {
+		return strconv.FormatInt(*v.Integer, 10), true
+	}
+
+	if v.String != nil {
+		return *v.String, true
+	}
+
+	return "", false
+}
+
 // GdType represents a type with values
 type GdType struct {
-	Key        string     `parser:" @Ident ('('"`
-	Parameters []*GdValue `parser:"(@@ ( ',' @@ )* )? ')')?"`
-	Pos        lexer.Position
+	Key            string    `parser:" ( @Ident | @Generic"`
+	TypeParameters []*GdType `parser:" @@ ( ',' @@ )* ']' )"`
+	// HasParameterList is false for bare identifiers like the class name in Object(InputEventKey, ...)
+	HasParameterList bool       `parser:"( @'('"`
+	Parameters       []*GdValue `parser:"(@@ ( ',' @@ )* )? ')')?"`
+	Pos              lexer.Position
+	// EndPos is the position of the first token after the type, which can be in one of the following lines
+	EndPos lexer.Position
+}
+
+// ToString returns a string representation of a GdType
+func (t *GdType) ToString() string {
+	key := t.Key
+	if len(t.TypeParameters) > 0 {
+		var typeParams []string
+		for _, param := range t.TypeParameters {
+			typeParams = append(typeParams, param.ToString())
+		}
+		key = fmt.Sprintf("%s[%s]", key, strings.Join(typeParams, ", "))
+	}
+
+	if !t.HasParameterList && len(t.Parameters) == 0 {
+		return key
+	}
+
+	var values []string
+	for _, param := range t.Parameters {
+		values = append(values, param.ToString())
+	}
+	return fmt.Sprintf("%s (%s)", key, strings.Join(values, ", "))
 }
 
 // GdField represents a field with a value
@@ -80,16 +128,32 @@ type GdField struct {
 
 // GdMapField represents a field with a value within a map
 type GdMapField struct {
-	Key   string   `parser:"@String ':'"`
-	Value *GdValue `parser:" @@"`
-	Pos   lexer.Position
+	IsStringNameKey bool     `parser:"@'&'?"`
+	Key             string   `parser:"@String ':'"`
+	Value           *GdValue `parser:" @@"`
+	Pos             lexer.Position
 }
 
 // ToString returns a string representation of a GdMapField
 func (kv *GdMapField) ToString() string {
+	if kv.IsStringNameKey {
+		return fmt.Sprintf("&\"%s\": %s", kv.Key, kv.Value.ToString())
+	}
 	return fmt.Sprintf("\"%s\": %s", kv.Key, kv.Value.ToString())
 }
 
+// Boolean is a bool which can be captured from the true and false keywords
+type Boolean bool
+
+// Capture implements participle.Capture
+func (b *Boolean) Capture(values []string) error {
+	*b = values[0] == "true"
+	return nil
+}
+
+// StringName represents Godot 4 StringName literals like &"name"
+type StringName string
+
 // GdValue represents a value
 type GdValue struct {
 	IsEmptyMap   *bool         `parser:" (@'{' '}')"`
@@ -97,13 +161,16 @@ type GdValue struct {
 	KeyValuePair *GdMapField   `parser:"| @@"`
 	IsEmptyArray *bool         `parser:"| (@'[' ']')"`
 	Array        []*GdValue    `parser:"| '[' ( @@ ( ',' @@ )* )? (',')? ']'"`
+	StringName   *string       `parser:"| '&' @String"`
 	String       *string       `parser:"| @String"`
 	Integer      *int64        `parser:"| @Int"`
 	Float        *float64      `parser:"| @Float"`
-	Bool         *bool         `parser:"| (@'true' | 'false')"`
+	Bool         *Boolean      `parser:"| @('true' | 'false')"`
 	Null         *bool         `parser:"| (@'null')"`
 	Type         *GdType       `parser:"| @@"`
 	Pos          lexer.Position
+	// EndPos is the position of the first token after the value, which can be in one of the following lines
+	EndPos lexer.Position
 }
 
 // Raw returns an interface{} which contains the actual value of the associated GdValue
@@ -120,6 +187,10 @@ func (v *GdValue) Raw() interface{} {
 		return v.Array
 	}
 
+	if v.StringName != nil {
+		return StringName(*v.StringName)
+	}
+
 	if v.String != nil {
 		return *v.String
 	}
@@ -133,7 +204,7 @@ func (v *GdValue) Raw() interface{} {
 	}
 
 	if v.Bool != nil {
-		return *v.Bool
+		return bool(*v.Bool)
 	}
 
 	if v.Null != nil {
@@ -164,6 +235,8 @@ func (v *GdValue) ToString() string {
 			values = append(values, v.ToString())
 		}
 		return fmt.Sprintf("[%s]", strings.Join(values, ", "))
+	case StringName:
+		return fmt.Sprintf("&\"%s\"", value)
 	case string:
 		return fmt.Sprintf("\"%s\"", value)
 	case int64:
@@ -173,11 +246,7 @@ func (v *GdValue) ToString() string {
 	case bool:
 		return fmt.Sprintf("%v", value)
 	case GdType:
-		var values []string
-		for _, param := range value.Parameters {
-			values = append(values, param.ToString())
-		}
-		return fmt.Sprintf("%s (%s)", v.Type.Key, strings.Join(values, ", "))
+		return value.ToString()
 	default:
 		return "null"
 	}
@@ -53,6 +53,17 @@ value2="test"`
 	})
 }
 
+func TestGdResourceLocation(t *testing.T) {
+	tscn, err := Parse(strings.NewReader("[gd_scene]\n[node name=\"Player\"]\nvalue=1"))
+	assert.NoError(t, err)
+
+	section := tscn.Sections[0]
+	location := section.Location(section.Fields[0].Value.Pos)
+	assert.Equal(t, "node", location.Section)
+	assert.Equal(t, 2, location.SectionPosition.Line)
+	assert.Equal(t, 3, location.Position.Line)
+}
+
 func TestGdMapFieldToString(t *testing.T) {
 	val := "value"
 	kv := GdMapField{
@@ -158,7 +169,7 @@ func TestGdValueToStringWithKeyValuePair(t *testing.T) {
 }
 
 func TestGdValueToStringWithArray(t *testing.T) {
-	s, i, f, b, n := "str", int64(42), 13.37, true, true
+	s, i, f, b, n := "str", int64(42), 13.37, Boolean(true), true
 	arr := GdValue{Array: []*GdValue{
 		{String: &s},
 		{Integer: &i},
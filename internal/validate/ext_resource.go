@@ -3,45 +3,55 @@ package validate
 import (
 	"fmt"
 
-	"github.com/pkg/errors"
-
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 )
 
 // https://docs.godotengine.org/en/stable/development/file_formats/tscn.html#external-resources
-func validatorExtResourceRequiredAttributes(tscnFile *parser.TscnFile) error {
+func validatorExtResourceRequiredAttributes(tscnFile *parser.TscnFile) diagnostic.List {
+	var diagnostics diagnostic.List
+
 	for _, section := range tscnFile.Sections {
 		if section.ResourceType != parser.ResourceTypeExtResource {
 			continue
 		}
 
-		// validate path
-		path, err := section.GetAttribute("path")
-		if err != nil {
-			return errors.Wrapf(err, "ext_resource is missing required field 'path' %s", section.Pos)
-		}
-		if path.String == nil {
-			return fmt.Errorf("ext_resource attribute path must be a string %s", section.Pos)
-		}
-
-		// validate type
-		t, err := section.GetAttribute("type")
-		if err != nil {
-			return errors.Wrapf(err, "ext_resource is missing required field 'type' %s", section.Pos)
-		}
-		if t.String == nil {
-			return fmt.Errorf("ext_resource attribute type must be a string %s", section.Pos)
+		// validate path and type
+		for _, key := range []string{"path", "type"} {
+			value, err := section.GetAttribute(key)
+			if err != nil {
+				diagnostics.Add(diagnostic.FromError(&diagnostic.MissingAttributeError{
+					Location:  section.Location(section.Pos),
+					Attribute: key,
+				}))
+				continue
+			}
+			if value.String == nil {
+				diagnostics.Add(diagnostic.FromError(&diagnostic.InvalidValueError{
+					Location: valueLocation(section, value),
+					Key:      key,
+					Msg:      fmt.Sprintf("ext_resource attribute %s must be a string", key),
+				}))
+			}
 		}
 
 		// validate id
 		id, err := section.GetAttribute("id")
 		if err != nil {
-			return errors.Wrapf(err, "ext_resource is missing required field 'id' %s", section.Pos)
+			diagnostics.Add(diagnostic.FromError(&diagnostic.MissingAttributeError{
+				Location:  section.Location(section.Pos),
+				Attribute: "id",
+			}))
+			continue
 		}
-		if id.Integer == nil {
-			return fmt.Errorf("ext_resource attribute id must be an integer %s", section.Pos)
+		if _, ok := id.AsID(); !ok {
+			diagnostics.Add(diagnostic.FromError(&diagnostic.InvalidValueError{
+				Location: valueLocation(section, id),
+				Key:      "id",
+				Msg:      "ext_resource attribute id must be an integer or a string",
+			}))
 		}
 	}
 
-	return nil
+	return diagnostics
 }
@@ -13,26 +13,33 @@ func TestValidatorExtResourceRequiredAttributes(t *testing.T) {
 	tscn, err := parser.Parse(strings.NewReader(`[gd_scene]
 [ext_resource path="res://Player.tscn" type="PackedScene" id=5]`))
 	assert.NoError(t, err)
-	assert.NoError(t, validatorExtResourceRequiredAttributes(tscn))
+	assert.Empty(t, validatorExtResourceRequiredAttributes(tscn))
 }
 
 func TestValidatorExtResourceRequiredAttributesNoPath(t *testing.T) {
 	tscn, err := parser.Parse(strings.NewReader(`[gd_scene]
 [ext_resource type="PackedScene" id=5]`))
 	assert.NoError(t, err)
-	assert.Error(t, validatorExtResourceRequiredAttributes(tscn))
+	assert.NotEmpty(t, validatorExtResourceRequiredAttributes(tscn))
 }
 
 func TestValidatorExtResourceRequiredAttributesNoType(t *testing.T) {
 	tscn, err := parser.Parse(strings.NewReader(`[gd_scene]
 [ext_resource path="res://Player.tscn" id=5]`))
 	assert.NoError(t, err)
-	assert.Error(t, validatorExtResourceRequiredAttributes(tscn))
+	assert.NotEmpty(t, validatorExtResourceRequiredAttributes(tscn))
 }
 
 func TestValidatorExtResourceRequiredAttributesNoId(t *testing.T) {
 	tscn, err := parser.Parse(strings.NewReader(`[gd_scene]
 [ext_resource path="res://Player.tscn" type="PackedScene"]`))
 	assert.NoError(t, err)
-	assert.Error(t, validatorExtResourceRequiredAttributes(tscn))
+	assert.NotEmpty(t, validatorExtResourceRequiredAttributes(tscn))
+}
+
+func TestValidatorExtResourceRequiredAttributesWithStringId(t *testing.T) {
+	tscn, err := parser.Parse(strings.NewReader(`[gd_scene format=3]
+[ext_resource type="PackedScene" uid="uid://dh1x0t8pnwr3l" path="res://Player.tscn" id="1_kq1ra"]`))
+	assert.NoError(t, err)
+	assert.Empty(t, validatorExtResourceRequiredAttributes(tscn))
 }
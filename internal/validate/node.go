@@ -4,23 +4,24 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/pkg/errors"
-
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 )
 
-func validatorFirstNodeHasNoParent(tscnFile *parser.TscnFile) error {
+func validatorFirstNodeHasNoParent(tscnFile *parser.TscnFile) diagnostic.List {
 	for _, section := range tscnFile.Sections {
 		if section.ResourceType != parser.ResourceTypeNode {
 			continue
 		}
 
-		_, err := section.GetAttribute("parent")
+		parent, err := section.GetAttribute("parent")
 		if err == nil {
-			return fmt.Errorf(
-				"the first node in the file, which is also the scene root, must not have a 'parent' attribute. %s",
-				section.Pos,
-			)
+			return diagnostic.List{diagnostic.FromError(&diagnostic.InvalidTreeError{
+				Location: valueLocation(section, parent),
+				Node:     nodeName(section),
+				Parent:   parent.ToString(),
+				Msg:      "the first node in the file, which is also the scene root, must not have a 'parent' attribute.",
+			})}
 		}
 
 		// we only care about the first node
@@ -30,7 +31,9 @@ func validatorFirstNodeHasNoParent(tscnFile *parser.TscnFile) error {
 	return nil
 }
 
-func validatorOnlyOneRootNode(tscnFile *parser.TscnFile) error {
+func validatorOnlyOneRootNode(tscnFile *parser.TscnFile) diagnostic.List {
+	var diagnostics diagnostic.List
+
 	foundRoot := false
 	for _, section := range tscnFile.Sections {
 		if section.ResourceType != parser.ResourceTypeNode {
@@ -40,27 +43,32 @@ func validatorOnlyOneRootNode(tscnFile *parser.TscnFile) error {
 		_, err := section.GetAttribute("parent")
 		if err != nil {
 			if foundRoot {
-				return fmt.Errorf("found a second root node (a node without parent): %s", section.Pos)
+				diagnostics.Add(diagnostic.FromError(&diagnostic.InvalidTreeError{
+					Location: section.Location(section.Pos),
+					Node:     nodeName(section),
+					Msg:      "found a second root node (a node without parent)",
+				}))
 			}
 
 			foundRoot = true
 		}
 	}
 
-	return nil
+	return diagnostics
 }
 
-func validatorTestIfAllResourceReferencesActuallyExist(tscnFile *parser.TscnFile) error {
+func validatorTestIfAllResourceReferencesActuallyExist(tscnFile *parser.TscnFile) diagnostic.List {
+	var diagnostics diagnostic.List
+
 	types := findTypeReferencesInTscnFile(tscnFile)
 
 	for _, t := range types {
-		err := doesTypeReferenceExistInTscnFile(tscnFile, t)
-		if err != nil {
-			return err
+		if d, ok := doesTypeReferenceExistInTscnFile(tscnFile, t); !ok {
+			diagnostics.Add(d)
 		}
 	}
 
-	return nil
+	return diagnostics
 }
 
 func findTypeReferencesInTscnFile(tscnFile *parser.TscnFile) (types []*parser.GdType) {
@@ -117,19 +125,36 @@ func findTypeReferenceInGdValue(v *parser.GdValue) (types []*parser.GdType) {
 	return types
 }
 
-func doesTypeReferenceExistInTscnFile(tscnFile *parser.TscnFile, typeRef *parser.GdType) error {
+func doesTypeReferenceExistInTscnFile(tscnFile *parser.TscnFile, typeRef *parser.GdType) (diagnostic.Diagnostic, bool) {
 	// if type reference isn't ExtResource/SubResource we don't need to check
 	if typeRef.Key != "ExtResource" && typeRef.Key != "SubResource" {
-		return nil
+		return diagnostic.Diagnostic{}, true
 	}
 
 	// type references have to be ExtResource(ID) or SubResource(ID)
 	if len(typeRef.Parameters) != 1 {
-		return fmt.Errorf(
-			"type reference %s(%v) is not a valid type reference",
-			typeRef.Key,
-			convertGdValuesIntoString(typeRef.Parameters),
-		)
+		return diagnostic.FromError(&diagnostic.InvalidValueError{
+			Location: typeLocation(tscnFile, typeRef),
+			Key:      typeRef.Key,
+			Msg: fmt.Sprintf(
+				"type reference %s(%v) is not a valid type reference",
+				typeRef.Key,
+				convertGdValuesIntoString(typeRef.Parameters),
+			),
+		}), false
+	}
+
+	typeRefID, ok := typeRef.Parameters[0].AsID()
+	if !ok {
+		return diagnostic.FromError(&diagnostic.InvalidValueError{
+			Location: typeLocation(tscnFile, typeRef),
+			Key:      typeRef.Key,
+			Msg: fmt.Sprintf(
+				"type reference %s(%v) must reference an integer or a string id",
+				typeRef.Key,
+				convertGdValuesIntoString(typeRef.Parameters),
+			),
+		}), false
 	}
 
 	for _, section := range tscnFile.Sections {
@@ -148,29 +173,27 @@ func doesTypeReferenceExistInTscnFile(tscnFile *parser.TscnFile, typeRef *parser
 			continue
 		}
 
+		// resources without a valid id are reported by other validators
 		idValue, err := section.GetAttribute("id")
 		if err != nil {
-			return errors.Wrapf(err, "could not retrieve attribute id %s", typeRef.Pos)
+			continue
 		}
-		if idValue.Integer == nil {
-			return fmt.Errorf("id field must be integer %s", typeRef.Pos)
+		resourceID, ok := idValue.AsID()
+		if !ok {
+			continue
 		}
 
-		typeRefID := *typeRef.Parameters[0].Integer
-		extResourceID := *idValue.Integer
-
 		// found referenced element, stop...
-		if typeRefID == extResourceID {
-			return nil
+		if typeRefID == resourceID {
+			return diagnostic.Diagnostic{}, true
 		}
 	}
 
-	return fmt.Errorf(
-		"could not find type reference %s(%v) %s",
-		typeRef.Key,
-		convertGdValuesIntoString(typeRef.Parameters),
-		typeRef.Pos,
-	)
+	return diagnostic.FromError(&diagnostic.DanglingReferenceError{
+		Location:  typeLocation(tscnFile, typeRef),
+		Reference: typeRef.Key,
+		ID:        convertGdValuesIntoString(typeRef.Parameters),
+	}), false
 }
 
 func convertGdValuesIntoString(values []*parser.GdValue) string {
@@ -180,3 +203,32 @@ func convertGdValuesIntoString(values []*parser.GdValue) string {
 	}
 	return strings.Join(parts, ", ")
 }
+
+// nodeName returns the name attribute of a node section, empty if it has none
+func nodeName(section *parser.GdResource) string {
+	name, err := section.GetAttribute("name")
+	if err != nil || name.String == nil {
+		return ""
+	}
+	return *name.String
+}
+
+// typeLocation returns the location of a type reference like ExtResource(1) including the section it's part of
+func typeLocation(tscnFile *parser.TscnFile, typeRef *parser.GdType) diagnostic.Location {
+	location := diagnostic.Location{
+		Position:        typeRef.Pos,
+		End:             typeRef.EndPos,
+		Section:         tscnFile.Key,
+		SectionPosition: tscnFile.Pos,
+	}
+
+	// sections are in order, the last one starting before the type reference contains it
+	for _, section := range tscnFile.Sections {
+		if section.Pos.Offset > typeRef.Pos.Offset {
+			break
+		}
+		location.Section = section.ResourceType
+		location.SectionPosition = section.Pos
+	}
+	return location
+}
@@ -4,21 +4,23 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 )
 
 func TestValidatorFirstNodeHasNoParentWithBadTestCase(t *testing.T) {
 	tscn, err := parser.Parse(strings.NewReader(`[gd_scene format=2] [node name="Test" type="Node2D" parent="."]`))
 	assert.NoError(t, err)
-	assert.Error(t, validatorFirstNodeHasNoParent(tscn))
+	assert.NotEmpty(t, validatorFirstNodeHasNoParent(tscn))
 }
 
 func TestValidatorFirstNodeHasNoParentWithGoodTestCase(t *testing.T) {
 	tscn, err := parser.Parse(strings.NewReader(`[gd_scene format=2] [node name="Test" type="Node2D"]`))
 	assert.NoError(t, err)
-	assert.NoError(t, validatorFirstNodeHasNoParent(tscn))
+	assert.Empty(t, validatorFirstNodeHasNoParent(tscn))
 }
 
 func TestValidatorOnlyOneRootNode(t *testing.T) {
@@ -26,5 +28,43 @@ func TestValidatorOnlyOneRootNode(t *testing.T) {
 [node name="Test" type="Node2D"]
 [node name="Test2" type="Node2D"]`))
 	assert.NoError(t, err)
-	assert.Error(t, validatorOnlyOneRootNode(tscn))
+	assert.NotEmpty(t, validatorOnlyOneRootNode(tscn))
+}
+
+func TestValidatorOnlyOneRootNodeReturnsInvalidTreeError(t *testing.T) {
+	tscn, err := parser.Parse(strings.NewReader(`[gd_scene]
+[node name="Test" type="Node2D"]
+[node name="Test2" type="Node2D"]`))
+	assert.NoError(t, err)
+
+	diagnostics := validatorOnlyOneRootNode(tscn)
+	assert.Len(t, diagnostics, 1)
+
+	var treeError *diagnostic.InvalidTreeError
+	assert.True(t, errors.As(diagnostics[0], &treeError))
+	assert.Equal(t, "Test2", treeError.Node)
+	assert.Equal(t, "node", treeError.Section)
+	assert.Equal(t, 3, treeError.Position.Line)
+}
+
+func TestValidatorResourceReferencesReturnsDanglingReferenceError(t *testing.T) {
+	tscn, err := parser.Parse(strings.NewReader(`[gd_scene]
+[ext_resource path="res://icon.png" type="Texture" id=1]
+[node name="Sprite" type="Sprite"]
+texture = ExtResource( 4 )`))
+	assert.NoError(t, err)
+
+	diagnostics := validatorTestIfAllResourceReferencesActuallyExist(tscn)
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, "could not find type reference ExtResource(4)", diagnostics[0].Message)
+
+	var dangling *diagnostic.DanglingReferenceError
+	assert.True(t, errors.As(diagnostics[0], &dangling))
+	assert.Equal(t, "ExtResource", dangling.Reference)
+	assert.Equal(t, "4", dangling.ID)
+	assert.Equal(t, "node", dangling.Section)
+	assert.Equal(t, 3, dangling.SectionPosition.Line)
+	assert.Equal(t, 4, dangling.Position.Line)
+	assert.Equal(t, 11, dangling.Position.Column)
+	assert.Equal(t, 27, dangling.End.Column)
 }
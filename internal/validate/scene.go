@@ -1,30 +1,42 @@
 package validate
 
 import (
-	"fmt"
-
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
 )
 
-func validatorSceneIsInSupportedFormat(tscnFile *parser.TscnFile) error {
+func validatorSceneIsInSupportedFormat(tscnFile *parser.TscnFile) diagnostic.List {
 	version, err := tscnFile.GetAttribute("format")
 	if err != nil {
 		return nil // no format specified, don't bother
 	}
 
 	if version.Integer == nil {
-		return fmt.Errorf("gd_scene format is not an integer %s", tscnFile.Pos)
+		return diagnostic.List{diagnostic.FromError(&diagnostic.InvalidValueError{
+			Location: headerLocation(tscnFile, version),
+			Key:      "format",
+			Msg:      "gd_scene format is not an integer",
+		})}
 	}
 
-	if *version.Integer != godot.FormatVersion {
-		return fmt.Errorf(
-			"gd_scene format is unsupported version '%d', we only support version '%d' %s",
-			*version.Integer,
-			godot.FormatVersion,
-			tscnFile.Pos,
-		)
+	if !godot.IsSupportedFormatVersion(*version.Integer) {
+		return diagnostic.List{diagnostic.FromError(&diagnostic.UnsupportedFormatError{
+			Location:  headerLocation(tscnFile, version),
+			Format:    *version.Integer,
+			Supported: []int64{godot.FormatVersionGodot3, godot.FormatVersionGodot4},
+		})}
 	}
 
 	return nil
 }
+
+// headerLocation returns the location of a problematic value in the file header, e.g. [gd_scene format=2]
+func headerLocation(tscnFile *parser.TscnFile, value *parser.GdValue) diagnostic.Location {
+	return diagnostic.Location{
+		Position:        value.Pos,
+		End:             value.EndPos,
+		Section:         tscnFile.Key,
+		SectionPosition: tscnFile.Pos,
+	}
+}
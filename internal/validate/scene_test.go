@@ -4,35 +4,53 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 )
 
 func TestValidatorSceneIsInSupportedFormatNoFormat(t *testing.T) {
 	tscn, err := parser.Parse(strings.NewReader(`[gd_scene]`))
 	assert.NoError(t, err)
-	err = validatorSceneIsInSupportedFormat(tscn)
-	assert.NoError(t, err)
+	assert.Empty(t, validatorSceneIsInSupportedFormat(tscn))
 }
 
 func TestValidatorSceneIsInSupportedFormatValidFormat(t *testing.T) {
 	tscn, err := parser.Parse(strings.NewReader(`[gd_scene format=2]`))
 	assert.NoError(t, err)
-	err = validatorSceneIsInSupportedFormat(tscn)
-	assert.NoError(t, err)
+	assert.Empty(t, validatorSceneIsInSupportedFormat(tscn))
 }
 
 func TestValidatorSceneIsInSupportedFormatInvalidFormat1(t *testing.T) {
 	tscn, err := parser.Parse(strings.NewReader(`[gd_scene format=1]`))
 	assert.NoError(t, err)
-	err = validatorSceneIsInSupportedFormat(tscn)
-	assert.Error(t, err)
+	assert.NotEmpty(t, validatorSceneIsInSupportedFormat(tscn))
+}
+
+func TestValidatorSceneIsInSupportedFormatValidFormat3(t *testing.T) {
+	tscn, err := parser.Parse(strings.NewReader(`[gd_scene format=3 uid="uid://cecaux1sm7mo0"]`))
+	assert.NoError(t, err)
+	assert.Empty(t, validatorSceneIsInSupportedFormat(tscn))
 }
 
-func TestValidatorSceneIsInSupportedFormatInvalidFormat3(t *testing.T) {
-	tscn, err := parser.Parse(strings.NewReader(`[gd_scene format=3]`))
+func TestValidatorSceneIsInSupportedFormatInvalidFormat4(t *testing.T) {
+	tscn, err := parser.Parse(strings.NewReader(`[gd_scene format=4]`))
 	assert.NoError(t, err)
-	err = validatorSceneIsInSupportedFormat(tscn)
-	assert.Error(t, err)
+	assert.NotEmpty(t, validatorSceneIsInSupportedFormat(tscn))
+}
+
+func TestValidatorSceneIsInSupportedFormatReturnsUnsupportedFormatError(t *testing.T) {
+	tscn, err := parser.Parse(strings.NewReader(`[gd_resource type="Animation" format=5]`))
+	assert.NoError(t, err)
+
+	diagnostics := validatorSceneIsInSupportedFormat(tscn)
+	assert.Len(t, diagnostics, 1)
+
+	var unsupported *diagnostic.UnsupportedFormatError
+	assert.True(t, errors.As(diagnostics[0], &unsupported))
+	assert.Equal(t, int64(5), unsupported.Format)
+	assert.Equal(t, "gd_resource", unsupported.Section)
+	assert.Equal(t, 38, unsupported.Position.Column)
 }
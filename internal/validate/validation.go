@@ -7,29 +7,66 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 )
 
-type tscnFileValidationFunc func(tscnFile *parser.TscnFile) error
+// tscnFileValidationFunc returns every problem found, the rule ID is set by the caller
+type tscnFileValidationFunc func(tscnFile *parser.TscnFile) diagnostic.List
 
-// TscnFileFormat runs a set of pre-defined validators against a TSCN file
+// Validator is one of the pre-defined validators
+type Validator struct {
+	// ID is the rule ID of the diagnostics the validator reports
+	ID       string
+	Name     string
+	Function tscnFileValidationFunc
+}
+
+var validators = []Validator{
+	{"ext-resource-required-attributes", "ExtResource has required attributes", validatorExtResourceRequiredAttributes},
+	{"root-node-without-parent", "Scene root must not have a path attribute", validatorFirstNodeHasNoParent},
+	{"single-root-node", "Scene must not have multiple root nodes", validatorOnlyOneRootNode},
+	{"supported-format", "Scene must be set to supported version", validatorSceneIsInSupportedFormat},
+	{
+		"resource-references-exist",
+		"All references to ExtResource/SubResource must exist",
+		validatorTestIfAllResourceReferencesActuallyExist,
+	},
+}
+
+// TscnFileFormat runs a set of pre-defined validators against a TSCN file and returns the first problem found
 func TscnFileFormat(tscnFile *parser.TscnFile) error {
-	validators := []struct {
-		Name     string
-		Function tscnFileValidationFunc
-	}{
-		{"ExtResource has required attributes", validatorExtResourceRequiredAttributes},
-		{"Scene root must not have a path attribute", validatorFirstNodeHasNoParent},
-		{"Scene must not have multiple root nodes", validatorOnlyOneRootNode},
-		{"Scene must be set to supported version", validatorSceneIsInSupportedFormat},
-		{"All references to ExtResource/SubResource must exist", validatorTestIfAllResourceReferencesActuallyExist},
+	for _, validator := range validators {
+		diagnostics := validator.Function(tscnFile)
+		if len(diagnostics) > 0 {
+			d := diagnostics[0]
+			d.RuleID = validator.ID
+			return errors.Wrap(d, fmt.Sprintf("validate for '%s' failed", validator.Name))
+		}
 	}
 
+	return nil
+}
+
+// Validators returns the pre-defined validators in the order they run
+func Validators() []Validator {
+	return append([]Validator(nil), validators...)
+}
+
+// Diagnose runs a set of pre-defined validators against a TSCN file and returns all problems found
+func Diagnose(tscnFile *parser.TscnFile) diagnostic.List {
+	var diagnostics diagnostic.List
 	for _, validator := range validators {
-		err := validator.Function(tscnFile)
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf("validate for '%s' failed", validator.Name))
+		for _, d := range validator.Function(tscnFile) {
+			d.RuleID = validator.ID
+			diagnostics.Add(d)
 		}
 	}
+	return diagnostics
+}
 
-	return nil
+// valueLocation returns the location of a problematic value within the section
+func valueLocation(section *parser.GdResource, value *parser.GdValue) diagnostic.Location {
+	location := section.Location(value.Pos)
+	location.End = value.EndPos
+	return location
 }
@@ -3,6 +3,7 @@ package validate
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,6 +11,50 @@ import (
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
 )
 
+func TestDiagnose(t *testing.T) {
+	tscn, err := parser.Parse(strings.NewReader(`[gd_scene format=4]
+[ext_resource type="PackedScene"]
+[node name="Root" type="Node2D"]
+script = ExtResource( 1 )
+[node name="SecondRoot" type="Node2D"]
+[node name="ThirdRoot" type="Node2D"]
+texture = SubResource( 2 )`))
+	assert.NoError(t, err)
+
+	diagnostics := Diagnose(tscn)
+
+	var ruleIDs []string
+	for _, d := range diagnostics {
+		ruleIDs = append(ruleIDs, d.RuleID)
+	}
+	assert.Equal(t, []string{
+		"ext-resource-required-attributes",
+		"ext-resource-required-attributes",
+		"single-root-node",
+		"single-root-node",
+		"supported-format",
+		"resource-references-exist",
+		"resource-references-exist",
+	}, ruleIDs)
+
+	assert.Equal(t, 5, diagnostics[2].Position.Line)
+	assert.Equal(t, 6, diagnostics[3].Position.Line)
+	assert.Equal(t, "could not find type reference SubResource(2)", diagnostics[6].Message)
+}
+
+func TestTscnFileFormatReturnsFirstProblem(t *testing.T) {
+	tscn, err := parser.Parse(strings.NewReader(`[gd_scene]
+[node name="Root" type="Node2D"]
+[node name="SecondRoot" type="Node2D"]
+[node name="ThirdRoot" type="Node2D"]`))
+	assert.NoError(t, err)
+
+	err = TscnFileFormat(tscn)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "validate for 'Scene must not have multiple root nodes' failed")
+	assert.Contains(t, err.Error(), "3:1")
+}
+
 func TestIntegrationTscnFileFormat(t *testing.T) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -41,6 +86,7 @@ func TestIntegrationTscnFileFormat(t *testing.T) {
 
 		err = TscnFileFormat(tscnFile)
 		assert.NoError(t, err)
+		assert.Empty(t, Diagnose(tscnFile))
 
 		err = f.Close()
 		if err != nil {
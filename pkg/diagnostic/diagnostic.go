@@ -0,0 +1,174 @@
+// Package diagnostic contains the problems found while parsing, validating and converting Godot files
+package diagnostic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Severity determines how serious a problem is
+type Severity int
+
+const (
+	// SeverityError is a problem Godot would refuse to load
+	SeverityError Severity = iota
+	// SeverityWarning is a problem that doesn't prevent loading the file
+	SeverityWarning
+	// SeverityInfo is a hint which doesn't need to be fixed
+	SeverityInfo
+)
+
+// String returns the name of the severity, e.g. "error"
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// Diagnostic is a single problem found in a file
+type Diagnostic struct {
+	Severity Severity
+	// RuleID identifies the rule which found the problem, e.g. "single-root-node"
+	RuleID  string
+	Message string
+	// Position is where the problem was found, the filename is empty unless it was passed to the parser
+	Position lexer.Position
+	// End is the position after the problem, the zero position if only the start is known
+	End lexer.Position
+	// Err is the error describing the problem, nil if the diagnostic was created with New
+	Err error
+}
+
+// New creates a diagnostic of the severity error, the rule ID is set by whoever runs the rule
+func New(pos lexer.Position, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+		Position: pos,
+	}
+}
+
+// FromError creates a diagnostic of the severity error, the location is taken from the typed Error in the chain of err.
+// If the chain already contains a diagnostic it's returned instead.
+func FromError(err error) Diagnostic {
+	var existing Diagnostic
+	if errors.As(err, &existing) {
+		return existing
+	}
+
+	d := Diagnostic{Severity: SeverityError, Message: err.Error(), Err: err}
+
+	var typed Error
+	if errors.As(err, &typed) {
+		location := typed.Where()
+		d.Position = location.Position
+		d.End = location.End
+		// errors end with their position, which is already part of the diagnostic
+		d.Message = strings.TrimSuffix(d.Message, " "+location.Position.String())
+	}
+	return d
+}
+
+// String formats the diagnostic like a compiler would, e.g. "Player.tscn:4:1: error: message [rule-id]"
+func (d Diagnostic) String() string {
+	var sb strings.Builder
+	if d.Position.Filename != "" || d.Position.Line != 0 {
+		sb.WriteString(d.Position.String())
+		sb.WriteString(": ")
+	}
+	sb.WriteString(d.Severity.String())
+	sb.WriteString(": ")
+	sb.WriteString(d.Message)
+	if d.RuleID != "" {
+		sb.WriteString(" [")
+		sb.WriteString(d.RuleID)
+		sb.WriteString("]")
+	}
+	return sb.String()
+}
+
+// Error implements the error interface
+func (d Diagnostic) Error() string {
+	return d.String()
+}
+
+// Unwrap returns the error describing the problem, this makes errors.As work with diagnostics
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// List is a list of diagnostics
+type List []Diagnostic
+
+// Add appends the diagnostics to the list
+func (l *List) Add(diagnostics ...Diagnostic) {
+	*l = append(*l, diagnostics...)
+}
+
+// Errors returns the diagnostics with the severity error
+func (l List) Errors() List {
+	return l.WithSeverity(SeverityError)
+}
+
+// WithSeverity returns the diagnostics of the given severity
+func (l List) WithSeverity(severity Severity) List {
+	var result List
+	for _, d := range l {
+		if d.Severity == severity {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// HasErrors checks if the list contains at least one diagnostic with the severity error
+func (l List) HasErrors() bool {
+	for _, d := range l {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort sorts the diagnostics by filename and position
+func (l List) Sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		a, b := l[i].Position, l[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// Err returns the list as error if it contains errors, otherwise nil
+func (l List) Err() error {
+	if !l.HasErrors() {
+		return nil
+	}
+	return l
+}
+
+// Error implements the error interface, every diagnostic is written in its own line
+func (l List) Error() string {
+	lines := make([]string, len(l))
+	for index, d := range l {
+		lines[index] = d.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,52 @@
+package diagnostic
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnosticString(t *testing.T) {
+	d := New(lexer.Position{Filename: "Player.tscn", Line: 4, Column: 1}, "found a second root node %s", "Enemy")
+	d.RuleID = "single-root-node"
+	assert.Equal(t, "Player.tscn:4:1: error: found a second root node Enemy [single-root-node]", d.String())
+	assert.Equal(t, d.String(), d.Error())
+
+	d = Diagnostic{Severity: SeverityWarning, Message: "unused resource"}
+	assert.Equal(t, "warning: unused resource", d.String())
+}
+
+func TestSeverityString(t *testing.T) {
+	assert.Equal(t, "error", SeverityError.String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "info", SeverityInfo.String())
+	assert.Equal(t, "severity(7)", Severity(7).String())
+}
+
+func TestList(t *testing.T) {
+	var list List
+	assert.False(t, list.HasErrors())
+	assert.NoError(t, list.Err())
+
+	list.Add(Diagnostic{Severity: SeverityWarning, Message: "b", Position: lexer.Position{Line: 7, Column: 1}})
+	assert.False(t, list.HasErrors())
+	assert.NoError(t, list.Err())
+
+	list.Add(
+		New(lexer.Position{Line: 3, Column: 5}, "c"),
+		New(lexer.Position{Line: 3, Column: 1}, "a"),
+	)
+	assert.True(t, list.HasErrors())
+	assert.Len(t, list.Errors(), 2)
+	assert.Len(t, list.WithSeverity(SeverityWarning), 1)
+
+	list.Sort()
+	assert.Equal(t, "a", list[0].Message)
+	assert.Equal(t, "c", list[1].Message)
+	assert.Equal(t, "b", list[2].Message)
+
+	err := list.Err()
+	assert.Error(t, err)
+	assert.Equal(t, "3:1: error: a\n3:5: error: c\n7:1: warning: b", err.Error())
+}
@@ -0,0 +1,170 @@
+package diagnostic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Error is implemented by all typed errors of this package, use errors.As to get the concrete type
+type Error interface {
+	error
+	// Message returns the error message without its position
+	Message() string
+	// Where returns where the problem was found
+	Where() Location
+}
+
+// Location describes where a problem was found
+type Location struct {
+	// Position is where the problem starts
+	Position lexer.Position
+	// End is the position after the problem, the zero position if only the start is known
+	End lexer.Position
+	// Section is the type of the section involved, e.g. "ext_resource", or the file type like "gd_scene" for the file
+	// header
+	Section string
+	// SectionPosition is the position of the header of the section involved
+	SectionPosition lexer.Position
+}
+
+// Where returns the location itself, it makes the embedding errors implement Error
+func (l Location) Where() Location {
+	return l
+}
+
+// sectionName returns the section type or the fallback if the problem isn't part of a section
+func (l Location) sectionName(fallback string) string {
+	if l.Section == "" {
+		return fallback
+	}
+	return l.Section
+}
+
+// errorString formats errors like the rest of the parser, the message followed by the position
+func errorString(e Error) string {
+	return fmt.Sprintf("%s %s", e.Message(), e.Where().Position)
+}
+
+// ParseError is a syntax error, the file couldn't be parsed
+type ParseError struct {
+	Location
+	Msg string
+}
+
+// Message returns the error message without its position
+func (e *ParseError) Message() string {
+	return e.Msg
+}
+
+func (e *ParseError) Error() string {
+	return errorString(e)
+}
+
+// MissingAttributeError is a section without a required attribute, e.g. an ext_resource without path
+type MissingAttributeError struct {
+	Location
+	Attribute string
+}
+
+// Message returns the error message without its position
+func (e *MissingAttributeError) Message() string {
+	return fmt.Sprintf("%s is missing required attribute '%s'", e.sectionName("file"), e.Attribute)
+}
+
+func (e *MissingAttributeError) Error() string {
+	return errorString(e)
+}
+
+// InvalidValueError is an attribute or field with a value of the wrong type or format
+type InvalidValueError struct {
+	Location
+	// Key is the attribute or field containing the value
+	Key string
+	Msg string
+}
+
+// Message returns the error message without its position
+func (e *InvalidValueError) Message() string {
+	return e.Msg
+}
+
+func (e *InvalidValueError) Error() string {
+	return errorString(e)
+}
+
+// DanglingReferenceError is an ExtResource or SubResource reference to a resource which doesn't exist
+type DanglingReferenceError struct {
+	Location
+	// Reference is the type of the reference, either "ExtResource" or "SubResource"
+	Reference string
+	ID        string
+}
+
+// Message returns the error message without its position
+func (e *DanglingReferenceError) Message() string {
+	return fmt.Sprintf("could not find type reference %s(%s)", e.Reference, e.ID)
+}
+
+func (e *DanglingReferenceError) Error() string {
+	return errorString(e)
+}
+
+// InvalidTreeError is a problem with the node tree of a scene, e.g. multiple root nodes or a missing parent
+type InvalidTreeError struct {
+	Location
+	// Node is the name of the node involved, empty if it's not about a single node
+	Node string
+	// Parent is the parent path of the node, empty if the node has none
+	Parent string
+	Msg    string
+}
+
+// Message returns the error message without its position
+func (e *InvalidTreeError) Message() string {
+	return e.Msg
+}
+
+func (e *InvalidTreeError) Error() string {
+	return errorString(e)
+}
+
+// UnsupportedFormatError is a file written in a format version this parser doesn't support
+type UnsupportedFormatError struct {
+	Location
+	Format    int64
+	Supported []int64
+}
+
+// Message returns the error message without its position
+func (e *UnsupportedFormatError) Message() string {
+	supported := make([]string, len(e.Supported))
+	for index, format := range e.Supported {
+		supported[index] = fmt.Sprintf("'%d'", format)
+	}
+	return fmt.Sprintf(
+		"%s format is unsupported version '%d', we only support versions %s",
+		e.sectionName("file"),
+		e.Format,
+		strings.Join(supported, " and "),
+	)
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	return errorString(e)
+}
+
+// UnknownSectionError is a section of a type which isn't allowed in the file, e.g. a node in a resource
+type UnknownSectionError struct {
+	Location
+}
+
+// Message returns the error message without its position
+func (e *UnknownSectionError) Message() string {
+	return fmt.Sprintf("invalid resource type found: %s", e.Section)
+}
+
+func (e *UnknownSectionError) Error() string {
+	return errorString(e)
+}
@@ -0,0 +1,58 @@
+package diagnostic
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorMessages(t *testing.T) {
+	location := Location{Position: lexer.Position{Line: 3, Column: 7}, Section: "ext_resource"}
+
+	missing := &MissingAttributeError{Location: location, Attribute: "path"}
+	assert.Equal(t, "ext_resource is missing required attribute 'path'", missing.Message())
+	assert.Equal(t, "ext_resource is missing required attribute 'path' 3:7", missing.Error())
+
+	dangling := &DanglingReferenceError{Location: location, Reference: "ExtResource", ID: "4"}
+	assert.Equal(t, "could not find type reference ExtResource(4) 3:7", dangling.Error())
+
+	unsupported := &UnsupportedFormatError{
+		Location:  Location{Position: lexer.Position{Line: 1, Column: 18}, Section: "gd_scene"},
+		Format:    5,
+		Supported: []int64{2, 3},
+	}
+	assert.Equal(t, "gd_scene format is unsupported version '5', we only support versions '2' and '3'", unsupported.Message())
+
+	unknown := &UnknownSectionError{Location: Location{Section: "node"}}
+	assert.Equal(t, "invalid resource type found: node", unknown.Message())
+}
+
+func TestFromError(t *testing.T) {
+	err := errors.Wrap(&DanglingReferenceError{
+		Location: Location{
+			Position: lexer.Position{Filename: "Player.tscn", Line: 9, Column: 9},
+			End:      lexer.Position{Filename: "Player.tscn", Line: 9, Column: 25},
+			Section:  "node",
+		},
+		Reference: "ExtResource",
+		ID:        "4",
+	}, "could not convert node")
+
+	d := FromError(err)
+	assert.Equal(t, SeverityError, d.Severity)
+	assert.Equal(t, "could not convert node: could not find type reference ExtResource(4)", d.Message)
+	assert.Equal(t, 9, d.Position.Line)
+	assert.Equal(t, 25, d.End.Column)
+
+	// the typed error is still reachable through the diagnostic and further wrapping
+	var dangling *DanglingReferenceError
+	assert.True(t, errors.As(errors.Wrap(d, "invalid file format"), &dangling))
+	assert.Equal(t, "4", dangling.ID)
+	assert.Equal(t, "node", dangling.Section)
+
+	d = FromError(errors.New("could not read file"))
+	assert.Equal(t, "could not read file", d.Message)
+	assert.Equal(t, lexer.Position{}, d.Position)
+}
@@ -0,0 +1,90 @@
+package diagnostic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Render formats the diagnostic like String and adds the source line with a caret below the problem, e.g.
+//
+//	Player.tscn:4:1: error: found a second root node (a node without parent) [single-root-node]
+//	4 | [node name="Enemy" type="Node2D"]
+//	  | ^
+//
+// The source is the content of the file the diagnostic was found in, if the position isn't part of it the result is the
+// same as String.
+func (d Diagnostic) Render(source string) string {
+	header := d.String()
+
+	lines := strings.Split(source, "\n")
+	if d.Position.Line < 1 || d.Position.Line > len(lines) {
+		return header
+	}
+	line := []rune(strings.TrimSuffix(lines[d.Position.Line-1], "\r"))
+
+	start := d.Position.Column - 1
+	if start < 0 {
+		start = 0
+	}
+	if start > len(line) {
+		start = len(line)
+	}
+
+	// the end can be the start of the next token, the range stops at the end of the line and excludes whitespace
+	end := start + 1
+	switch {
+	case d.End.Line == d.Position.Line && d.End.Column > d.Position.Column:
+		end = d.End.Column - 1
+	case d.End.Line > d.Position.Line:
+		end = len(line)
+	}
+	if end > len(line) {
+		end = len(line)
+	}
+	for end > start+1 && unicode.IsSpace(line[end-1]) {
+		end--
+	}
+	width := end - start
+	if width < 1 {
+		width = 1
+	}
+
+	// keep tabs so the caret lines up with the source line
+	var padding strings.Builder
+	for _, r := range line[:start] {
+		if r == '\t' {
+			padding.WriteRune('\t')
+		} else {
+			padding.WriteRune(' ')
+		}
+	}
+
+	lineNumber := strconv.Itoa(d.Position.Line)
+	gutter := strings.Repeat(" ", len(lineNumber))
+
+	return fmt.Sprintf(
+		"%s\n%s | %s\n%s | %s%s",
+		header,
+		lineNumber,
+		string(line),
+		gutter,
+		padding.String(),
+		strings.Repeat("^", width),
+	)
+}
+
+// Render renders every diagnostic of the list like Diagnostic.Render, separated by an empty line
+func (l List) Render(source string) string {
+	rendered := make([]string, len(l))
+	for index, d := range l {
+		rendered[index] = d.Render(source)
+	}
+	return strings.Join(rendered, "\n\n")
+}
+
+// RenderError renders an error like Diagnostic.Render, the position is taken from the typed Error in its chain
+func RenderError(source string, err error) string {
+	return FromError(err).Render(source)
+}
@@ -0,0 +1,71 @@
+package diagnostic
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+)
+
+const renderSource = `[gd_scene load_steps=2 format=2]
+
+[node name="Player" type="KinematicBody2D"]
+	texture = ExtResource( 4 )
+`
+
+func TestRender(t *testing.T) {
+	d := New(lexer.Position{Filename: "Player.tscn", Line: 3, Column: 1}, "found a second root node")
+	assert.Equal(
+		t,
+		"Player.tscn:3:1: error: found a second root node\n"+
+			"3 | [node name=\"Player\" type=\"KinematicBody2D\"]\n"+
+			"  | ^",
+		d.Render(renderSource),
+	)
+}
+
+func TestRenderRangeKeepsTabs(t *testing.T) {
+	d := New(lexer.Position{Line: 4, Column: 12}, "could not find type reference ExtResource(4)")
+	d.End = lexer.Position{Line: 4, Column: 28}
+	assert.Equal(
+		t,
+		"4:12: error: could not find type reference ExtResource(4)\n"+
+			"4 | \ttexture = ExtResource( 4 )\n"+
+			"  | \t          ^^^^^^^^^^^^^^^^",
+		d.Render(renderSource),
+	)
+}
+
+func TestRenderRangeEndingInNextLine(t *testing.T) {
+	d := New(lexer.Position{Line: 4, Column: 12}, "could not find type reference ExtResource(4)")
+	d.End = lexer.Position{Line: 5, Column: 1}
+	assert.Equal(
+		t,
+		"4:12: error: could not find type reference ExtResource(4)\n"+
+			"4 | \ttexture = ExtResource( 4 )\n"+
+			"  | \t          ^^^^^^^^^^^^^^^^",
+		d.Render(renderSource),
+	)
+}
+
+func TestRenderWithoutSourceLine(t *testing.T) {
+	d := New(lexer.Position{Line: 42, Column: 1}, "unexpected end of file")
+	assert.Equal(t, d.String(), d.Render(renderSource))
+
+	d = Diagnostic{Message: "could not read file"}
+	assert.Equal(t, "error: could not read file", d.Render(renderSource))
+}
+
+func TestRenderError(t *testing.T) {
+	err := &MissingAttributeError{
+		Location:  Location{Position: lexer.Position{Line: 3, Column: 1}, Section: "node"},
+		Attribute: "parent",
+	}
+	assert.Equal(
+		t,
+		"3:1: error: node is missing required attribute 'parent'\n"+
+			"3 | [node name=\"Player\" type=\"KinematicBody2D\"]\n"+
+			"  | ^",
+		RenderError(renderSource, err),
+	)
+}
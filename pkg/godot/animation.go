@@ -0,0 +1,167 @@
+package godot
+
+import (
+	"math"
+	"sort"
+)
+
+// AnimationLoopMode determines what happens when an animation reaches its end
+type AnimationLoopMode int64
+
+const (
+	// AnimationLoopNone stops the animation at its end
+	AnimationLoopNone AnimationLoopMode = iota
+	// AnimationLoopLinear restarts the animation from the beginning, Godot 3 writes this as loop = true
+	AnimationLoopLinear
+	// AnimationLoopPingPong plays the animation backwards once it reached its end (Godot 4 only)
+	AnimationLoopPingPong
+)
+
+// AnimationTrackType is the type of an animation track as written in tracks/N/type
+type AnimationTrackType string
+
+const (
+	// AnimationTrackValue changes a property, the key values are the property values wrapped in a Value like node
+	// fields
+	AnimationTrackValue AnimationTrackType = "value"
+	// AnimationTrackMethod calls methods, the key values are of the type AnimationMethodCall
+	AnimationTrackMethod AnimationTrackType = "method"
+	// AnimationTrackBezier animates a float property along a curve, the key values are of the type AnimationBezierKey
+	AnimationTrackBezier AnimationTrackType = "bezier"
+	// AnimationTrackTransform changes a 3D transform (Godot 3 only), the key values are of the type
+	// AnimationTransformKey
+	AnimationTrackTransform AnimationTrackType = "transform"
+	// AnimationTrackPosition3D changes a 3D position (Godot 4 only), the key values are of the type Vector3
+	AnimationTrackPosition3D AnimationTrackType = "position_3d"
+	// AnimationTrackRotation3D changes a 3D rotation (Godot 4 only), the key values are of the type Quaternion
+	AnimationTrackRotation3D AnimationTrackType = "rotation_3d"
+	// AnimationTrackScale3D changes a 3D scale (Godot 4 only), the key values are of the type Vector3
+	AnimationTrackScale3D AnimationTrackType = "scale_3d"
+	// AnimationTrackBlendShape changes a blend shape (Godot 4 only), the key values are of the type float64
+	AnimationTrackBlendShape AnimationTrackType = "blend_shape"
+	// AnimationTrackAudio plays audio streams, the key values are of the type AnimationAudioClip
+	AnimationTrackAudio AnimationTrackType = "audio"
+	// AnimationTrackAnimation plays animations of an AnimationPlayer, the key values are the animation names
+	AnimationTrackAnimation AnimationTrackType = "animation"
+)
+
+// Animation is the model of an Animation resource, usually a sub resource of an AnimationPlayer
+type Animation struct {
+	// Name is the resource_name of the animation, empty if the file doesn't contain one
+	Name string
+	// Length is the duration of the animation in seconds
+	Length   float64
+	LoopMode AnimationLoopMode
+	// Step is the snapping interval used by the editor in seconds
+	Step   float64
+	Tracks []*AnimationTrack
+	MetaData
+}
+
+// AnimationTrack is a single track of an animation, e.g. tracks/0/type, tracks/0/path, ...
+type AnimationTrack struct {
+	Type AnimationTrackType
+	// Path is the node path of the animated node or property, e.g. Sprite:frame
+	Path string
+	// Interpolation is the interpolation type: 0 is nearest, 1 is linear and 2 is cubic
+	Interpolation int64
+	// LoopWrap and Enabled are true if the file doesn't contain them, like in Godot
+	LoopWrap bool
+	Imported bool
+	Enabled  bool
+	// UpdateMode is the update mode of value tracks: 0 is continuous, 1 is discrete and 2 is capture
+	UpdateMode int64
+	// Keys are the keys of the track sorted by time
+	Keys []AnimationKey
+	MetaData
+}
+
+// AnimationKey is a key of an animation track, the type of its value depends on the type of the track
+type AnimationKey struct {
+	Time       float64
+	Transition float64
+	Value      interface{}
+}
+
+// AnimationMethodCall is the value of a key of a method track
+type AnimationMethodCall struct {
+	Method string
+	// Args are the arguments wrapped in a Value like node fields
+	Args []interface{}
+}
+
+// AnimationBezierKey is the value of a key of a bezier track, the handles are relative to the key
+type AnimationBezierKey struct {
+	Value     float64
+	InHandle  Vector2
+	OutHandle Vector2
+	// HandleMode is 0 for free and 1 for balanced handles (Godot 4 only)
+	HandleMode int64
+}
+
+// AnimationTransformKey is the value of a key of a Godot 3 transform track
+type AnimationTransformKey struct {
+	Position Vector3
+	Rotation Quaternion
+	Scale    Vector3
+}
+
+// AnimationAudioClip is the value of a key of an audio track
+type AnimationAudioClip struct {
+	// Stream references the audio stream, nil if the key has no stream
+	Stream      *Reference
+	StartOffset float64
+	EndOffset   float64
+}
+
+// animationKeyTimeEpsilon is the tolerance used to match key times, times are stored as 32 bit floats by Godot
+const animationKeyTimeEpsilon = 1e-5
+
+// IsLooping checks if the animation restarts or plays backwards after reaching its end
+func (a *Animation) IsLooping() bool {
+	return a.LoopMode != AnimationLoopNone
+}
+
+// TracksOfType returns all tracks of the given type in their order
+func (a *Animation) TracksOfType(trackType AnimationTrackType) []*AnimationTrack {
+	var tracks []*AnimationTrack
+	for _, track := range a.Tracks {
+		if track.Type == trackType {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks
+}
+
+// NodePath returns the parsed path of the track, the sub names are the animated property
+func (t *AnimationTrack) NodePath() NodePath {
+	return ParseNodePath(t.Path)
+}
+
+// FindKey returns the index of the last key at or before time, -1 if the track has no key at or before time
+func (t *AnimationTrack) FindKey(time float64) int {
+	return sort.Search(len(t.Keys), func(index int) bool {
+		return t.Keys[index].Time > time+animationKeyTimeEpsilon
+	}) - 1
+}
+
+// KeyAt returns the key at exactly this time
+func (t *AnimationTrack) KeyAt(time float64) (AnimationKey, bool) {
+	index := t.FindKey(time)
+	if index == -1 || math.Abs(t.Keys[index].Time-time) > animationKeyTimeEpsilon {
+		return AnimationKey{}, false
+	}
+	return t.Keys[index], true
+}
+
+// KeysBetween returns all keys with a time from start up to and including end
+func (t *AnimationTrack) KeysBetween(start, end float64) []AnimationKey {
+	from := sort.Search(len(t.Keys), func(index int) bool {
+		return t.Keys[index].Time >= start-animationKeyTimeEpsilon
+	})
+	to := t.FindKey(end) + 1
+	if to < from {
+		return nil
+	}
+	return t.Keys[from:to]
+}
@@ -0,0 +1,80 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createAnimationTrack() *AnimationTrack {
+	return &AnimationTrack{
+		Type: AnimationTrackValue,
+		Path: "Sprite:frame",
+		Keys: []AnimationKey{
+			{Time: 0, Transition: 1, Value: int64(36)},
+			{Time: 0.1, Transition: 1, Value: int64(37)},
+			{Time: 0.2, Transition: 1, Value: int64(38)},
+			{Time: 0.3, Transition: 1, Value: int64(39)},
+		},
+	}
+}
+
+func TestAnimationTrackFindKey(t *testing.T) {
+	track := createAnimationTrack()
+
+	assert.Equal(t, -1, track.FindKey(-0.1))
+	assert.Equal(t, 0, track.FindKey(0))
+	assert.Equal(t, 0, track.FindKey(0.05))
+	assert.Equal(t, 1, track.FindKey(0.1))
+	assert.Equal(t, 2, track.FindKey(0.2))
+	assert.Equal(t, 3, track.FindKey(1))
+}
+
+func TestAnimationTrackKeyAt(t *testing.T) {
+	track := createAnimationTrack()
+
+	key, ok := track.KeyAt(0.2)
+	assert.True(t, ok)
+	assert.Equal(t, int64(38), key.Value)
+
+	// times are stored as 32 bit floats by Godot
+	key, ok = track.KeyAt(float64(float32(0.3)))
+	assert.True(t, ok)
+	assert.Equal(t, int64(39), key.Value)
+
+	_, ok = track.KeyAt(0.15)
+	assert.False(t, ok)
+	_, ok = (&AnimationTrack{}).KeyAt(0)
+	assert.False(t, ok)
+}
+
+func TestAnimationTrackKeysBetween(t *testing.T) {
+	track := createAnimationTrack()
+
+	assert.Len(t, track.KeysBetween(0.1, 0.2), 2)
+	assert.Len(t, track.KeysBetween(0.05, 0.15), 1)
+	assert.Len(t, track.KeysBetween(-1, 1), 4)
+	assert.Empty(t, track.KeysBetween(0.11, 0.19))
+	assert.Empty(t, track.KeysBetween(0.3, 0.1))
+}
+
+func TestAnimationTracksOfType(t *testing.T) {
+	animation := &Animation{
+		Tracks: []*AnimationTrack{
+			{Type: AnimationTrackValue},
+			{Type: AnimationTrackMethod, Path: "."},
+			{Type: AnimationTrackValue},
+		},
+	}
+
+	assert.Len(t, animation.TracksOfType(AnimationTrackValue), 2)
+	assert.Equal(t, ".", animation.TracksOfType(AnimationTrackMethod)[0].Path)
+	assert.Empty(t, animation.TracksOfType(AnimationTrackAudio))
+	assert.False(t, animation.IsLooping())
+}
+
+func TestAnimationTrackNodePath(t *testing.T) {
+	track := &AnimationTrack{Type: AnimationTrackValue, Path: "Sprite:frame"}
+	assert.Equal(t, []string{"Sprite"}, track.NodePath().Names)
+	assert.Equal(t, "frame", track.NodePath().Property())
+}
@@ -0,0 +1,11 @@
+package godot
+
+// Autoload is a script or scene which is loaded at startup, configured in the autoload section of project.godot
+type Autoload struct {
+	Name string
+	// Path is the script or scene without the leading * of singletons, e.g. "res://globals/Game.gd"
+	Path string
+	// Singleton is true if the autoload is available as global variable with its name, written as "*res://..."
+	Singleton bool
+	MetaData
+}
@@ -0,0 +1,152 @@
+package godot
+
+// ConfigFile is the model of files written by Godots ConfigFile class, e.g. export_presets.cfg, override.cfg,
+// plugin.cfg or .gdextension files. Sections and keys keep the order of the file.
+type ConfigFile struct {
+	// Format is the format version used to write values, e.g. FormatVersionGodot3 writes Vector2( 1, 2 )
+	Format int64
+	// sections in order, fields in front of the first section header belong to the section with an empty name
+	sections []*configSection
+	MetaData
+}
+
+type configSection struct {
+	name   string
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewConfigFile creates an empty config file which is written in the Godot 4 format
+func NewConfigFile() *ConfigFile {
+	return &ConfigFile{Format: FormatVersionGodot4}
+}
+
+// Sections returns the names of all sections in order
+func (c *ConfigFile) Sections() []string {
+	names := make([]string, len(c.sections))
+	for index, section := range c.sections {
+		names[index] = section.name
+	}
+	return names
+}
+
+// HasSection checks if the section exists
+func (c *ConfigFile) HasSection(section string) bool {
+	return c.section(section) != nil
+}
+
+// HasSectionKey checks if the key exists in the section
+func (c *ConfigFile) HasSectionKey(section, key string) bool {
+	s := c.section(section)
+	if s == nil {
+		return false
+	}
+	_, ok := s.values[key]
+	return ok
+}
+
+// SectionKeys returns the keys of a section in order, nil if the section doesn't exist
+func (c *ConfigFile) SectionKeys(section string) []string {
+	s := c.section(section)
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s.keys...)
+}
+
+// SectionFields returns the fields of a section as they were parsed, e.g. to decode them with Unmarshal. Returns nil
+// if the section doesn't exist.
+func (c *ConfigFile) SectionFields(section string) map[string]interface{} {
+	s := c.section(section)
+	if s == nil {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(s.values))
+	for key, value := range s.values {
+		fields[key] = value
+	}
+	return fields
+}
+
+// Field returns a value as it was parsed, which includes the Value wrappers and their positions
+func (c *ConfigFile) Field(section, key string) (interface{}, bool) {
+	s := c.section(section)
+	if s == nil {
+		return nil, false
+	}
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// GetValue returns a value without its Value wrappers like Godots ConfigFile.get_value, the default value is returned
+// if the key doesn't exist
+func (c *ConfigFile) GetValue(section, key string, defaultValue interface{}) interface{} {
+	value, ok := c.Field(section, key)
+	if !ok {
+		return defaultValue
+	}
+	return Unwrap(value)
+}
+
+// SetValue sets a value and creates the section if necessary, new keys are added at the end of the section. Like
+// Godots ConfigFile.set_value a nil value erases the key.
+func (c *ConfigFile) SetValue(section, key string, value interface{}) {
+	if value == nil {
+		c.EraseSectionKey(section, key)
+		return
+	}
+
+	s := c.section(section)
+	if s == nil {
+		s = &configSection{name: section, values: make(map[string]interface{})}
+		c.sections = append(c.sections, s)
+	}
+
+	if _, ok := s.values[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = value
+}
+
+// EraseSection removes a section with all its keys
+func (c *ConfigFile) EraseSection(section string) {
+	for index, s := range c.sections {
+		if s.name == section {
+			c.sections = append(c.sections[:index], c.sections[index+1:]...)
+			return
+		}
+	}
+}
+
+// EraseSectionKey removes a key, the section is removed as well if it has no keys left
+func (c *ConfigFile) EraseSectionKey(section, key string) {
+	s := c.section(section)
+	if s == nil {
+		return
+	}
+
+	if _, ok := s.values[key]; !ok {
+		return
+	}
+	delete(s.values, key)
+
+	for index, k := range s.keys {
+		if k == key {
+			s.keys = append(s.keys[:index], s.keys[index+1:]...)
+			break
+		}
+	}
+
+	if len(s.keys) == 0 {
+		c.EraseSection(section)
+	}
+}
+
+func (c *ConfigFile) section(name string) *configSection {
+	for _, s := range c.sections {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
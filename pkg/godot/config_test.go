@@ -0,0 +1,45 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFileGetValue(t *testing.T) {
+	config := NewConfigFile()
+	config.SetValue("application", "config/name", Value{Value: "Test"})
+
+	assert.Equal(t, "Test", config.GetValue("application", "config/name", nil))
+	assert.Equal(t, "default", config.GetValue("application", "missing", "default"))
+	assert.Equal(t, "default", config.GetValue("missing", "config/name", "default"))
+}
+
+func TestConfigFileSetValueKeepsOrder(t *testing.T) {
+	config := NewConfigFile()
+	config.SetValue("b", "z", 1)
+	config.SetValue("a", "y", 2)
+	config.SetValue("b", "x", 3)
+	config.SetValue("b", "z", 4)
+
+	assert.Equal(t, []string{"b", "a"}, config.Sections())
+	assert.Equal(t, []string{"z", "x"}, config.SectionKeys("b"))
+	assert.Equal(t, 4, config.GetValue("b", "z", nil))
+}
+
+func TestConfigFileErase(t *testing.T) {
+	config := NewConfigFile()
+	config.SetValue("a", "x", 1)
+	config.SetValue("a", "y", 2)
+	config.SetValue("b", "x", 3)
+
+	config.SetValue("a", "x", nil)
+	assert.False(t, config.HasSectionKey("a", "x"))
+	assert.True(t, config.HasSectionKey("a", "y"))
+
+	config.EraseSectionKey("a", "y")
+	assert.False(t, config.HasSection("a"))
+
+	config.EraseSection("b")
+	assert.Empty(t, config.Sections())
+}
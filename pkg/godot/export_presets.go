@@ -0,0 +1,73 @@
+package godot
+
+// ExportPresets are the export presets of a project (export_presets.cfg)
+type ExportPresets struct {
+	// Presets in the order of their index, they are written as preset.0, preset.1, ...
+	Presets []*ExportPreset
+	// Format is the format version used to write values, see ConfigFile.Format
+	Format int64
+	// Rest contains sections which don't belong to a preset as they were parsed
+	Rest map[string]map[string]interface{}
+	MetaData
+}
+
+// ExportPreset is a single preset of export_presets.cfg, consisting of the preset.N and the preset.N.options sections
+type ExportPreset struct {
+	// Name is the name of the preset shown in the export dialog, e.g. "Windows Desktop"
+	Name string
+	// Platform is the platform the preset exports for, e.g. "HTML5" or "Linux/X11"
+	Platform string
+	// Runnable marks the preset which is used for one click deploy
+	Runnable bool
+	// CustomFeatures are comma separated feature tags which are enabled in the export, e.g. "demo,steam"
+	CustomFeatures string
+	// ExportFilter selects the exported resources, e.g. "all_resources" or "scenes"
+	ExportFilter string
+	// IncludeFilter and ExcludeFilter are comma separated patterns of additional files, e.g. "*.json"
+	IncludeFilter string
+	ExcludeFilter string
+	// ExportPath is the path the project is exported to, relative to the project directory
+	ExportPath string
+	// Options are the platform specific options of the preset.N.options section without Value wrappers, e.g.
+	// "binary_format/64_bits": true
+	Options map[string]interface{}
+
+	// Fields and OptionFields contain the sections as they were parsed, the typed fields above take precedence when
+	// the presets are written
+	Fields       map[string]interface{}
+	OptionFields map[string]interface{}
+	MetaData
+}
+
+// NewExportPreset creates an empty preset for the platform
+func NewExportPreset(name, platform string) *ExportPreset {
+	return &ExportPreset{
+		Name:         name,
+		Platform:     platform,
+		ExportFilter: "all_resources",
+		Options:      make(map[string]interface{}),
+		Fields:       make(map[string]interface{}),
+		OptionFields: make(map[string]interface{}),
+	}
+}
+
+// Preset returns the preset with the given name, nil if there is none
+func (e *ExportPresets) Preset(name string) *ExportPreset {
+	for _, preset := range e.Presets {
+		if preset.Name == name {
+			return preset
+		}
+	}
+	return nil
+}
+
+// PresetsForPlatform returns all presets of a platform, e.g. "Android"
+func (e *ExportPresets) PresetsForPlatform(platform string) []*ExportPreset {
+	var presets []*ExportPreset
+	for _, preset := range e.Presets {
+		if preset.Platform == platform {
+			presets = append(presets, preset)
+		}
+	}
+	return presets
+}
@@ -0,0 +1,19 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportPresetsPreset(t *testing.T) {
+	web := NewExportPreset("Web", "HTML5")
+	demo := NewExportPreset("Web Demo", "HTML5")
+	linux := NewExportPreset("Linux", "Linux/X11")
+	presets := &ExportPresets{Presets: []*ExportPreset{web, demo, linux}}
+
+	assert.Equal(t, demo, presets.Preset("Web Demo"))
+	assert.Nil(t, presets.Preset("Windows"))
+	assert.Equal(t, []*ExportPreset{web, demo}, presets.PresetsForPlatform("HTML5"))
+	assert.Empty(t, presets.PresetsForPlatform("Android"))
+}
@@ -0,0 +1,124 @@
+package godot
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldGroup is a group of fields created by ExpandFields, e.g. the fields of tracks/0/path and tracks/0/type
+type FieldGroup map[string]interface{}
+
+// FieldList is a group of fields with consecutive indices created by ExpandFields, e.g. tracks/0, tracks/1
+type FieldList []interface{}
+
+// ExpandFields converts fields with slash separated keys like tracks/0/path into nested groups, so that
+// fields["tracks"] becomes a FieldList whose elements are FieldGroups containing "path". Groups whose keys are the
+// indices 0 to n-1 become lists, all others stay groups (e.g. the tile ids of a TileSet). If a key is used as value
+// and as group at once, like 0:0/0 and 0:0/0/terrain in Godot 4 TileSets, these keys stay unexpanded.
+func ExpandFields(fields map[string]interface{}) map[string]interface{} {
+	return expandFieldGroup(fields)
+}
+
+func expandFieldGroup(fields map[string]interface{}) map[string]interface{} {
+	type group struct {
+		value    interface{}
+		hasValue bool
+		children map[string]interface{}
+	}
+
+	groups := make(map[string]*group)
+	for key, value := range fields {
+		name, rest := key, ""
+		if index := strings.Index(key, "/"); index != -1 {
+			name, rest = key[:index], key[index+1:]
+		}
+
+		g, ok := groups[name]
+		if !ok {
+			g = &group{children: make(map[string]interface{})}
+			groups[name] = g
+		}
+
+		if rest == "" {
+			g.value, g.hasValue = value, true
+		} else {
+			g.children[rest] = value
+		}
+	}
+
+	result := make(map[string]interface{}, len(groups))
+	for name, g := range groups {
+		switch {
+		case len(g.children) == 0:
+			result[name] = g.value
+		case !g.hasValue:
+			result[name] = newFieldGroupOrList(expandFieldGroup(g.children))
+		default:
+			// the key is a value and a group at once, keep everything as it is
+			result[name] = g.value
+			for rest, value := range g.children {
+				result[name+"/"+rest] = value
+			}
+		}
+	}
+	return result
+}
+
+func newFieldGroupOrList(fields map[string]interface{}) interface{} {
+	list := make(FieldList, len(fields))
+	for key, value := range fields {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(fields) || strconv.Itoa(index) != key {
+			return FieldGroup(fields)
+		}
+		list[index] = value
+	}
+	return list
+}
+
+// FlattenFields is the reverse of ExpandFields, it converts FieldGroups and FieldLists back into slash separated keys
+func FlattenFields(fields map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		flattenField(result, key, value)
+	}
+	return result
+}
+
+func flattenField(result map[string]interface{}, key string, value interface{}) {
+	switch v := value.(type) {
+	case FieldGroup:
+		for childKey, childValue := range v {
+			flattenField(result, key+"/"+childKey, childValue)
+		}
+	case FieldList:
+		for index, childValue := range v {
+			flattenField(result, key+"/"+strconv.Itoa(index), childValue)
+		}
+	default:
+		result[key] = value
+	}
+}
+
+// Keys returns the keys of the group in sorted order, indices are sorted numerically and come before other keys
+func (g FieldGroup) Keys() []string {
+	keys := make([]string, 0, len(g))
+	for key := range g {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, aErr := strconv.Atoi(keys[i])
+		b, bErr := strconv.Atoi(keys[j])
+		switch {
+		case aErr == nil && bErr == nil && a != b:
+			return a < b
+		case (aErr == nil) != (bErr == nil):
+			return aErr == nil
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+	return keys
+}
@@ -0,0 +1,70 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandFields(t *testing.T) {
+	fields := map[string]interface{}{
+		"length":           1.0,
+		"tracks/0/type":    "value",
+		"tracks/0/path":    "Sprite:frame",
+		"tracks/1/type":    "method",
+		"bones/0/name":     "Hip",
+		"0/name":           "Grass",
+		"2/name":           "Water",
+		"0:0/0":            0,
+		"0:0/0/terrain":    1,
+		"surfaces/0":       "surface",
+		"shapes/0/shape":   "Shape",
+		"shapes/0/one_way": false,
+	}
+
+	expanded := ExpandFields(fields)
+
+	assert.Equal(t, map[string]interface{}{
+		"length": 1.0,
+		"tracks": FieldList{
+			FieldGroup{"type": "value", "path": "Sprite:frame"},
+			FieldGroup{"type": "method"},
+		},
+		"bones":    FieldList{FieldGroup{"name": "Hip"}},
+		"0":        FieldGroup{"name": "Grass"},
+		"2":        FieldGroup{"name": "Water"},
+		"0:0":      FieldGroup{"0": 0, "0/terrain": 1},
+		"surfaces": FieldList{"surface"},
+		"shapes":   FieldList{FieldGroup{"shape": "Shape", "one_way": false}},
+	}, expanded)
+
+	assert.Equal(t, fields, FlattenFields(expanded))
+}
+
+func TestExpandFieldsWithSparseIndices(t *testing.T) {
+	expanded := ExpandFields(map[string]interface{}{
+		"0/name": "Grass",
+		"5/name": "Water",
+		"2/name": "Sand",
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"0": FieldGroup{"name": "Grass"},
+		"2": FieldGroup{"name": "Sand"},
+		"5": FieldGroup{"name": "Water"},
+	}, expanded)
+
+	tracks := ExpandFields(map[string]interface{}{
+		"tracks/1/type": "value",
+		"tracks/2/type": "method",
+	})
+
+	group, ok := tracks["tracks"].(FieldGroup)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1", "2"}, group.Keys())
+}
+
+func TestFieldGroupKeys(t *testing.T) {
+	group := FieldGroup{"10": 1, "2": 2, "name": 3, "0": 4, "02": 5}
+	assert.Equal(t, []string{"0", "02", "2", "10", "name"}, group.Keys())
+}
@@ -0,0 +1,104 @@
+package godot
+
+import "sort"
+
+// GlobalClass is a script with a class_name, which makes it available as a type in the whole project
+type GlobalClass struct {
+	Name string
+	// Base is the class the script extends, either another global class or a built-in class like "Node2D"
+	Base string
+	// Path is the path of the script, e.g. "res://src/StateMachine.gd"
+	Path string
+	// Icon is the path of the icon shown in the editor, empty if the class doesn't have one
+	Icon     string
+	Language string
+	MetaData
+}
+
+// GlobalClassRegistry contains the global classes of a project, read from _global_script_classes in Godot 3 projects or
+// .godot/global_script_class_cache.cfg in Godot 4 projects
+type GlobalClassRegistry struct {
+	classes map[string]*GlobalClass
+}
+
+// NewGlobalClassRegistry creates a registry with the given classes
+func NewGlobalClassRegistry(classes ...*GlobalClass) *GlobalClassRegistry {
+	registry := &GlobalClassRegistry{classes: make(map[string]*GlobalClass, len(classes))}
+	for _, class := range classes {
+		registry.Add(class)
+	}
+	return registry
+}
+
+// Add adds a class, an existing class with the same name is replaced
+func (r *GlobalClassRegistry) Add(class *GlobalClass) {
+	r.classes[class.Name] = class
+}
+
+// Merge adds all classes of another registry, classes of the other registry replace classes with the same name
+func (r *GlobalClassRegistry) Merge(other *GlobalClassRegistry) {
+	for _, class := range other.classes {
+		r.Add(class)
+	}
+}
+
+// Classes returns all classes sorted by their name
+func (r *GlobalClassRegistry) Classes() []*GlobalClass {
+	classes := make([]*GlobalClass, 0, len(r.classes))
+	for _, class := range r.classes {
+		classes = append(classes, class)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		return classes[i].Name < classes[j].Name
+	})
+	return classes
+}
+
+// Class returns the class with the given name
+func (r *GlobalClassRegistry) Class(name string) (*GlobalClass, bool) {
+	class, ok := r.classes[name]
+	return class, ok
+}
+
+// ClassOfScript returns the class defined by the script at the given path
+func (r *GlobalClassRegistry) ClassOfScript(path string) (*GlobalClass, bool) {
+	for _, class := range r.classes {
+		if class.Path == path {
+			return class, true
+		}
+	}
+	return nil, false
+}
+
+// Inherits checks if a class is the base class or extends it directly or through other global classes
+func (r *GlobalClassRegistry) Inherits(name, base string) bool {
+	for visited := make(map[string]bool); !visited[name]; {
+		if name == base {
+			return true
+		}
+		visited[name] = true
+
+		class, ok := r.classes[name]
+		if !ok {
+			return false
+		}
+		name = class.Base
+	}
+	return false
+}
+
+// NativeBase returns the first built-in class a class extends, e.g. "CharacterBody2D" for a class Player extending a
+// global class Actor. Returns the name itself if it isn't a global class.
+func (r *GlobalClassRegistry) NativeBase(name string) string {
+	for visited := make(map[string]bool); !visited[name]; {
+		visited[name] = true
+
+		class, ok := r.classes[name]
+		if !ok {
+			return name
+		}
+		name = class.Base
+	}
+	// the classes extend each other in a cycle
+	return ""
+}
@@ -0,0 +1,47 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalClassRegistry(t *testing.T) {
+	registry := NewGlobalClassRegistry(
+		&GlobalClass{Name: "Player", Base: "Actor", Path: "res://Player.gd"},
+		&GlobalClass{Name: "Actor", Base: "CharacterBody2D", Path: "res://Actor.gd"},
+	)
+
+	assert.Equal(t, "Actor", registry.Classes()[0].Name)
+	assert.True(t, registry.Inherits("Player", "Actor"))
+	assert.True(t, registry.Inherits("Player", "CharacterBody2D"))
+	assert.True(t, registry.Inherits("Player", "Player"))
+	assert.False(t, registry.Inherits("Actor", "Player"))
+	assert.Equal(t, "CharacterBody2D", registry.NativeBase("Player"))
+	assert.Equal(t, "Node2D", registry.NativeBase("Node2D"))
+
+	_, ok := registry.ClassOfScript("res://Enemy.gd")
+	assert.False(t, ok)
+}
+
+func TestGlobalClassRegistryMerge(t *testing.T) {
+	registry := NewGlobalClassRegistry(&GlobalClass{Name: "Player", Path: "res://old/Player.gd"})
+	registry.Merge(NewGlobalClassRegistry(
+		&GlobalClass{Name: "Player", Path: "res://Player.gd"},
+		&GlobalClass{Name: "Enemy", Path: "res://Enemy.gd"},
+	))
+
+	assert.Len(t, registry.Classes(), 2)
+	player, _ := registry.Class("Player")
+	assert.Equal(t, "res://Player.gd", player.Path)
+}
+
+func TestGlobalClassRegistryWithCycle(t *testing.T) {
+	registry := NewGlobalClassRegistry(
+		&GlobalClass{Name: "A", Base: "B"},
+		&GlobalClass{Name: "B", Base: "A"},
+	)
+
+	assert.False(t, registry.Inherits("A", "Node"))
+	assert.Equal(t, "", registry.NativeBase("A"))
+}
@@ -1,7 +1,20 @@
 // Package godot represents the data models of a TSCN file
 package godot
 
-// FormatVersion defines the version of the TSCN file format
-// Snippet from the C++ code:
-//   https://github.com/godotengine/godot/blob/master/scene/resources/resource_format_text.cpp#L39
-const FormatVersion = 2
+// Versions of the TSCN file format, see FORMAT_VERSION in the C++ code:
+// https://github.com/godotengine/godot/blob/master/scene/resources/resource_format_text.cpp
+const (
+	// FormatVersionGodot3 is the format version written by Godot 3.x
+	FormatVersionGodot3 = 2
+	// FormatVersionGodot4 is the format version written by Godot 4.x
+	FormatVersionGodot4 = 3
+	// FormatVersion is the format version written by Godot 3.x, it predates the support of Godot 4
+	//
+	// Deprecated: use FormatVersionGodot3 or FormatVersionGodot4 instead.
+	FormatVersion = FormatVersionGodot3
+)
+
+// IsSupportedFormatVersion checks if the given TSCN file format version can be read
+func IsSupportedFormatVersion(version int64) bool {
+	return version == FormatVersionGodot3 || version == FormatVersionGodot4
+}
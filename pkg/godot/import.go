@@ -2,6 +2,26 @@ package godot
 
 // Import is the configuration of well Godot imports (*.import file)
 type Import struct {
+	// Importer is the name of the importer, e.g. "texture"
+	Importer string
+	// Type is the type of the imported resource, e.g. "StreamTexture"
+	Type string
+	// UID is the unique identifier of the imported resource (Godot 4 only)
+	UID string
+	// Path is the imported resource, empty if the resource is imported once per platform, see PlatformPaths
+	Path string
+	// PlatformPaths are the imported resources of the platform specific variants like path.s3tc, the key is the
+	// platform, e.g. "s3tc"
+	PlatformPaths map[string]string
+	// SourceFile is the file which was imported, e.g. res://icon.png
+	SourceFile string
+	// DestFiles are the files created by the importer
+	DestFiles []string
+	// Parameters are the import settings of the params section without Value wrappers, e.g. "compress/mode": int64(0)
+	Parameters map[string]interface{}
+
+	// Remap, Deps and Params contain the sections as they were parsed, the typed fields above take precedence when
+	// the import is written
 	Remap  map[string]interface{}
 	Deps   map[string]interface{}
 	Params map[string]interface{}
@@ -0,0 +1,190 @@
+package godot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InputMap contains the input actions of a project, decoded from the input section of project.godot
+type InputMap struct {
+	// Actions in the order of the file
+	Actions []*InputAction
+}
+
+// InputAction is an action like "ui_accept" with the events triggering it
+type InputAction struct {
+	Name     string
+	Deadzone float64
+	// Events are of the types *InputEventKey, *InputEventMouseButton, *InputEventJoypadButton and
+	// *InputEventJoypadMotion, other events are kept as *InputEventObject
+	Events []InputEvent
+	MetaData
+}
+
+// InputEvent is an event which triggers an input action
+type InputEvent interface {
+	// Binding describes the input of the event like "Ctrl+W" or "Joypad Button 0", events with the same binding
+	// trigger the same input
+	Binding() string
+}
+
+// InputModifiers are the modifier keys which have to be pressed for key and mouse button events
+type InputModifiers struct {
+	Alt   bool
+	Shift bool
+	// Ctrl is called control in Godot 3
+	Ctrl bool
+	Meta bool
+	// Command is Meta on macOS and Ctrl everywhere else (Godot 3 only)
+	Command bool
+}
+
+// prefix returns the pressed modifiers joined by + like Godot shows them, e.g. "Ctrl+Shift+"
+func (m InputModifiers) prefix() string {
+	var sb strings.Builder
+	for _, modifier := range []struct {
+		pressed bool
+		name    string
+	}{{m.Command, "Command"}, {m.Ctrl, "Ctrl"}, {m.Meta, "Meta"}, {m.Alt, "Alt"}, {m.Shift, "Shift"}} {
+		if modifier.pressed {
+			sb.WriteString(modifier.name)
+			sb.WriteString("+")
+		}
+	}
+	return sb.String()
+}
+
+// InputEventKey is a keyboard event
+type InputEventKey struct {
+	Device int64
+	InputModifiers
+	// Keycode is the key with the keyboard layout applied (scancode in Godot 3), 0 if the event uses PhysicalKeycode
+	Keycode int64
+	// PhysicalKeycode is the key position on a US QWERTY keyboard (physical_scancode in Godot 3)
+	PhysicalKeycode int64
+	Unicode         int64
+	// Key is the name of the keycode, or of the physical keycode if the keycode is 0, e.g. "W" or "Escape"
+	Key string
+	Object
+}
+
+// Binding returns the key with its modifiers, e.g. "Ctrl+W" or "W (Physical)" for physical keys
+func (e *InputEventKey) Binding() string {
+	if e.Keycode == 0 && e.PhysicalKeycode != 0 {
+		return fmt.Sprintf("%s%s (Physical)", e.prefix(), e.Key)
+	}
+	return e.prefix() + e.Key
+}
+
+// InputEventMouseButton is a mouse button event
+type InputEventMouseButton struct {
+	Device int64
+	InputModifiers
+	// ButtonIndex is the mouse button, starting with 1 for the left button
+	ButtonIndex int64
+	DoubleClick bool
+	Object
+}
+
+var mouseButtonNames = map[int64]string{
+	1: "Left Mouse Button",
+	2: "Right Mouse Button",
+	3: "Middle Mouse Button",
+	4: "Mouse Wheel Up",
+	5: "Mouse Wheel Down",
+	6: "Mouse Wheel Left",
+	7: "Mouse Wheel Right",
+	8: "Mouse Thumb Button 1",
+	9: "Mouse Thumb Button 2",
+}
+
+// Binding returns the button with its modifiers, e.g. "Shift+Left Mouse Button"
+func (e *InputEventMouseButton) Binding() string {
+	name, ok := mouseButtonNames[e.ButtonIndex]
+	if !ok {
+		name = fmt.Sprintf("Mouse Button %d", e.ButtonIndex)
+	}
+	if e.DoubleClick {
+		name = "Double Click " + name
+	}
+	return e.prefix() + name
+}
+
+// InputEventJoypadButton is a gamepad button event, the button indices differ between Godot 3 and 4
+type InputEventJoypadButton struct {
+	Device      int64
+	ButtonIndex int64
+	Pressure    float64
+	Object
+}
+
+// Binding returns the button, e.g. "Joypad Button 0"
+func (e *InputEventJoypadButton) Binding() string {
+	return fmt.Sprintf("Joypad Button %d", e.ButtonIndex)
+}
+
+// InputEventJoypadMotion is a gamepad axis event like moving a stick
+type InputEventJoypadMotion struct {
+	Device int64
+	Axis   int64
+	// AxisValue is the direction of the axis, -1 or 1
+	AxisValue float64
+	Object
+}
+
+// Binding returns the axis and its direction, e.g. "Joypad Axis 1-"
+func (e *InputEventJoypadMotion) Binding() string {
+	direction := "+"
+	if e.AxisValue < 0 {
+		direction = "-"
+	}
+	return fmt.Sprintf("Joypad Axis %d%s", e.Axis, direction)
+}
+
+// InputEventObject is an event of a class without typed model, e.g. InputEventAction or InputEventMIDI
+type InputEventObject struct {
+	Object
+}
+
+// Binding returns the class of the event, for unknown events the binding can't be determined
+func (e *InputEventObject) Binding() string {
+	return e.Class
+}
+
+// Action returns the action with the given name, nil if there is none
+func (m *InputMap) Action(name string) *InputAction {
+	for _, action := range m.Actions {
+		if action.Name == name {
+			return action
+		}
+	}
+	return nil
+}
+
+// Conflicts returns the bindings which trigger more than one action with the names of these actions, e.g.
+// "Space": ["jump", "ui_accept"]. Events of unknown classes are ignored.
+func (m *InputMap) Conflicts() map[string][]string {
+	actions := make(map[string][]string)
+	for _, action := range m.Actions {
+		seen := make(map[string]bool)
+		for _, event := range action.Events {
+			if _, unknown := event.(*InputEventObject); unknown {
+				continue
+			}
+			binding := event.Binding()
+			if seen[binding] {
+				continue
+			}
+			seen[binding] = true
+			actions[binding] = append(actions[binding], action.Name)
+		}
+	}
+
+	conflicts := make(map[string][]string)
+	for binding, names := range actions {
+		if len(names) > 1 {
+			conflicts[binding] = names
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,36 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputEventBinding(t *testing.T) {
+	key := &InputEventKey{InputModifiers: InputModifiers{Ctrl: true, Shift: true}, Keycode: 83, Key: "S"}
+	assert.Equal(t, "Ctrl+Shift+S", key.Binding())
+
+	physical := &InputEventKey{PhysicalKeycode: 87, Key: "W"}
+	assert.Equal(t, "W (Physical)", physical.Binding())
+
+	mouse := &InputEventMouseButton{InputModifiers: InputModifiers{Alt: true}, ButtonIndex: 2, DoubleClick: true}
+	assert.Equal(t, "Alt+Double Click Right Mouse Button", mouse.Binding())
+	assert.Equal(t, "Mouse Button 12", (&InputEventMouseButton{ButtonIndex: 12}).Binding())
+
+	assert.Equal(t, "Joypad Axis 1-", (&InputEventJoypadMotion{Axis: 1, AxisValue: -1}).Binding())
+	assert.Equal(t, "InputEventAction", (&InputEventObject{Object: Object{Class: "InputEventAction"}}).Binding())
+}
+
+func TestInputMapConflicts(t *testing.T) {
+	space := &InputEventKey{Keycode: 32, Key: "Space"}
+	inputMap := &InputMap{Actions: []*InputAction{
+		{Name: "jump", Events: []InputEvent{space, &InputEventJoypadButton{ButtonIndex: 0}}},
+		{Name: "ui_accept", Events: []InputEvent{space, space}},
+		{Name: "fire", Events: []InputEvent{&InputEventJoypadButton{ButtonIndex: 1}}},
+		{Name: "custom", Events: []InputEvent{&InputEventObject{}, &InputEventObject{}}},
+	}}
+
+	assert.Equal(t, map[string][]string{"Space": {"jump", "ui_accept"}}, inputMap.Conflicts())
+	assert.Equal(t, "fire", inputMap.Action("fire").Name)
+	assert.Nil(t, inputMap.Action("missing"))
+}
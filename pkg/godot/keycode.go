@@ -0,0 +1,152 @@
+package godot
+
+import "fmt"
+
+const (
+	// keySpecialGodot3 is the flag of non printable keys in Godot 3 (SPKEY), the key is stored in the lower bits
+	keySpecialGodot3 = 1 << 24
+	// keySpecialGodot4 is the flag of non printable keys in Godot 4 (Key::SPECIAL)
+	keySpecialGodot4 = 1 << 22
+	// keyCodeMaskGodot3 and keyCodeMaskGodot4 remove the modifier flags of a keycode
+	keyCodeMaskGodot3 = 1<<25 - 1
+	keyCodeMaskGodot4 = 1<<23 - 1
+)
+
+// printableKeyNames are the names of printable keys which aren't shown as their character, these are the same in Godot
+// 3 and 4
+var printableKeyNames = map[int64]string{
+	' ':  "Space",
+	'!':  "Exclam",
+	'"':  "QuoteDbl",
+	'#':  "NumberSign",
+	'$':  "Dollar",
+	'%':  "Percent",
+	'&':  "Ampersand",
+	'\'': "Apostrophe",
+	'(':  "ParenLeft",
+	')':  "ParenRight",
+	'*':  "Asterisk",
+	'+':  "Plus",
+	',':  "Comma",
+	'-':  "Minus",
+	'.':  "Period",
+	'/':  "Slash",
+	':':  "Colon",
+	';':  "Semicolon",
+	'<':  "Less",
+	'=':  "Equal",
+	'>':  "Greater",
+	'?':  "Question",
+	'@':  "At",
+	'[':  "BracketLeft",
+	'\\': "BackSlash",
+	']':  "BracketRight",
+	'^':  "AsciiCircum",
+	'_':  "UnderScore",
+	'`':  "QuoteLeft",
+	'{':  "BraceLeft",
+	'|':  "Bar",
+	'}':  "BraceRight",
+	'~':  "AsciiTilde",
+}
+
+// specialKeyNames are the names of the non printable keys by their value without the special flag
+var specialKeyNames = map[int64]string{
+	0x01: "Escape",
+	0x02: "Tab",
+	0x03: "BackTab",
+	0x07: "Insert",
+	0x08: "Delete",
+	0x09: "Pause",
+	0x0A: "Print",
+	0x0B: "SysReq",
+	0x0C: "Clear",
+	0x0D: "Home",
+	0x0E: "End",
+	0x0F: "Left",
+	0x10: "Up",
+	0x11: "Right",
+	0x12: "Down",
+	0x13: "PageUp",
+	0x14: "PageDown",
+	0x15: "Shift",
+	0x17: "Meta",
+	0x18: "Alt",
+	0x19: "CapsLock",
+	0x1A: "NumLock",
+	0x1B: "ScrollLock",
+	0x81: "Kp Multiply",
+	0x82: "Kp Divide",
+	0x83: "Kp Subtract",
+	0x84: "Kp Period",
+	0x85: "Kp Add",
+}
+
+// specialKeyNamesGodot3 and specialKeyNamesGodot4 contain the keys which were renamed in Godot 4
+var specialKeyNamesGodot3 = map[int64]string{
+	0x04: "BackSpace",
+	0x05: "Return",
+	0x06: "Enter",
+	0x16: "Control",
+}
+
+var specialKeyNamesGodot4 = map[int64]string{
+	0x04: "Backspace",
+	0x05: "Enter",
+	0x06: "Kp Enter",
+	0x16: "Ctrl",
+}
+
+const (
+	keyF1  = 0x1C
+	keyKp0 = 0x86
+	keyKp9 = 0x8F
+)
+
+// keycodeFormat describes how the keycodes of a Godot version are encoded
+type keycodeFormat struct {
+	special      int64
+	mask         int64
+	functionKeys int64
+	renamed      map[int64]string
+}
+
+var (
+	keycodeFormatGodot3 = keycodeFormat{keySpecialGodot3, keyCodeMaskGodot3, 16, specialKeyNamesGodot3}
+	keycodeFormatGodot4 = keycodeFormat{keySpecialGodot4, keyCodeMaskGodot4, 35, specialKeyNamesGodot4}
+)
+
+// KeycodeName returns the name Godot uses for a keycode (scancode in Godot 3), e.g. "W", "Space" or "Escape". The
+// values of non printable keys differ between the format versions, modifier flags are ignored.
+func KeycodeName(keycode int64, format int64) string {
+	f := keycodeFormatGodot4
+	if format < FormatVersionGodot4 {
+		f = keycodeFormatGodot3
+	}
+	keycode &= f.mask
+
+	if keycode&f.special == 0 {
+		if name, ok := printableKeyNames[keycode]; ok {
+			return name
+		}
+		if keycode > ' ' && keycode < 0x7F {
+			return string(rune(keycode))
+		}
+		return fmt.Sprintf("Keycode(%d)", keycode)
+	}
+
+	key := keycode &^ f.special
+	if name, ok := f.renamed[key]; ok {
+		return name
+	}
+	if name, ok := specialKeyNames[key]; ok {
+		return name
+	}
+	if key >= keyF1 && key < keyF1+f.functionKeys {
+		return fmt.Sprintf("F%d", key-keyF1+1)
+	}
+	if key >= keyKp0 && key <= keyKp9 {
+		return fmt.Sprintf("Kp %d", key-keyKp0)
+	}
+	return fmt.Sprintf("Keycode(%d)", keycode)
+}
@@ -0,0 +1,33 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeycodeName(t *testing.T) {
+	assert.Equal(t, "W", KeycodeName(87, FormatVersionGodot3))
+	assert.Equal(t, "5", KeycodeName('5', FormatVersionGodot4))
+	assert.Equal(t, "Space", KeycodeName(32, FormatVersionGodot4))
+	assert.Equal(t, "BracketLeft", KeycodeName('[', FormatVersionGodot3))
+	assert.Equal(t, "Keycode(0)", KeycodeName(0, FormatVersionGodot4))
+}
+
+func TestKeycodeNameOfSpecialKeys(t *testing.T) {
+	assert.Equal(t, "Escape", KeycodeName(16777217, FormatVersionGodot3))
+	assert.Equal(t, "Escape", KeycodeName(4194305, FormatVersionGodot4))
+	assert.Equal(t, "Up", KeycodeName(16777232, FormatVersionGodot3))
+	assert.Equal(t, "Return", KeycodeName(16777221, FormatVersionGodot3))
+	assert.Equal(t, "Enter", KeycodeName(4194309, FormatVersionGodot4))
+	assert.Equal(t, "Control", KeycodeName(16777238, FormatVersionGodot3))
+	assert.Equal(t, "Ctrl", KeycodeName(4194326, FormatVersionGodot4))
+	assert.Equal(t, "F12", KeycodeName(16777255, FormatVersionGodot3))
+	assert.Equal(t, "F20", KeycodeName(4194351, FormatVersionGodot4))
+	assert.Equal(t, "Kp 7", KeycodeName(4194445, FormatVersionGodot4))
+}
+
+func TestKeycodeNameIgnoresModifiers(t *testing.T) {
+	// KEY_MASK_CTRL | KEY_S in Godot 3
+	assert.Equal(t, "S", KeycodeName(1<<27|83, FormatVersionGodot3))
+}
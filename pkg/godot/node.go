@@ -5,42 +5,247 @@ import (
 	"strings"
 )
 
+const (
+	// uniqueNamePrefix marks names of nodes which are unique in their scene, e.g. %HealthBar
+	uniqueNamePrefix = "%"
+	// uniqueNameField is the field of nodes which can be accessed by their unique name
+	uniqueNameField = "unique_name_in_owner"
+	// sceneTreeRootName is the name of the root of the scene tree in absolute paths like /root/Main
+	sceneTreeRootName = "root"
+)
+
 // Node is Godots central building block. Godot stores scenes as a tree of nodes.
 type Node struct {
 	Name     string
 	Type     string
 	Instance Type
+	Groups   []string
 	Fields   map[string]interface{}
-	Children map[string]*Node
-	Parent   *Node
+	// InheritedIndex is the index attribute of nodes added to an inherited scene, it places the node between the
+	// children of the base scene. Nil if the node has no index attribute.
+	InheritedIndex *int
+	Parent         *Node
 	MetaData
+
+	// children in the order of the file, which is the draw and process order in Godot
+	children []*Node
+	// childrenByName is a lookup of children by their name, it is rebuilt when a child has been renamed
+	childrenByName map[string]*Node
+}
+
+// Children returns the child nodes in order
+func (n *Node) Children() []*Node {
+	children := make([]*Node, len(n.children))
+	copy(children, n.children)
+	return children
+}
+
+// ChildCount returns the number of child nodes
+func (n *Node) ChildCount() int {
+	return len(n.children)
+}
+
+// ChildAt returns the child node at the given index
+func (n *Node) ChildAt(index int) (*Node, error) {
+	if index < 0 || index >= len(n.children) {
+		return nil, fmt.Errorf("child index out of range: %d", index)
+	}
+	return n.children[index], nil
+}
+
+// Child returns the direct child node with the given name
+func (n *Node) Child(name string) (*Node, bool) {
+	if child, ok := n.childrenByName[name]; ok && child.Name == name && child.Parent == n {
+		return child, true
+	}
+
+	// a child might have been renamed since the lookup was built
+	for _, child := range n.children {
+		if child.Name == name {
+			n.rebuildChildLookup()
+			return child, true
+		}
+	}
+
+	return nil, false
+}
+
+// Index returns the position of the node among its siblings, or -1 if it has no parent
+func (n *Node) Index() int {
+	if n.Parent == nil {
+		return -1
+	}
+	return n.Parent.indexOf(n)
+}
+
+// MoveChild moves a child node to the given index, shifting its siblings like Godots Node.move_child
+func (n *Node) MoveChild(child *Node, index int) error {
+	from := n.indexOf(child)
+	if from == -1 {
+		return fmt.Errorf("node %s is not a child of %s", child.Name, n.Name)
+	}
+	if index < 0 || index >= len(n.children) {
+		return fmt.Errorf("child index out of range: %d", index)
+	}
+
+	n.children = append(n.children[:from], n.children[from+1:]...)
+	n.children = append(n.children[:index], append([]*Node{child}, n.children[index:]...)...)
+	return nil
 }
 
-// AddNode adds a node as the child of the current node
+// AddNode adds a node as the last child of the current node, an existing child with the same name is replaced.
+// If the node is already attached to another parent it will be moved.
 func (n *Node) AddNode(node *Node) {
+	if node.Parent != nil {
+		node.Parent.detach(node)
+	}
+
+	if existing, ok := n.Child(node.Name); ok {
+		n.children[n.indexOf(existing)] = node
+		existing.Parent = nil
+	} else {
+		n.children = append(n.children, node)
+	}
+
 	node.Parent = n
-	n.Children[node.Name] = node
+	if n.childrenByName == nil {
+		n.childrenByName = make(map[string]*Node)
+	}
+	n.childrenByName[node.Name] = node
+}
+
+// Clone returns a deep copy of the node and its children, the copy has no parent
+func (n *Node) Clone() *Node {
+	clone := &Node{
+		Name:     n.Name,
+		Type:     n.Type,
+		Instance: n.Instance,
+		MetaData: n.MetaData,
+	}
+
+	if n.Groups != nil {
+		clone.Groups = append([]string{}, n.Groups...)
+	}
+
+	if n.Fields != nil {
+		clone.Fields = make(map[string]interface{}, len(n.Fields))
+		for key, value := range n.Fields {
+			clone.Fields[key] = value
+		}
+	}
+
+	if n.InheritedIndex != nil {
+		index := *n.InheritedIndex
+		clone.InheritedIndex = &index
+	}
+
+	for _, child := range n.children {
+		clone.AddNode(child.Clone())
+	}
+
+	return clone
+}
+
+// Path returns the path of the node relative to the root of its tree, "." for the root itself
+func (n *Node) Path() string {
+	if n.Parent == nil {
+		return "."
+	}
+
+	var parts []string
+	for node := n; node.Parent != nil; node = node.Parent {
+		parts = append([]string{node.Name}, parts...)
+	}
+	return strings.Join(parts, "/")
 }
 
-// GetNode retrieves a node for a given path
+// GetNode retrieves a node for a given path like Godots Node.get_node: paths are relative to the node, ".." is the
+// parent, names starting with % are unique names and absolute paths like /root/Main start at the root of the tree. Sub
+// names like :frame are ignored.
 func (n *Node) GetNode(path string) (*Node, error) {
-	if path == "." {
-		return n, nil
+	p := ParseNodePath(path)
+	if len(p.Names) == 0 {
+		return nil, fmt.Errorf("could not get node path: %s", path)
 	}
 
-	parts := strings.Split(path, "/")
-	root := n
-	for _, p := range parts {
-		node, hasChild := root.Children[p]
+	node := n
+	names := p.Names
+	if p.Absolute {
+		if len(names) < 2 || names[0] != sceneTreeRootName || names[1] != n.root().Name {
+			return nil, fmt.Errorf("could not get node path: %s", path)
+		}
+		node = n.root()
+		names = names[2:]
+	}
 
-		if !hasChild {
+	for _, name := range names {
+		next, ok := node.nextNode(name)
+		if !ok {
 			return nil, fmt.Errorf("could not get node path: %s", path)
 		}
+		node = next
+	}
+
+	return node, nil
+}
+
+// nextNode resolves a single name of a node path
+func (n *Node) nextNode(name string) (*Node, bool) {
+	switch {
+	case name == ".":
+		return n, true
+	case name == "..":
+		return n.Parent, n.Parent != nil
+	case isUniqueName(name):
+		// like Godot the nodes owned by the node itself are searched first, then the ones of its owner
+		unique := strings.TrimPrefix(name, uniqueNamePrefix)
+		if n.isOwner() {
+			if node, ok := n.uniqueNode(unique); ok {
+				return node, true
+			}
+		}
+		if owner := n.owner(); owner != nil {
+			if node, ok := owner.uniqueNode(unique); ok {
+				return node, true
+			}
+		}
+		// Godot 3 allowed % in node names
+		return n.Child(name)
+	default:
+		return n.Child(name)
+	}
+}
 
-		root = node
+// GetPathTo returns the relative path from this node to another node of the same tree like Godots Node.get_path_to
+func (n *Node) GetPathTo(node *Node) (NodePath, error) {
+	if n == node {
+		return NodePath{Names: []string{"."}}, nil
 	}
 
-	return root, nil
+	ancestors := make(map[*Node]int)
+	depth := 0
+	for ancestor := n; ancestor != nil; ancestor = ancestor.Parent {
+		ancestors[ancestor] = depth
+		depth++
+	}
+
+	var names []string
+	common := node
+	for ; common != nil; common = common.Parent {
+		if _, ok := ancestors[common]; ok {
+			break
+		}
+		names = append([]string{common.Name}, names...)
+	}
+	if common == nil {
+		return NodePath{}, fmt.Errorf("%s and %s are not part of the same tree", n.Name, node.Name)
+	}
+
+	up := make([]string, ancestors[common])
+	for index := range up {
+		up[index] = ".."
+	}
+	return NodePath{Names: append(up, names...)}, nil
 }
 
 // RemoveNode deletes a child node
@@ -56,6 +261,85 @@ func (n *Node) RemoveNode(path string) error {
 		return fmt.Errorf("can't remove root node, or this node is not attached to anything")
 	}
 
-	delete(parent.Children, node.Name)
+	parent.detach(node)
 	return nil
 }
+
+func (n *Node) detach(child *Node) {
+	index := n.indexOf(child)
+	if index == -1 {
+		return
+	}
+	n.children = append(n.children[:index], n.children[index+1:]...)
+	if n.childrenByName[child.Name] == child {
+		delete(n.childrenByName, child.Name)
+	}
+	child.Parent = nil
+}
+
+func (n *Node) indexOf(child *Node) int {
+	for index, c := range n.children {
+		if c == child {
+			return index
+		}
+	}
+	return -1
+}
+
+func (n *Node) rebuildChildLookup() {
+	n.childrenByName = make(map[string]*Node, len(n.children))
+	for _, child := range n.children {
+		n.childrenByName[child.Name] = child
+	}
+}
+
+// isUniqueName checks if a name of a path refers to a node by its unique name
+func isUniqueName(name string) bool {
+	return strings.HasPrefix(name, uniqueNamePrefix) && len(name) > len(uniqueNamePrefix)
+}
+
+// isUnique checks if the node can be accessed by its unique name
+func (n *Node) isUnique() bool {
+	unique, _ := Unwrap(n.Fields[uniqueNameField]).(bool)
+	return unique
+}
+
+// isOwner checks if the node owns its descendants, which is the case for the root of a scene and instanced scenes
+func (n *Node) isOwner() bool {
+	return n.Parent == nil || n.Instance.Identifier != ""
+}
+
+// owner returns the node owning this node
+func (n *Node) owner() *Node {
+	node := n.Parent
+	for node != nil && !node.isOwner() {
+		node = node.Parent
+	}
+	return node
+}
+
+// uniqueNode returns the node with the unique name owned by this node, the descendants of instanced scenes belong to
+// the instanced scene
+func (n *Node) uniqueNode(name string) (*Node, bool) {
+	for _, child := range n.children {
+		if child.Name == name && child.isUnique() {
+			return child, true
+		}
+		if child.isOwner() {
+			continue
+		}
+		if node, ok := child.uniqueNode(name); ok {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// root returns the root of the tree the node is part of
+func (n *Node) root() *Node {
+	node := n
+	for node.Parent != nil {
+		node = node.Parent
+	}
+	return node
+}
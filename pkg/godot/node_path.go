@@ -0,0 +1,85 @@
+package godot
+
+import "strings"
+
+// nodePathTypeKey is the identifier of node path literals like NodePath("Sprite:frame")
+const nodePathTypeKey = "NodePath"
+
+// NodePath is a path to a node and optionally to a property of it, e.g. "../Player/Sprite:modulate:a"
+type NodePath struct {
+	// Names are the node names of the path, ".." is the parent and names starting with % are unique names
+	Names []string
+	// SubNames are the property names following the node names, e.g. ["modulate", "a"]
+	SubNames []string
+	// Absolute is true for paths starting at the root of the scene tree like /root/Main
+	Absolute bool
+}
+
+// ParseNodePath parses a node path like NodePath("...") does
+func ParseNodePath(path string) NodePath {
+	var p NodePath
+	if strings.HasPrefix(path, "/") {
+		p.Absolute = true
+		path = path[1:]
+	}
+
+	parts := strings.Split(path, ":")
+	for _, name := range strings.Split(parts[0], "/") {
+		if name != "" {
+			p.Names = append(p.Names, name)
+		}
+	}
+	for _, subName := range parts[1:] {
+		if subName != "" {
+			p.SubNames = append(p.SubNames, subName)
+		}
+	}
+	return p
+}
+
+// NodePathOf returns the node path of a NodePath("...") literal or a string
+func NodePathOf(value interface{}) (NodePath, bool) {
+	switch v := value.(type) {
+	case Value:
+		return NodePathOf(v.Value)
+	case NodePath:
+		return v, true
+	case string:
+		return ParseNodePath(v), true
+	case Type:
+		if v.Identifier != nodePathTypeKey || len(v.Parameters) != 1 {
+			return NodePath{}, false
+		}
+		path, ok := Unwrap(v.Parameters[0]).(string)
+		if !ok {
+			return NodePath{}, false
+		}
+		return ParseNodePath(path), true
+	default:
+		return NodePath{}, false
+	}
+}
+
+// String returns the path as it's written in NodePath("...")
+func (p NodePath) String() string {
+	var sb strings.Builder
+	if p.Absolute {
+		sb.WriteString("/")
+	}
+	sb.WriteString(strings.Join(p.Names, "/"))
+	for _, subName := range p.SubNames {
+		sb.WriteString(":")
+		sb.WriteString(subName)
+	}
+	return sb.String()
+}
+
+// IsEmpty checks if the path neither has names nor sub names
+func (p NodePath) IsEmpty() bool {
+	return !p.Absolute && len(p.Names) == 0 && len(p.SubNames) == 0
+}
+
+// Property returns the sub names joined like in the path, e.g. "modulate:a" for "Sprite:modulate:a"
+func (p NodePath) Property() string {
+	return strings.Join(p.SubNames, ":")
+}
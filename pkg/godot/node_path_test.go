@@ -0,0 +1,47 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNodePath(t *testing.T) {
+	p := ParseNodePath("../Player/Sprite:modulate:a")
+	assert.Equal(t, []string{"..", "Player", "Sprite"}, p.Names)
+	assert.Equal(t, []string{"modulate", "a"}, p.SubNames)
+	assert.False(t, p.Absolute)
+	assert.Equal(t, "modulate:a", p.Property())
+	assert.Equal(t, "../Player/Sprite:modulate:a", p.String())
+
+	p = ParseNodePath("/root/Main")
+	assert.True(t, p.Absolute)
+	assert.Equal(t, []string{"root", "Main"}, p.Names)
+	assert.Equal(t, "/root/Main", p.String())
+
+	p = ParseNodePath(":frame")
+	assert.Empty(t, p.Names)
+	assert.Equal(t, []string{"frame"}, p.SubNames)
+	assert.Equal(t, ":frame", p.String())
+
+	assert.Equal(t, "%HealthBar", ParseNodePath("%HealthBar").String())
+	assert.True(t, ParseNodePath("").IsEmpty())
+	assert.False(t, ParseNodePath(".").IsEmpty())
+}
+
+func TestNodePathOf(t *testing.T) {
+	literal := Type{Identifier: "NodePath", Parameters: []interface{}{Value{Value: "Sprite:frame"}}}
+	p, ok := NodePathOf(Value{Value: literal})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Sprite"}, p.Names)
+	assert.Equal(t, []string{"frame"}, p.SubNames)
+
+	p, ok = NodePathOf("Camera2D")
+	assert.True(t, ok)
+	assert.Equal(t, "Camera2D", p.String())
+
+	_, ok = NodePathOf(Type{Identifier: "Vector2", Parameters: []interface{}{1.0, 2.0}})
+	assert.False(t, ok)
+	_, ok = NodePathOf(int64(1))
+	assert.False(t, ok)
+}
@@ -8,21 +8,18 @@ import (
 
 func createPlayerNodeTree() Node {
 	player := Node{
-		Name:     "Player",
-		Type:     "Spatial",
-		Children: make(map[string]*Node),
+		Name: "Player",
+		Type: "Spatial",
 	}
 
 	arm := Node{
-		Name:     "Arm",
-		Type:     "Spatial",
-		Children: make(map[string]*Node),
+		Name: "Arm",
+		Type: "Spatial",
 	}
 
 	hand := Node{
-		Name:     "Hand",
-		Type:     "Spatial",
-		Children: make(map[string]*Node),
+		Name: "Hand",
+		Type: "Spatial",
 	}
 
 	thumb := Node{
@@ -90,37 +87,219 @@ func TestAddNode(t *testing.T) {
 	player := createPlayerNodeTree()
 	hand, err := player.GetNode("Arm/Hand")
 	assert.NoError(t, err)
-	assert.Len(t, hand.Children, 2)
+	assert.Len(t, hand.Children(), 2)
 
 	hand.AddNode(&Node{
 		Name: "Middle Finger",
 		Type: "Spatial",
 	})
-	assert.Len(t, hand.Children, 3)
+	assert.Len(t, hand.Children(), 3)
 
 	middleFinger, err := hand.GetNode("Middle Finger")
 	assert.NoError(t, err)
 	assert.Equal(t, "Middle Finger", middleFinger.Name)
 }
 
+func TestAddNodeKeepsOrder(t *testing.T) {
+	player := createPlayerNodeTree()
+	hand, err := player.GetNode("Arm/Hand")
+	assert.NoError(t, err)
+
+	hand.AddNode(&Node{Name: "Middle Finger"})
+	hand.AddNode(&Node{Name: "Ring Finger"})
+
+	var names []string
+	for _, child := range hand.Children() {
+		names = append(names, child.Name)
+	}
+	assert.Equal(t, []string{"Thumb", "Index Finger", "Middle Finger", "Ring Finger"}, names)
+
+	ringFinger, err := hand.ChildAt(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ring Finger", ringFinger.Name)
+	assert.Equal(t, 3, ringFinger.Index())
+	assert.Equal(t, -1, player.Index())
+
+	_, err = hand.ChildAt(4)
+	assert.Error(t, err)
+}
+
+func TestAddNodeMovesNodeFromPreviousParent(t *testing.T) {
+	player := createPlayerNodeTree()
+	thumb, err := player.GetNode("Arm/Hand/Thumb")
+	assert.NoError(t, err)
+
+	player.AddNode(thumb)
+	assert.Equal(t, "Player", thumb.Parent.Name)
+
+	_, err = player.GetNode("Arm/Hand/Thumb")
+	assert.Error(t, err)
+	_, err = player.GetNode("Thumb")
+	assert.NoError(t, err)
+}
+
+func TestMoveChild(t *testing.T) {
+	player := createPlayerNodeTree()
+	hand, err := player.GetNode("Arm/Hand")
+	assert.NoError(t, err)
+	hand.AddNode(&Node{Name: "Middle Finger"})
+
+	middleFinger, _ := hand.Child("Middle Finger")
+	assert.NoError(t, hand.MoveChild(middleFinger, 0))
+	assert.Equal(t, 0, middleFinger.Index())
+
+	thumb, _ := hand.Child("Thumb")
+	assert.Equal(t, 1, thumb.Index())
+
+	assert.Error(t, hand.MoveChild(middleFinger, 3))
+	assert.Error(t, hand.MoveChild(&player, 0))
+}
+
+func TestGetNodeAfterRename(t *testing.T) {
+	player := createPlayerNodeTree()
+	thumb, err := player.GetNode("Arm/Hand/Thumb")
+	assert.NoError(t, err)
+
+	thumb.Name = "Pollex"
+
+	_, err = player.GetNode("Arm/Hand/Thumb")
+	assert.Error(t, err)
+	node, err := player.GetNode("Arm/Hand/Pollex")
+	assert.NoError(t, err)
+	assert.Equal(t, thumb, node)
+}
+
+func TestNodePath(t *testing.T) {
+	player := createPlayerNodeTree()
+	thumb, err := player.GetNode("Arm/Hand/Thumb")
+	assert.NoError(t, err)
+	assert.Equal(t, "Arm/Hand/Thumb", thumb.Path())
+	assert.Equal(t, ".", player.Path())
+}
+
+// createUniqueNodeTree creates Main/HUD/HealthBar with the unique HealthBar and an instanced Enemy which has its own
+// unique HealthBar
+func createUniqueNodeTree() (main, hud, healthBar, enemy, enemyHealthBar *Node) {
+	unique := map[string]interface{}{"unique_name_in_owner": Value{Value: true}}
+
+	main = &Node{Name: "Main", Type: "Node2D"}
+	hud = &Node{Name: "HUD", Type: "CanvasLayer"}
+	healthBar = &Node{Name: "HealthBar", Type: "ProgressBar", Fields: unique}
+	enemy = &Node{Name: "Enemy", Instance: Type{Identifier: "ExtResource", Parameters: []interface{}{int64(1)}}}
+	enemyHealthBar = &Node{Name: "HealthBar", Type: "ProgressBar", Fields: unique}
+
+	main.AddNode(hud)
+	hud.AddNode(healthBar)
+	main.AddNode(enemy)
+	enemy.AddNode(enemyHealthBar)
+	return main, hud, healthBar, enemy, enemyHealthBar
+}
+
+func TestGetNodeRelativePaths(t *testing.T) {
+	main, hud, healthBar, enemy, _ := createUniqueNodeTree()
+
+	node, err := healthBar.GetNode("..")
+	assert.NoError(t, err)
+	assert.Same(t, hud, node)
+
+	node, err = healthBar.GetNode("../../Enemy")
+	assert.NoError(t, err)
+	assert.Same(t, enemy, node)
+
+	node, err = hud.GetNode("./HealthBar:value")
+	assert.NoError(t, err)
+	assert.Same(t, healthBar, node)
+
+	_, err = main.GetNode("..")
+	assert.EqualError(t, err, "could not get node path: ..")
+	_, err = main.GetNode("")
+	assert.Error(t, err)
+}
+
+func TestGetNodeAbsolutePaths(t *testing.T) {
+	main, _, healthBar, _, _ := createUniqueNodeTree()
+
+	node, err := healthBar.GetNode("/root/Main")
+	assert.NoError(t, err)
+	assert.Same(t, main, node)
+
+	node, err = main.GetNode("/root/Main/HUD/HealthBar")
+	assert.NoError(t, err)
+	assert.Same(t, healthBar, node)
+
+	_, err = main.GetNode("/root/Other")
+	assert.Error(t, err)
+	_, err = main.GetNode("/Main")
+	assert.Error(t, err)
+}
+
+func TestGetNodeUniqueNames(t *testing.T) {
+	main, hud, healthBar, enemy, enemyHealthBar := createUniqueNodeTree()
+
+	for _, from := range []*Node{main, hud, healthBar} {
+		node, err := from.GetNode("%HealthBar")
+		assert.NoError(t, err)
+		assert.Same(t, healthBar, node)
+	}
+
+	// the instanced scene owns its unique nodes
+	node, err := enemy.GetNode("%HealthBar")
+	assert.NoError(t, err)
+	assert.Same(t, enemyHealthBar, node)
+
+	node, err = main.GetNode("%HealthBar/..")
+	assert.NoError(t, err)
+	assert.Same(t, hud, node)
+
+	_, err = main.GetNode("%HUD")
+	assert.Error(t, err)
+}
+
+func TestGetPathTo(t *testing.T) {
+	main, hud, healthBar, enemy, enemyHealthBar := createUniqueNodeTree()
+
+	path, err := healthBar.GetPathTo(enemyHealthBar)
+	assert.NoError(t, err)
+	assert.Equal(t, "../../Enemy/HealthBar", path.String())
+
+	path, err = main.GetPathTo(healthBar)
+	assert.NoError(t, err)
+	assert.Equal(t, "HUD/HealthBar", path.String())
+
+	path, err = healthBar.GetPathTo(hud)
+	assert.NoError(t, err)
+	assert.Equal(t, "..", path.String())
+
+	path, err = enemy.GetPathTo(enemy)
+	assert.NoError(t, err)
+	assert.Equal(t, ".", path.String())
+
+	node, err := healthBar.GetNode(path.String())
+	assert.NoError(t, err)
+	assert.Same(t, healthBar, node)
+
+	_, err = main.GetPathTo(&Node{Name: "Other"})
+	assert.EqualError(t, err, "Main and Other are not part of the same tree")
+}
+
 func TestRemoveNodeWithDeepPath(t *testing.T) {
 	player := createPlayerNodeTree()
 	hand, err := player.GetNode("Arm/Hand")
 	assert.NoError(t, err)
-	assert.Len(t, hand.Children, 2)
+	assert.Len(t, hand.Children(), 2)
 	err = player.RemoveNode("Arm/Hand/Thumb")
 	assert.NoError(t, err)
-	assert.Len(t, hand.Children, 1)
+	assert.Len(t, hand.Children(), 1)
 }
 
 func TestRemoveNodeWithDirectChild(t *testing.T) {
 	player := createPlayerNodeTree()
 	hand, err := player.GetNode("Arm/Hand")
 	assert.NoError(t, err)
-	assert.Len(t, hand.Children, 2)
+	assert.Len(t, hand.Children(), 2)
 	err = hand.RemoveNode("Thumb")
 	assert.NoError(t, err)
-	assert.Len(t, hand.Children, 1)
+	assert.Len(t, hand.Children(), 1)
 }
 
 func TestRemoveNodeWithChildren(t *testing.T) {
@@ -129,7 +308,7 @@ func TestRemoveNodeWithChildren(t *testing.T) {
 	assert.NoError(t, err)
 	arm, err := player.GetNode("Arm")
 	assert.NoError(t, err)
-	assert.Len(t, arm.Children, 0)
+	assert.Len(t, arm.Children(), 0)
 }
 
 func TestRemoveNodeWithSelfReturnsError(t *testing.T) {
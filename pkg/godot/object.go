@@ -0,0 +1,17 @@
+package godot
+
+// Object is an inline object like Object(InputEventKey,"device":0,"alt":false), Godot uses these for instance to store
+// the events of input actions in project.godot
+type Object struct {
+	// Class is the class of the object, e.g. "InputEventKey"
+	Class string
+	// Properties of the object, the values keep their Value wrappers which are used to write them in their original
+	// order
+	Properties map[string]interface{}
+	MetaData
+}
+
+// Property returns a property without its Value wrappers, nil if the object doesn't have it
+func (o Object) Property(name string) interface{} {
+	return Unwrap(o.Properties[name])
+}
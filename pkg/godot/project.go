@@ -24,3 +24,93 @@ type Project struct {
 	Fields      map[string]interface{}
 	MetaData
 }
+
+// ProjectSections are the sections of project.godot which have their own field in Project, other sections are stored
+// in Project.Rest
+var ProjectSections = []string{
+	"android",
+	"audio",
+	"application",
+	"autoload",
+	"compression",
+	"display",
+	"editor",
+	"filesystem",
+	"gui",
+	"input",
+	"layernames",
+	"locale",
+	"logging",
+	"memory",
+	"network",
+	"node",
+	"rendering",
+	"world",
+}
+
+// Section returns the fields of a section by its name like "display", including the sections stored in Rest. Returns
+// nil if the project doesn't have the section.
+func (p *Project) Section(name string) map[string]interface{} {
+	switch name {
+	case "android":
+		return p.Android
+	case "audio":
+		return p.Audio
+	case "application":
+		return p.Application
+	case "autoload":
+		return p.Autoload
+	case "compression":
+		return p.Compression
+	case "display":
+		return p.Display
+	case "editor":
+		return p.Editor
+	case "filesystem":
+		return p.Filesystem
+	case "gui":
+		return p.GUI
+	case "input":
+		return p.Input
+	case "layernames":
+		return p.LayerNames
+	case "locale":
+		return p.Locale
+	case "logging":
+		return p.Logging
+	case "memory":
+		return p.Memory
+	case "network":
+		return p.Network
+	case "node":
+		return p.Node
+	case "rendering":
+		return p.Rendering
+	case "world":
+		return p.World
+	default:
+		return p.Rest[name]
+	}
+}
+
+// Settings returns all settings of the project by their path, e.g. "display/window/size/width"
+func (p *Project) Settings() *Settings {
+	settings := &Settings{values: make(map[string]interface{})}
+
+	for key, value := range p.Fields {
+		settings.values[key] = value
+	}
+
+	sections := append([]string(nil), ProjectSections...)
+	for name := range p.Rest {
+		sections = append(sections, name)
+	}
+
+	for _, name := range sections {
+		for key, value := range p.Section(name) {
+			settings.values[name+"/"+key] = value
+		}
+	}
+
+	return settings
+}
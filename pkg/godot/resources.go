@@ -4,10 +4,14 @@ package godot
 type Resource struct {
 	// Type determines the resource type
 	Type string
+	// Format is the version of the file format this resource was parsed from
+	Format int64
+	// UID is the unique identifier Godot 4 assigns to every resource
+	UID string
 	// ExtResources is a map of external resources, key is their ID
-	ExtResources map[int64]*ExtResource
+	ExtResources map[string]*ExtResource
 	// SubResources is a map of internal resources, key is their ID
-	SubResources map[int64]*SubResource
+	SubResources map[string]*SubResource
 	// Fields contains the fields attached to the resource
 	Fields map[string]interface{}
 	// MetaData contains extra data like the lexer position
@@ -19,7 +23,9 @@ type Resource struct {
 type ExtResource struct {
 	Path string
 	Type string
-	ID   int64
+	// ID is an integer in Godot 3 files and a string like "1_x7k2p" in Godot 4 files
+	ID  string
+	UID string
 	MetaData
 }
 
@@ -27,7 +33,7 @@ type ExtResource struct {
 // Documentation: https://docs.godotengine.org/en/stable/development/file_formats/tscn.html#internal-resources
 type SubResource struct {
 	Type   string
-	ID     int64
+	ID     string
 	Fields map[string]interface{}
 	MetaData
 }
@@ -2,10 +2,14 @@ package godot
 
 // Scene is the data representation of a Godot TSCN Scene, which contains resources and a node tree
 type Scene struct {
+	// Format is the version of the file format this scene was parsed from
+	Format int64
+	// UID is the unique identifier Godot 4 assigns to every scene
+	UID string
 	// ExtResources is a map of external resources, key is their ID
-	ExtResources map[int64]*ExtResource
+	ExtResources map[string]*ExtResource
 	// SubResources is a map of internal resources, key is their ID
-	SubResources map[int64]*SubResource
+	SubResources map[string]*SubResource
 	// Node is the root node of the scene tree
 	*Node
 	// Editables is a list of editable scenes
@@ -15,3 +19,8 @@ type Scene struct {
 	// MetaData contains extra data like the lexer position
 	MetaData
 }
+
+// IsInherited checks if the scene inherits from another scene, which is the case if its root node instances a scene
+func (s *Scene) IsInherited() bool {
+	return s.Node != nil && s.Node.Instance.Identifier != ""
+}
@@ -0,0 +1,183 @@
+package godot
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Settings are project settings by their path like Godots ProjectSettings, e.g. "display/window/size/width". Paths
+// with a feature tag suffix like "rendering/quality/driver/driver_name.mobile" override the setting without suffix for
+// exports with that feature, see WithFeatures.
+type Settings struct {
+	values map[string]interface{}
+}
+
+// Paths returns the paths of all settings sorted by name
+func (s *Settings) Paths() []string {
+	paths := make([]string, 0, len(s.values))
+	for path := range s.values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Has checks if the setting exists
+func (s *Settings) Has(path string) bool {
+	_, ok := s.values[path]
+	return ok
+}
+
+// Get returns a setting without its Value wrappers
+func (s *Settings) Get(path string) (interface{}, bool) {
+	value, ok := s.values[path]
+	if !ok {
+		return nil, false
+	}
+	return Unwrap(value), true
+}
+
+// Field returns a setting as it was parsed, which includes the Value wrappers and their positions
+func (s *Settings) Field(path string) (interface{}, bool) {
+	value, ok := s.values[path]
+	return value, ok
+}
+
+// GetString returns a string setting, the default value is returned if the setting doesn't exist or isn't a string
+func (s *Settings) GetString(path string, defaultValue string) string {
+	value, _ := s.Get(path)
+	switch v := value.(type) {
+	case string:
+		return v
+	case StringName:
+		return string(v)
+	default:
+		return defaultValue
+	}
+}
+
+// GetInt returns an integer setting, the default value is returned if the setting doesn't exist or isn't an integer
+func (s *Settings) GetInt(path string, defaultValue int64) int64 {
+	value, _ := s.Get(path)
+	if i, ok := value.(int64); ok {
+		return i
+	}
+	return defaultValue
+}
+
+// GetFloat returns a number setting, integers are converted. The default value is returned if the setting doesn't
+// exist or isn't a number.
+func (s *Settings) GetFloat(path string, defaultValue float64) float64 {
+	value, _ := s.Get(path)
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	default:
+		return defaultValue
+	}
+}
+
+// GetBool returns a boolean setting, the default value is returned if the setting doesn't exist or isn't a boolean
+func (s *Settings) GetBool(path string, defaultValue bool) bool {
+	value, _ := s.Get(path)
+	if b, ok := value.(bool); ok {
+		return b
+	}
+	return defaultValue
+}
+
+// Overrides returns the feature tags which override a setting, e.g. ["mobile"] if the project contains
+// "rendering/quality/driver/driver_name.mobile"
+func (s *Settings) Overrides(path string) []string {
+	var features []string
+	for _, override := range s.Paths() {
+		base, tags, ok := splitFeatureOverride(override)
+		if ok && base == path {
+			features = append(features, tags...)
+		}
+	}
+	return features
+}
+
+// WithFeatures returns the effective settings of an export with the given feature tags like ["mobile", "release"].
+// Overrides apply if one of their tags is in the feature set, if multiple overrides apply the last one in the file
+// wins. The result doesn't contain the overrides themselves.
+func (s *Settings) WithFeatures(features []string) *Settings {
+	enabled := make(map[string]bool, len(features))
+	for _, feature := range features {
+		enabled[feature] = true
+	}
+
+	type override struct {
+		base  string
+		value interface{}
+	}
+
+	result := &Settings{values: make(map[string]interface{}, len(s.values))}
+	var overrides []override
+
+	for path, value := range s.values {
+		base, tags, ok := splitFeatureOverride(path)
+		if !ok {
+			result.values[path] = value
+			continue
+		}
+		for _, tag := range tags {
+			if enabled[tag] {
+				overrides = append(overrides, override{base, value})
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(overrides, func(i, j int) bool {
+		a, b := settingPosition(overrides[i].value), settingPosition(overrides[j].value)
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return overrides[i].base < overrides[j].base
+	})
+
+	for _, o := range overrides {
+		result.values[o.base] = o.value
+	}
+
+	return result
+}
+
+// splitFeatureOverride splits a path like "application/config/name.mobile.release" into the overridden path and its
+// feature tags, returns false for regular settings
+func splitFeatureOverride(path string) (string, []string, bool) {
+	name := path[strings.LastIndex(path, "/")+1:]
+	index := strings.Index(name, ".")
+	if index <= 0 {
+		return "", nil, false
+	}
+
+	base := path[:len(path)-len(name)+index]
+	var tags []string
+	for _, tag := range strings.Split(name[index+1:], ".") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return base, tags, len(tags) > 0
+}
+
+// settingPosition returns the position of a setting, the zero position if it was added after parsing
+func settingPosition(value interface{}) lexer.Position {
+	switch v := value.(type) {
+	case Value:
+		return v.LexerPosition
+	case Type:
+		return v.LexerPosition
+	case Object:
+		return v.LexerPosition
+	default:
+		return lexer.Position{}
+	}
+}
@@ -0,0 +1,90 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSettingsProject() *Project {
+	at := func(line int, value interface{}) Value {
+		return Value{Value: value, MetaData: MetaData{LexerPosition: lexer.Position{Line: line}}}
+	}
+
+	return &Project{
+		Fields:      map[string]interface{}{"config_version": at(1, int64(4))},
+		Application: map[string]interface{}{"config/name": at(3, "Game"), "config/name.mobile": at(4, "Game Mobile")},
+		Display: map[string]interface{}{
+			"window/size/width":                 at(6, int64(1280)),
+			"window/size/width.mobile":          at(7, int64(720)),
+			"window/size/width.release.android": at(8, int64(640)),
+			"window/vsync/use_vsync":            at(9, false),
+		},
+		Rest: map[string]map[string]interface{}{
+			"debug": {"settings/fps/force_fps": at(11, 60.5)},
+		},
+	}
+}
+
+func TestProjectSection(t *testing.T) {
+	project := newTestSettingsProject()
+	assert.Equal(t, project.Display, project.Section("display"))
+	assert.Equal(t, project.Rest["debug"], project.Section("debug"))
+	assert.Nil(t, project.Section("missing"))
+}
+
+func TestSettingsGet(t *testing.T) {
+	settings := newTestSettingsProject().Settings()
+
+	value, ok := settings.Get("display/window/size/width")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1280), value)
+
+	field, ok := settings.Field("display/window/size/width")
+	assert.True(t, ok)
+	assert.Equal(t, 6, field.(Value).LexerPosition.Line)
+
+	_, ok = settings.Get("display/window/size/height")
+	assert.False(t, ok)
+	assert.True(t, settings.Has("config_version"))
+
+	assert.Equal(t, "Game", settings.GetString("application/config/name", ""))
+	assert.Equal(t, int64(1280), settings.GetInt("display/window/size/width", 0))
+	assert.Equal(t, int64(600), settings.GetInt("display/window/size/height", 600))
+	assert.Equal(t, 1280.0, settings.GetFloat("display/window/size/width", 0))
+	assert.Equal(t, 60.5, settings.GetFloat("debug/settings/fps/force_fps", 0))
+	assert.False(t, settings.GetBool("display/window/vsync/use_vsync", true))
+	assert.Equal(t, "fallback", settings.GetString("display/window/size/width", "fallback"))
+}
+
+func TestSettingsOverrides(t *testing.T) {
+	settings := newTestSettingsProject().Settings()
+	assert.Equal(t, []string{"mobile", "release", "android"}, settings.Overrides("display/window/size/width"))
+	assert.Empty(t, settings.Overrides("display/window/vsync/use_vsync"))
+}
+
+func TestSettingsWithFeatures(t *testing.T) {
+	settings := newTestSettingsProject().Settings()
+
+	mobile := settings.WithFeatures([]string{"mobile"})
+	assert.Equal(t, "Game Mobile", mobile.GetString("application/config/name", ""))
+	assert.Equal(t, int64(720), mobile.GetInt("display/window/size/width", 0))
+	assert.False(t, mobile.Has("display/window/size/width.mobile"))
+
+	// the last override in the file wins
+	android := settings.WithFeatures([]string{"mobile", "android"})
+	assert.Equal(t, int64(640), android.GetInt("display/window/size/width", 0))
+
+	desktop := settings.WithFeatures([]string{"pc", "release"})
+	assert.Equal(t, "Game", desktop.GetString("application/config/name", ""))
+	assert.Equal(t, int64(640), desktop.GetInt("display/window/size/width", 0))
+
+	assert.Equal(t, []string{
+		"application/config/name",
+		"config_version",
+		"debug/settings/fps/force_fps",
+		"display/window/size/width",
+		"display/window/vsync/use_vsync",
+	}, settings.WithFeatures(nil).Paths())
+}
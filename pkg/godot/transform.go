@@ -0,0 +1,225 @@
+package godot
+
+import "math"
+
+// Transform2D is a 2D affine transformation, X and Y are the columns of the basis.
+// Written as Transform2D(x.x, x.y, y.x, y.y, origin.x, origin.y).
+type Transform2D struct {
+	X, Y, Origin Vector2
+}
+
+// Basis is a 3x3 matrix used for rotation and scale, X, Y and Z are its columns.
+// Godot writes the matrix row by row: Basis(x.x, y.x, z.x, x.y, y.y, z.y, x.z, y.z, z.z).
+type Basis struct {
+	X, Y, Z Vector3
+}
+
+// Transform3D is a 3D affine transformation, Godot 3 calls this type Transform.
+// Written as the rows of the basis followed by the origin.
+type Transform3D struct {
+	Basis  Basis
+	Origin Vector3
+}
+
+// Quaternion represents a 3D rotation, Godot 3 calls this type Quat. Written as Quaternion(x, y, z, w).
+type Quaternion struct {
+	X, Y, Z, W float64
+}
+
+// Projection is a 4x4 matrix used for camera projections, X, Y, Z and W are its columns.
+// Godot writes the matrix column by column.
+type Projection struct {
+	X, Y, Z, W Vector4
+}
+
+var (
+	// Transform2DIdentity is the transformation which doesn't change anything
+	Transform2DIdentity = Transform2D{X: Vector2{1, 0}, Y: Vector2{0, 1}}
+	// BasisIdentity is the basis which doesn't change anything
+	BasisIdentity = Basis{X: Vector3{1, 0, 0}, Y: Vector3{0, 1, 0}, Z: Vector3{0, 0, 1}}
+	// Transform3DIdentity is the transformation which doesn't change anything
+	Transform3DIdentity = Transform3D{Basis: BasisIdentity}
+	// QuaternionIdentity is the rotation which doesn't change anything
+	QuaternionIdentity = Quaternion{W: 1}
+	// ProjectionIdentity is the projection which doesn't change anything
+	ProjectionIdentity = Projection{X: Vector4{X: 1}, Y: Vector4{Y: 1}, Z: Vector4{Z: 1}, W: Vector4{W: 1}}
+)
+
+// NewTransform2D creates a transformation from the rotation, scale and position of a Node2D
+func NewTransform2D(rotation float64, scale, origin Vector2) Transform2D {
+	sin, cos := math.Sincos(rotation)
+	return Transform2D{
+		X:      Vector2{cos * scale.X, sin * scale.X},
+		Y:      Vector2{-sin * scale.Y, cos * scale.Y},
+		Origin: origin,
+	}
+}
+
+// BasisXform transforms the vector without applying the origin
+func (t Transform2D) BasisXform(v Vector2) Vector2 {
+	return t.X.Scale(v.X).Add(t.Y.Scale(v.Y))
+}
+
+// Xform transforms the vector, e.g. from local into parent space
+func (t Transform2D) Xform(v Vector2) Vector2 {
+	return t.BasisXform(v).Add(t.Origin)
+}
+
+// Mul combines both transformations, the result applies o first and t second
+func (t Transform2D) Mul(o Transform2D) Transform2D {
+	return Transform2D{
+		X:      t.BasisXform(o.X),
+		Y:      t.BasisXform(o.Y),
+		Origin: t.Xform(o.Origin),
+	}
+}
+
+// AffineInverse returns the inverse transformation
+func (t Transform2D) AffineInverse() Transform2D {
+	det := t.X.X*t.Y.Y - t.X.Y*t.Y.X
+	inverse := Transform2D{
+		X: Vector2{t.Y.Y / det, -t.X.Y / det},
+		Y: Vector2{-t.Y.X / det, t.X.X / det},
+	}
+	inverse.Origin = inverse.BasisXform(t.Origin.Scale(-1))
+	return inverse
+}
+
+// Rotation returns the rotation in radians
+func (t Transform2D) Rotation() float64 {
+	return t.X.Angle()
+}
+
+// Scale returns the scale, a negative determinant is represented by a negative y scale
+func (t Transform2D) Scale() Vector2 {
+	det := t.X.X*t.Y.Y - t.X.Y*t.Y.X
+	return Vector2{t.X.Length(), math.Copysign(t.Y.Length(), det)}
+}
+
+// basisFromRows creates a basis from the rows of the matrix
+func basisFromRows(a, b, c Vector3) Basis {
+	return Basis{
+		X: Vector3{a.X, b.X, c.X},
+		Y: Vector3{a.Y, b.Y, c.Y},
+		Z: Vector3{a.Z, b.Z, c.Z},
+	}
+}
+
+// Rows returns the rows of the matrix
+func (b Basis) Rows() [3]Vector3 {
+	return [3]Vector3{
+		{b.X.X, b.Y.X, b.Z.X},
+		{b.X.Y, b.Y.Y, b.Z.Y},
+		{b.X.Z, b.Y.Z, b.Z.Z},
+	}
+}
+
+// Xform transforms the vector
+func (b Basis) Xform(v Vector3) Vector3 {
+	return b.X.Scale(v.X).Add(b.Y.Scale(v.Y)).Add(b.Z.Scale(v.Z))
+}
+
+// Mul combines both matrices, the result applies o first and b second
+func (b Basis) Mul(o Basis) Basis {
+	return Basis{X: b.Xform(o.X), Y: b.Xform(o.Y), Z: b.Xform(o.Z)}
+}
+
+// Determinant returns the determinant of the matrix
+func (b Basis) Determinant() float64 {
+	return b.X.Dot(b.Y.Cross(b.Z))
+}
+
+// Transposed returns the matrix with rows and columns swapped
+func (b Basis) Transposed() Basis {
+	return basisFromRows(b.X, b.Y, b.Z)
+}
+
+// Inverse returns the inverse matrix
+func (b Basis) Inverse() Basis {
+	det := b.Determinant()
+	return basisFromRows(
+		b.Y.Cross(b.Z).Scale(1/det),
+		b.Z.Cross(b.X).Scale(1/det),
+		b.X.Cross(b.Y).Scale(1/det),
+	)
+}
+
+// Scale returns the length of each column
+func (b Basis) Scale() Vector3 {
+	return Vector3{b.X.Length(), b.Y.Length(), b.Z.Length()}
+}
+
+// Xform transforms the vector, e.g. from local into parent space
+func (t Transform3D) Xform(v Vector3) Vector3 {
+	return t.Basis.Xform(v).Add(t.Origin)
+}
+
+// Mul combines both transformations, the result applies o first and t second
+func (t Transform3D) Mul(o Transform3D) Transform3D {
+	return Transform3D{
+		Basis:  t.Basis.Mul(o.Basis),
+		Origin: t.Xform(o.Origin),
+	}
+}
+
+// AffineInverse returns the inverse transformation
+func (t Transform3D) AffineInverse() Transform3D {
+	basis := t.Basis.Inverse()
+	return Transform3D{
+		Basis:  basis,
+		Origin: basis.Xform(t.Origin.Scale(-1)),
+	}
+}
+
+// Mul combines both rotations, the result applies o first and q second
+func (q Quaternion) Mul(o Quaternion) Quaternion {
+	return Quaternion{
+		X: q.W*o.X + q.X*o.W + q.Y*o.Z - q.Z*o.Y,
+		Y: q.W*o.Y + q.Y*o.W + q.Z*o.X - q.X*o.Z,
+		Z: q.W*o.Z + q.Z*o.W + q.X*o.Y - q.Y*o.X,
+		W: q.W*o.W - q.X*o.X - q.Y*o.Y - q.Z*o.Z,
+	}
+}
+
+// Length returns the length of the quaternion, rotations have a length of 1
+func (q Quaternion) Length() float64 {
+	return math.Sqrt(q.X*q.X + q.Y*q.Y + q.Z*q.Z + q.W*q.W)
+}
+
+// Normalized returns the quaternion scaled to a length of 1
+func (q Quaternion) Normalized() Quaternion {
+	length := q.Length()
+	return Quaternion{q.X / length, q.Y / length, q.Z / length, q.W / length}
+}
+
+// Inverse returns the opposite rotation
+func (q Quaternion) Inverse() Quaternion {
+	lengthSquared := q.X*q.X + q.Y*q.Y + q.Z*q.Z + q.W*q.W
+	return Quaternion{-q.X / lengthSquared, -q.Y / lengthSquared, -q.Z / lengthSquared, q.W / lengthSquared}
+}
+
+// Xform rotates the vector, the quaternion has to be normalized
+func (q Quaternion) Xform(v Vector3) Vector3 {
+	u := Vector3{q.X, q.Y, q.Z}
+	uv := u.Cross(v)
+	return v.Add(uv.Scale(2 * q.W)).Add(u.Cross(uv).Scale(2))
+}
+
+// Basis converts the rotation into a rotation matrix, the quaternion has to be normalized
+func (q Quaternion) Basis() Basis {
+	return Basis{
+		X: q.Xform(Vector3{1, 0, 0}),
+		Y: q.Xform(Vector3{0, 1, 0}),
+		Z: q.Xform(Vector3{0, 0, 1}),
+	}
+}
+
+// Xform transforms the vector
+func (p Projection) Xform(v Vector4) Vector4 {
+	return p.X.Scale(v.X).Add(p.Y.Scale(v.Y)).Add(p.Z.Scale(v.Z)).Add(p.W.Scale(v.W))
+}
+
+// Mul combines both projections, the result applies o first and p second
+func (p Projection) Mul(o Projection) Projection {
+	return Projection{X: p.Xform(o.X), Y: p.Xform(o.Y), Z: p.Xform(o.Z), W: p.Xform(o.W)}
+}
@@ -0,0 +1,80 @@
+package godot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertVector2InDelta(t *testing.T, expected, actual Vector2) {
+	assert.InDelta(t, expected.X, actual.X, 1e-9)
+	assert.InDelta(t, expected.Y, actual.Y, 1e-9)
+}
+
+func assertVector3InDelta(t *testing.T, expected, actual Vector3) {
+	assert.InDelta(t, expected.X, actual.X, 1e-9)
+	assert.InDelta(t, expected.Y, actual.Y, 1e-9)
+	assert.InDelta(t, expected.Z, actual.Z, 1e-9)
+}
+
+func TestTransform2DWorldPosition(t *testing.T) {
+	parent := NewTransform2D(math.Pi/2, Vector2{2, 2}, Vector2{100, 50})
+	child := NewTransform2D(0, Vector2{1, 1}, Vector2{10, 0})
+
+	world := parent.Mul(child)
+	assertVector2InDelta(t, Vector2{100, 70}, world.Origin)
+	assertVector2InDelta(t, Vector2{100, 70}, parent.Xform(Vector2{10, 0}))
+	assert.InDelta(t, math.Pi/2, world.Rotation(), 1e-9)
+	assertVector2InDelta(t, Vector2{2, 2}, world.Scale())
+
+	identity := parent.Mul(parent.AffineInverse())
+	assertVector2InDelta(t, Transform2DIdentity.X, identity.X)
+	assertVector2InDelta(t, Transform2DIdentity.Y, identity.Y)
+	assertVector2InDelta(t, Vector2{}, identity.Origin)
+}
+
+func TestBasis(t *testing.T) {
+	// rotation by 90 degrees around the z axis
+	rotation := Basis{X: Vector3{0, 1, 0}, Y: Vector3{-1, 0, 0}, Z: Vector3{0, 0, 1}}
+
+	assertVector3InDelta(t, Vector3{0, 1, 0}, rotation.Xform(Vector3{1, 0, 0}))
+	assert.Equal(t, 1.0, rotation.Determinant())
+	assert.Equal(t, rotation.Transposed(), rotation.Inverse())
+	assert.Equal(t, BasisIdentity, rotation.Mul(rotation.Inverse()))
+	assert.Equal(t, [3]Vector3{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}}, rotation.Rows())
+	assert.Equal(t, Vector3{1, 1, 1}, rotation.Scale())
+}
+
+func TestTransform3DWorldPosition(t *testing.T) {
+	parent := Transform3D{
+		Basis:  Basis{X: Vector3{0, 1, 0}, Y: Vector3{-1, 0, 0}, Z: Vector3{0, 0, 1}},
+		Origin: Vector3{5, 0, 0},
+	}
+	child := Transform3D{Basis: BasisIdentity, Origin: Vector3{1, 2, 3}}
+
+	assertVector3InDelta(t, Vector3{3, 1, 3}, parent.Mul(child).Origin)
+	assertVector3InDelta(t, Vector3{1, 2, 3}, parent.AffineInverse().Xform(parent.Xform(Vector3{1, 2, 3})))
+	assert.Equal(t, Vector3{1, 2, 3}, Transform3DIdentity.Xform(Vector3{1, 2, 3}))
+}
+
+func TestQuaternion(t *testing.T) {
+	half := math.Sqrt(0.5)
+	// rotation by 90 degrees around the z axis
+	q := Quaternion{0, 0, half, half}
+
+	assertVector3InDelta(t, Vector3{0, 1, 0}, q.Xform(Vector3{1, 0, 0}))
+	assertVector3InDelta(t, Vector3{-1, 0, 0}, q.Mul(q).Xform(Vector3{1, 0, 0}))
+	assertVector3InDelta(t, Vector3{1, 0, 0}, q.Inverse().Xform(q.Xform(Vector3{1, 0, 0})))
+	assert.InDelta(t, 1, q.Length(), 1e-9)
+	assert.InDelta(t, 1, Quaternion{0, 0, 2, 2}.Normalized().Length(), 1e-9)
+	assertVector3InDelta(t, Vector3{-1, 0, 0}, q.Basis().Y)
+	assert.Equal(t, Vector3{1, 2, 3}, QuaternionIdentity.Xform(Vector3{1, 2, 3}))
+}
+
+func TestProjection(t *testing.T) {
+	scale := Projection{X: Vector4{X: 2}, Y: Vector4{Y: 2}, Z: Vector4{Z: 2}, W: Vector4{W: 1}}
+
+	assert.Equal(t, Vector4{2, 4, 6, 1}, scale.Xform(Vector4{1, 2, 3, 1}))
+	assert.Equal(t, scale, scale.Mul(ProjectionIdentity))
+}
@@ -3,12 +3,18 @@ package godot
 // Value is a wrapper for a regular TSCN value which also contains meta data
 type Value struct {
 	Value interface{}
+	// StringNameKeys contains the keys of a dictionary which are written as StringNames like &"name", Godot 4 treats
+	// them as different keys than Strings
+	StringNameKeys map[string]bool
 	MetaData
 }
 
 // Type represents more or less something akin to a struct in TSCN files, for instance a Vector
 type Type struct {
 	Identifier string
+	// TypeParameters contains the element types of typed collections like Array[int]
+	TypeParameters []Type
+	// Parameters is nil for bare identifiers like the class name in Object(InputEventKey, ...)
 	Parameters []interface{}
 	MetaData
 }
@@ -19,3 +25,56 @@ type KeyValuePair struct {
 	Value interface{}
 	MetaData
 }
+
+// StringName is an interned string introduced with Godot 4, written as &"name"
+type StringName string
+
+// Reference points to an external or internal resource, written as ExtResource( 1 ) or SubResource( 1 )
+type Reference struct {
+	// External is true for ExtResource and false for SubResource references
+	External bool
+	ID       string
+	MetaData
+}
+
+// Unwrap removes the Value wrappers of a field value and of all values it contains, e.g. the elements of arrays and
+// dictionaries, the parameters of types or the properties of objects
+func Unwrap(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Value:
+		return Unwrap(v.Value)
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for index, elem := range v {
+			values[index] = Unwrap(elem)
+		}
+		return values
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			m[key] = Unwrap(elem)
+		}
+		return m
+	case Type:
+		if v.Parameters != nil {
+			params := make([]interface{}, len(v.Parameters))
+			for index, param := range v.Parameters {
+				params[index] = Unwrap(param)
+			}
+			v.Parameters = params
+		}
+		return v
+	case KeyValuePair:
+		v.Value = Unwrap(v.Value)
+		return v
+	case Object:
+		properties := make(map[string]interface{}, len(v.Properties))
+		for key, property := range v.Properties {
+			properties[key] = Unwrap(property)
+		}
+		v.Properties = properties
+		return v
+	default:
+		return value
+	}
+}
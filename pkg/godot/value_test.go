@@ -0,0 +1,37 @@
+package godot
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnwrap(t *testing.T) {
+	pos := MetaData{LexerPosition: lexer.Position{Line: 3, Column: 1}}
+
+	assert.Equal(t, int64(2), Unwrap(Value{Value: int64(2), MetaData: pos}))
+	assert.Equal(t, "plain", Unwrap("plain"))
+
+	value := Value{Value: map[string]interface{}{
+		"formats": Value{Value: []interface{}{Value{Value: "s3tc"}, Value{Value: "etc"}}},
+		"size":    Type{Identifier: "Vector2i", Parameters: []interface{}{Value{Value: int64(1)}}, MetaData: pos},
+	}}
+	assert.Equal(t, map[string]interface{}{
+		"formats": []interface{}{"s3tc", "etc"},
+		"size":    Type{Identifier: "Vector2i", Parameters: []interface{}{int64(1)}, MetaData: pos},
+	}, Unwrap(value))
+}
+
+func TestUnwrapObject(t *testing.T) {
+	object := Object{
+		Class:      "InputEventKey",
+		Properties: map[string]interface{}{"scancode": Value{Value: int64(87)}},
+	}
+
+	unwrapped := Unwrap(object).(Object)
+	assert.Equal(t, "InputEventKey", unwrapped.Class)
+	assert.Equal(t, int64(87), unwrapped.Properties["scancode"])
+	assert.Equal(t, int64(87), object.Property("scancode"))
+	assert.Nil(t, object.Property("missing"))
+}
@@ -0,0 +1,310 @@
+package godot
+
+import "math"
+
+// Vector2 is a 2D vector, written as Vector2(x, y)
+type Vector2 struct {
+	X, Y float64
+}
+
+// Vector2i is a 2D vector with integer components, written as Vector2i(x, y)
+type Vector2i struct {
+	X, Y int64
+}
+
+// Vector3 is a 3D vector, written as Vector3(x, y, z)
+type Vector3 struct {
+	X, Y, Z float64
+}
+
+// Vector3i is a 3D vector with integer components, written as Vector3i(x, y, z)
+type Vector3i struct {
+	X, Y, Z int64
+}
+
+// Vector4 is a 4D vector, written as Vector4(x, y, z, w)
+type Vector4 struct {
+	X, Y, Z, W float64
+}
+
+// Rect2 is an axis aligned rectangle, written as Rect2(x, y, width, height)
+type Rect2 struct {
+	Position, Size Vector2
+}
+
+// Rect2i is an axis aligned rectangle with integer components, written as Rect2i(x, y, width, height)
+type Rect2i struct {
+	Position, Size Vector2i
+}
+
+// AABB is an axis aligned bounding box, written as AABB(x, y, z, width, height, depth)
+type AABB struct {
+	Position, Size Vector3
+}
+
+// Plane is a plane in hessian normal form, written as Plane(normal.x, normal.y, normal.z, d)
+type Plane struct {
+	Normal Vector3
+	D      float64
+}
+
+// Color is a RGBA color with components from 0 to 1, written as Color(r, g, b, a)
+type Color struct {
+	R, G, B, A float64
+}
+
+// Add returns the sum of both vectors
+func (v Vector2) Add(o Vector2) Vector2 {
+	return Vector2{v.X + o.X, v.Y + o.Y}
+}
+
+// Sub returns the difference of both vectors
+func (v Vector2) Sub(o Vector2) Vector2 {
+	return Vector2{v.X - o.X, v.Y - o.Y}
+}
+
+// Mul multiplies both vectors component wise
+func (v Vector2) Mul(o Vector2) Vector2 {
+	return Vector2{v.X * o.X, v.Y * o.Y}
+}
+
+// Scale multiplies every component with f
+func (v Vector2) Scale(f float64) Vector2 {
+	return Vector2{v.X * f, v.Y * f}
+}
+
+// Dot returns the dot product of both vectors
+func (v Vector2) Dot(o Vector2) float64 {
+	return v.X*o.X + v.Y*o.Y
+}
+
+// Length returns the length of the vector
+func (v Vector2) Length() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Normalized returns the vector scaled to a length of 1, the zero vector stays zero
+func (v Vector2) Normalized() Vector2 {
+	length := v.Length()
+	if length == 0 {
+		return v
+	}
+	return v.Scale(1 / length)
+}
+
+// DistanceTo returns the distance between both vectors
+func (v Vector2) DistanceTo(o Vector2) float64 {
+	return o.Sub(v).Length()
+}
+
+// Angle returns the angle of the vector to the x axis in radians
+func (v Vector2) Angle() float64 {
+	return math.Atan2(v.Y, v.X)
+}
+
+// Rotated returns the vector rotated by angle radians
+func (v Vector2) Rotated(angle float64) Vector2 {
+	sin, cos := math.Sincos(angle)
+	return Vector2{v.X*cos - v.Y*sin, v.X*sin + v.Y*cos}
+}
+
+// Add returns the sum of both vectors
+func (v Vector2i) Add(o Vector2i) Vector2i {
+	return Vector2i{v.X + o.X, v.Y + o.Y}
+}
+
+// Sub returns the difference of both vectors
+func (v Vector2i) Sub(o Vector2i) Vector2i {
+	return Vector2i{v.X - o.X, v.Y - o.Y}
+}
+
+// Mul multiplies both vectors component wise
+func (v Vector2i) Mul(o Vector2i) Vector2i {
+	return Vector2i{v.X * o.X, v.Y * o.Y}
+}
+
+// Vector2 converts the vector into a floating point vector
+func (v Vector2i) Vector2() Vector2 {
+	return Vector2{float64(v.X), float64(v.Y)}
+}
+
+// Add returns the sum of both vectors
+func (v Vector3) Add(o Vector3) Vector3 {
+	return Vector3{v.X + o.X, v.Y + o.Y, v.Z + o.Z}
+}
+
+// Sub returns the difference of both vectors
+func (v Vector3) Sub(o Vector3) Vector3 {
+	return Vector3{v.X - o.X, v.Y - o.Y, v.Z - o.Z}
+}
+
+// Mul multiplies both vectors component wise
+func (v Vector3) Mul(o Vector3) Vector3 {
+	return Vector3{v.X * o.X, v.Y * o.Y, v.Z * o.Z}
+}
+
+// Scale multiplies every component with f
+func (v Vector3) Scale(f float64) Vector3 {
+	return Vector3{v.X * f, v.Y * f, v.Z * f}
+}
+
+// Dot returns the dot product of both vectors
+func (v Vector3) Dot(o Vector3) float64 {
+	return v.X*o.X + v.Y*o.Y + v.Z*o.Z
+}
+
+// Cross returns the cross product of both vectors
+func (v Vector3) Cross(o Vector3) Vector3 {
+	return Vector3{
+		v.Y*o.Z - v.Z*o.Y,
+		v.Z*o.X - v.X*o.Z,
+		v.X*o.Y - v.Y*o.X,
+	}
+}
+
+// Length returns the length of the vector
+func (v Vector3) Length() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+// Normalized returns the vector scaled to a length of 1, the zero vector stays zero
+func (v Vector3) Normalized() Vector3 {
+	length := v.Length()
+	if length == 0 {
+		return v
+	}
+	return v.Scale(1 / length)
+}
+
+// DistanceTo returns the distance between both vectors
+func (v Vector3) DistanceTo(o Vector3) float64 {
+	return o.Sub(v).Length()
+}
+
+// Add returns the sum of both vectors
+func (v Vector3i) Add(o Vector3i) Vector3i {
+	return Vector3i{v.X + o.X, v.Y + o.Y, v.Z + o.Z}
+}
+
+// Sub returns the difference of both vectors
+func (v Vector3i) Sub(o Vector3i) Vector3i {
+	return Vector3i{v.X - o.X, v.Y - o.Y, v.Z - o.Z}
+}
+
+// Mul multiplies both vectors component wise
+func (v Vector3i) Mul(o Vector3i) Vector3i {
+	return Vector3i{v.X * o.X, v.Y * o.Y, v.Z * o.Z}
+}
+
+// Vector3 converts the vector into a floating point vector
+func (v Vector3i) Vector3() Vector3 {
+	return Vector3{float64(v.X), float64(v.Y), float64(v.Z)}
+}
+
+// Add returns the sum of both vectors
+func (v Vector4) Add(o Vector4) Vector4 {
+	return Vector4{v.X + o.X, v.Y + o.Y, v.Z + o.Z, v.W + o.W}
+}
+
+// Sub returns the difference of both vectors
+func (v Vector4) Sub(o Vector4) Vector4 {
+	return Vector4{v.X - o.X, v.Y - o.Y, v.Z - o.Z, v.W - o.W}
+}
+
+// Scale multiplies every component with f
+func (v Vector4) Scale(f float64) Vector4 {
+	return Vector4{v.X * f, v.Y * f, v.Z * f, v.W * f}
+}
+
+// Dot returns the dot product of both vectors
+func (v Vector4) Dot(o Vector4) float64 {
+	return v.X*o.X + v.Y*o.Y + v.Z*o.Z + v.W*o.W
+}
+
+// Length returns the length of the vector
+func (v Vector4) Length() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+// End returns the bottom right corner of the rectangle
+func (r Rect2) End() Vector2 {
+	return r.Position.Add(r.Size)
+}
+
+// Area returns the area of the rectangle
+func (r Rect2) Area() float64 {
+	return r.Size.X * r.Size.Y
+}
+
+// HasPoint checks if the point is inside of the rectangle, the end is exclusive
+func (r Rect2) HasPoint(point Vector2) bool {
+	end := r.End()
+	return point.X >= r.Position.X && point.Y >= r.Position.Y && point.X < end.X && point.Y < end.Y
+}
+
+// Intersects checks if both rectangles overlap
+func (r Rect2) Intersects(o Rect2) bool {
+	end, oEnd := r.End(), o.End()
+	return r.Position.X < oEnd.X && o.Position.X < end.X && r.Position.Y < oEnd.Y && o.Position.Y < end.Y
+}
+
+// End returns the bottom right corner of the rectangle
+func (r Rect2i) End() Vector2i {
+	return r.Position.Add(r.Size)
+}
+
+// Area returns the area of the rectangle
+func (r Rect2i) Area() int64 {
+	return r.Size.X * r.Size.Y
+}
+
+// HasPoint checks if the point is inside of the rectangle, the end is exclusive
+func (r Rect2i) HasPoint(point Vector2i) bool {
+	end := r.End()
+	return point.X >= r.Position.X && point.Y >= r.Position.Y && point.X < end.X && point.Y < end.Y
+}
+
+// End returns the corner opposite of the position
+func (b AABB) End() Vector3 {
+	return b.Position.Add(b.Size)
+}
+
+// Volume returns the volume of the box
+func (b AABB) Volume() float64 {
+	return b.Size.X * b.Size.Y * b.Size.Z
+}
+
+// HasPoint checks if the point is inside of the box, the end is exclusive
+func (b AABB) HasPoint(point Vector3) bool {
+	end := b.End()
+	return point.X >= b.Position.X && point.Y >= b.Position.Y && point.Z >= b.Position.Z &&
+		point.X < end.X && point.Y < end.Y && point.Z < end.Z
+}
+
+// Intersects checks if both boxes overlap
+func (b AABB) Intersects(o AABB) bool {
+	end, oEnd := b.End(), o.End()
+	return b.Position.X < oEnd.X && o.Position.X < end.X &&
+		b.Position.Y < oEnd.Y && o.Position.Y < end.Y &&
+		b.Position.Z < oEnd.Z && o.Position.Z < end.Z
+}
+
+// DistanceTo returns the signed distance of the point to the plane, positive values are above the plane
+func (p Plane) DistanceTo(point Vector3) float64 {
+	return p.Normal.Dot(point) - p.D
+}
+
+// Project returns the orthogonal projection of the point onto the plane
+func (p Plane) Project(point Vector3) Vector3 {
+	return point.Sub(p.Normal.Scale(p.DistanceTo(point)))
+}
+
+// Lerp linearly interpolates between both colors, weight 0 returns c and weight 1 returns o
+func (c Color) Lerp(o Color, weight float64) Color {
+	return Color{
+		c.R + (o.R-c.R)*weight,
+		c.G + (o.G-c.G)*weight,
+		c.B + (o.B-c.B)*weight,
+		c.A + (o.A-c.A)*weight,
+	}
+}
@@ -0,0 +1,67 @@
+package godot
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVector2Arithmetic(t *testing.T) {
+	a, b := Vector2{3, 4}, Vector2{1, 2}
+
+	assert.Equal(t, Vector2{4, 6}, a.Add(b))
+	assert.Equal(t, Vector2{2, 2}, a.Sub(b))
+	assert.Equal(t, Vector2{3, 8}, a.Mul(b))
+	assert.Equal(t, Vector2{6, 8}, a.Scale(2))
+	assert.Equal(t, 11.0, a.Dot(b))
+	assert.Equal(t, 5.0, a.Length())
+	assert.InDelta(t, 1, a.Normalized().Length(), 1e-9)
+	assert.Equal(t, Vector2{}, Vector2{}.Normalized())
+	assert.Equal(t, 5.0, Vector2{}.DistanceTo(a))
+	assert.InDelta(t, math.Pi/2, Vector2{0, 1}.Angle(), 1e-9)
+
+	rotated := Vector2{1, 0}.Rotated(math.Pi / 2)
+	assert.InDelta(t, 0, rotated.X, 1e-9)
+	assert.InDelta(t, 1, rotated.Y, 1e-9)
+}
+
+func TestVector3Arithmetic(t *testing.T) {
+	x, y := Vector3{1, 0, 0}, Vector3{0, 1, 0}
+
+	assert.Equal(t, Vector3{0, 0, 1}, x.Cross(y))
+	assert.Equal(t, Vector3{1, 1, 0}, x.Add(y))
+	assert.Equal(t, 0.0, x.Dot(y))
+	assert.Equal(t, 3.0, Vector3{1, 2, 2}.Length())
+	assert.Equal(t, Vector3{1, 2, 3}, Vector3i{1, 2, 3}.Vector3())
+	assert.Equal(t, Vector2{1, 2}, Vector2i{1, 2}.Vector2())
+	assert.Equal(t, Vector3i{2, 4, 6}, Vector3i{1, 2, 3}.Add(Vector3i{1, 2, 3}))
+}
+
+func TestRect2(t *testing.T) {
+	rect := Rect2{Position: Vector2{10, 10}, Size: Vector2{20, 10}}
+
+	assert.Equal(t, Vector2{30, 20}, rect.End())
+	assert.Equal(t, 200.0, rect.Area())
+	assert.True(t, rect.HasPoint(Vector2{10, 15}))
+	assert.False(t, rect.HasPoint(Vector2{30, 15}))
+	assert.True(t, rect.Intersects(Rect2{Position: Vector2{25, 15}, Size: Vector2{10, 10}}))
+	assert.False(t, rect.Intersects(Rect2{Position: Vector2{30, 10}, Size: Vector2{10, 10}}))
+	assert.True(t, Rect2i{Size: Vector2i{2, 2}}.HasPoint(Vector2i{1, 1}))
+}
+
+func TestAABBAndPlane(t *testing.T) {
+	box := AABB{Size: Vector3{1, 2, 3}}
+	assert.Equal(t, 6.0, box.Volume())
+	assert.True(t, box.HasPoint(Vector3{0.5, 1, 1}))
+	assert.False(t, box.Intersects(AABB{Position: Vector3{1, 0, 0}, Size: Vector3{1, 1, 1}}))
+
+	ground := Plane{Normal: Vector3{0, 1, 0}, D: 2}
+	assert.Equal(t, 3.0, ground.DistanceTo(Vector3{7, 5, 1}))
+	assert.Equal(t, Vector3{7, 2, 1}, ground.Project(Vector3{7, 5, 1}))
+}
+
+func TestColorLerp(t *testing.T) {
+	black, white := Color{0, 0, 0, 1}, Color{1, 1, 1, 1}
+	assert.Equal(t, Color{0.5, 0.5, 0.5, 1}, black.Lerp(white, 0.5))
+}
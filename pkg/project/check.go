@@ -0,0 +1,321 @@
+package project
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+	"github.com/atomicptr/godot-tscn-parser/pkg/tscn"
+)
+
+// Rule IDs of the diagnostics reported by CheckReferences
+const (
+	// RuleBrokenExtResource reports ext_resources pointing to files which don't exist
+	RuleBrokenExtResource = "broken-ext-resource"
+	// RuleExtResourceTypeMismatch reports ext_resources declaring a type the file they point to doesn't have
+	RuleExtResourceTypeMismatch = "ext-resource-type-mismatch"
+	// RuleUnusedSubResource reports sub_resources which are never referenced
+	RuleUnusedSubResource = "unused-sub-resource"
+	// RuleUnusedExtResource reports ext_resources which are never referenced
+	RuleUnusedExtResource = "unused-ext-resource"
+	// RuleUnreferencedFile reports scenes, resources and imported files no other file references
+	RuleUnreferencedFile = "unreferenced-file"
+)
+
+const (
+	// baseResourceType is the type every resource inherits from
+	baseResourceType = "Resource"
+	// packedSceneType is the type of scenes
+	packedSceneType = "PackedScene"
+)
+
+// resourceSubtypes lists the subtypes of resource types whose names don't contain the name of the base type, e.g.
+// ImageTexture is a Texture2D in Godot 4
+var resourceSubtypes = map[string][]string{
+	"Texture2D": {
+		"AnimatedTexture",
+		"AtlasTexture",
+		"CanvasTexture",
+		"CurveTexture",
+		"CurveXYZTexture",
+		"GradientTexture1D",
+		"ImageTexture",
+		"MeshTexture",
+		"ViewportTexture",
+	},
+}
+
+// typesByExtension are the types of files which aren't imported and can't be parsed
+var typesByExtension = map[string]string{
+	".gd":       "GDScript",
+	".cs":       "CSharpScript",
+	".shader":   "Shader",
+	".gdshader": "Shader",
+	".scn":      packedSceneType,
+	".res":      baseResourceType,
+}
+
+// CheckReferences checks the references between the files of the project: ext_resources pointing to files which
+// don't exist or have another type, sub_resources and ext_resources which are never used and scenes, resources and
+// imported files which aren't referenced by any other file. Files which couldn't be parsed are skipped.
+//
+// Files loaded by scripts, e.g. with preload, aren't known to the project, thus unreferenced files are only reported
+// with the severity info.
+func (p *Project) CheckReferences() diagnostic.List {
+	g := p.DependencyGraph()
+
+	var diagnostics diagnostic.List
+	for _, resPath := range p.Paths() {
+		file := p.Files[resPath]
+		if file.Err != nil {
+			continue
+		}
+		diagnostics.Add(p.checkExtResources(g, file)...)
+		diagnostics.Add(checkUnusedResources(file)...)
+	}
+	diagnostics.Add(p.checkUnreferencedFiles(g)...)
+
+	diagnostics.Sort()
+	return diagnostics
+}
+
+// checkExtResources checks that the ext_resources of a scene or resource exist and have the declared type
+func (p *Project) checkExtResources(g *Graph, file *File) diagnostic.List {
+	extResources, _ := resourcesOf(file)
+
+	var diagnostics diagnostic.List
+	for _, edge := range g.Edges(file.Path) {
+		ext, ok := extResources[edge.Key]
+		if edge.Kind != DependencyExtResource || !ok {
+			continue
+		}
+
+		if !p.exists(edge.To) {
+			d := diagnostic.New(edge.Position, "ext_resource %s points to %s which doesn't exist", ext.ID, ext.Path)
+			d.RuleID = RuleBrokenExtResource
+			diagnostics.Add(d)
+			continue
+		}
+
+		actual, ok := p.typeOf(edge.To)
+		if ok && ext.Type != "" && !isResourceSubtype(actual, ext.Type) {
+			d := diagnostic.New(
+				edge.Position,
+				"ext_resource %s has the type %s but %s is a %s",
+				ext.ID,
+				ext.Type,
+				ext.Path,
+				actual,
+			)
+			d.RuleID = RuleExtResourceTypeMismatch
+			diagnostics.Add(d)
+		}
+	}
+	return diagnostics
+}
+
+// checkUnusedResources reports the ext_resources and sub_resources of a file which are never used
+func checkUnusedResources(file *File) diagnostic.List {
+	extResources, subResources := resourcesOf(file)
+	usedExt, usedSub := usedResources(file)
+
+	var diagnostics diagnostic.List
+	for _, ext := range sortedExtResources(extResources) {
+		if !usedExt[ext.ID] {
+			d := diagnostic.New(ext.LexerPosition, "ext_resource %s (%s) is never used", ext.ID, ext.Path)
+			d.Severity = diagnostic.SeverityWarning
+			d.RuleID = RuleUnusedExtResource
+			diagnostics.Add(d)
+		}
+	}
+	for _, sub := range subResources {
+		if !usedSub[sub.ID] {
+			d := diagnostic.New(sub.LexerPosition, "sub_resource %s (%s) is never used", sub.ID, sub.Type)
+			d.Severity = diagnostic.SeverityWarning
+			d.RuleID = RuleUnusedSubResource
+			diagnostics.Add(d)
+		}
+	}
+	return diagnostics
+}
+
+// checkUnreferencedFiles reports scenes, resources and imported files which no other file depends on, the .import
+// file of an imported file doesn't count as reference
+func (p *Project) checkUnreferencedFiles(g *Graph) diagnostic.List {
+	var candidates []string
+	for _, resPath := range p.Paths() {
+		file := p.Files[resPath]
+		switch {
+		case file.Err != nil:
+			continue
+		case file.Scene != nil, file.Resource != nil:
+			candidates = append(candidates, resPath)
+		case file.Import != nil:
+			if source := strings.TrimSuffix(resPath, path.Ext(resPath)); p.exists(source) {
+				candidates = append(candidates, source)
+			}
+		}
+	}
+
+	var diagnostics diagnostic.List
+	for _, resPath := range candidates {
+		referenced := false
+		for _, dependent := range g.Dependents(resPath) {
+			if dependent != resPath && dependent != resPath+".import" {
+				referenced = true
+				break
+			}
+		}
+		if referenced {
+			continue
+		}
+
+		pos := lexer.Position{Filename: resPath, Line: 1, Column: 1}
+		d := diagnostic.New(pos, "%s isn't referenced by any file of the project", resPath)
+		d.Severity = diagnostic.SeverityInfo
+		d.RuleID = RuleUnreferencedFile
+		diagnostics.Add(d)
+	}
+	return diagnostics
+}
+
+// exists checks if a file exists in the project directory
+func (p *Project) exists(resPath string) bool {
+	name, err := tscn.ResourcePathToFSPath(resPath)
+	if err != nil {
+		return false
+	}
+	_, err = fs.Stat(p.FS, name)
+	return err == nil
+}
+
+// typeOf returns the resource type of a file, e.g. PackedScene for scenes or the type the .import file declares for
+// imported files. Returns false if the type is unknown.
+func (p *Project) typeOf(resPath string) (string, bool) {
+	if file, ok := p.Files[resPath]; ok {
+		switch {
+		case file.Scene != nil:
+			return packedSceneType, true
+		case file.Resource != nil:
+			return file.Resource.Type, file.Resource.Type != ""
+		default:
+			return "", false
+		}
+	}
+
+	if file, ok := p.Files[resPath+".import"]; ok && file.Import != nil {
+		return file.Import.Type, file.Import.Type != ""
+	}
+
+	resourceType, ok := typesByExtension[path.Ext(resPath)]
+	return resourceType, ok
+}
+
+// isResourceSubtype guesses if a type is the declared type or a subtype of it. Godot's class hierarchy isn't known to
+// the parser, subtypes are recognized by their name instead, e.g. StreamTexture is a Texture and AudioStreamMP3 is an
+// AudioStream.
+func isResourceSubtype(actual, declared string) bool {
+	if actual == declared || declared == baseResourceType {
+		return true
+	}
+	if strings.HasPrefix(actual, declared) || strings.HasSuffix(actual, declared) {
+		return true
+	}
+	for _, subtype := range resourceSubtypes[declared] {
+		if actual == subtype {
+			return true
+		}
+	}
+	return false
+}
+
+// resourcesOf returns the ext_resources and sub_resources of a scene or resource, nil for other files
+func resourcesOf(file *File) (map[string]*godot.ExtResource, map[string]*godot.SubResource) {
+	switch {
+	case file.Scene != nil:
+		return file.Scene.ExtResources, file.Scene.SubResources
+	case file.Resource != nil:
+		return file.Resource.ExtResources, file.Resource.SubResources
+	default:
+		return nil, nil
+	}
+}
+
+// usedResources returns the IDs of the ext_resources and sub_resources used by the nodes of a scene or the resource
+// itself. References within sub_resources only count if the sub_resource is used as well.
+func usedResources(file *File) (ext, sub map[string]bool) {
+	ext = make(map[string]bool)
+	sub = make(map[string]bool)
+	_, subResources := resourcesOf(file)
+
+	var queue []string
+	use := func(ref godot.Reference) {
+		switch {
+		case ref.External:
+			ext[ref.ID] = true
+		case !sub[ref.ID]:
+			sub[ref.ID] = true
+			queue = append(queue, ref.ID)
+		}
+	}
+
+	switch {
+	case file.Scene != nil && file.Scene.Node != nil:
+		var visit func(node *godot.Node)
+		visit = func(node *godot.Node) {
+			findReferences(node.Instance, use)
+			findReferences(node.Fields, use)
+			for _, child := range node.Children() {
+				visit(child)
+			}
+		}
+		visit(file.Scene.Node)
+	case file.Resource != nil:
+		findReferences(file.Resource.Fields, use)
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if res, ok := subResources[id]; ok {
+			findReferences(res.Fields, use)
+		}
+	}
+
+	return ext, sub
+}
+
+// findReferences calls visit for every ExtResource and SubResource reference within a value
+func findReferences(value interface{}, visit func(ref godot.Reference)) {
+	switch v := value.(type) {
+	case godot.Value:
+		findReferences(v.Value, visit)
+	case godot.KeyValuePair:
+		findReferences(v.Value, visit)
+	case godot.Type:
+		if ref, ok := convert.ReferenceOf(v); ok {
+			visit(ref)
+			return
+		}
+		for _, param := range v.Parameters {
+			findReferences(param, visit)
+		}
+	case godot.Object:
+		for _, property := range v.Properties {
+			findReferences(property, visit)
+		}
+	case []interface{}:
+		for _, elem := range v {
+			findReferences(elem, visit)
+		}
+	case map[string]interface{}:
+		for _, elem := range v {
+			findReferences(elem, visit)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package project
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+)
+
+func newCheckTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"project.godot": {Data: []byte(`config_version=4
+
+[application]
+
+run/main_scene="res://World.tscn"
+`)},
+		"World.tscn": {Data: []byte(`[gd_scene load_steps=8 format=2]
+
+[ext_resource path="res://Player.tscn" type="PackedScene" id=1]
+[ext_resource path="res://missing.png" type="Texture" id=2]
+[ext_resource path="res://default_env.tres" type="Texture" id=3]
+[ext_resource path="res://icon.png" type="Texture" id=4]
+
+[sub_resource type="RectangleShape2D" id=1]
+
+[sub_resource type="Gradient" id=2]
+
+[sub_resource type="GradientTexture" id=3]
+gradient = SubResource( 2 )
+
+[node name="World" type="Node2D"]
+
+[node name="Player" parent="." instance=ExtResource( 1 )]
+
+[node name="Sprite" type="Sprite" parent="."]
+texture = ExtResource( 2 )
+normal_map = ExtResource( 3 )
+
+[node name="Area" type="Area2D" parent="."]
+
+[node name="Shape" type="CollisionShape2D" parent="Area"]
+shape = SubResource( 1 )
+`)},
+		"Player.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="Player.gd" type="Script" id=1]
+
+[node name="Player" type="Node2D"]
+script = ExtResource( 1 )
+`)},
+		"Player.gd":        {Data: []byte("extends Node2D\n")},
+		"Unused.tscn":      {Data: []byte("[gd_scene format=2]\n\n[node name=\"Unused\" type=\"Node\"]\n")},
+		"default_env.tres": {Data: []byte("[gd_resource type=\"Environment\" format=2]\n\n[resource]\n")},
+		"icon.png":         {Data: []byte{}},
+		"icon.png.import": {Data: []byte(
+			"[remap]\n\nimporter=\"texture\"\ntype=\"StreamTexture\"\n\n[deps]\n\nsource_file=\"res://icon.png\"\n",
+		)},
+		"unused.png": {Data: []byte{}},
+		"unused.png.import": {Data: []byte(
+			"[remap]\n\nimporter=\"texture\"\ntype=\"StreamTexture\"\n\n[deps]\n\nsource_file=\"res://unused.png\"\n",
+		)},
+	}
+}
+
+func TestCheckReferences(t *testing.T) {
+	p := loadTestProject(t, newCheckTestFS())
+
+	var rendered []string
+	for _, d := range p.CheckReferences() {
+		rendered = append(rendered, d.String())
+	}
+
+	assert.Equal(t, []string{
+		"res://Unused.tscn:1:1: info: res://Unused.tscn isn't referenced by any file of the project [unreferenced-file]",
+		"res://World.tscn:4:1: error: ext_resource 2 points to res://missing.png which doesn't exist " +
+			"[broken-ext-resource]",
+		"res://World.tscn:5:1: error: ext_resource 3 has the type Texture but res://default_env.tres is a Environment " +
+			"[ext-resource-type-mismatch]",
+		"res://World.tscn:6:1: warning: ext_resource 4 (res://icon.png) is never used [unused-ext-resource]",
+		"res://World.tscn:10:1: warning: sub_resource 2 (Gradient) is never used [unused-sub-resource]",
+		"res://World.tscn:12:1: warning: sub_resource 3 (GradientTexture) is never used [unused-sub-resource]",
+		"res://unused.png:1:1: info: res://unused.png isn't referenced by any file of the project [unreferenced-file]",
+	}, rendered)
+}
+
+func TestCheckReferencesSeverities(t *testing.T) {
+	p := loadTestProject(t, newCheckTestFS())
+
+	diagnostics := p.CheckReferences()
+	assert.Len(t, diagnostics.Errors(), 2)
+	assert.Len(t, diagnostics.WithSeverity(diagnostic.SeverityWarning), 3)
+	assert.Len(t, diagnostics.WithSeverity(diagnostic.SeverityInfo), 2)
+}
+
+func TestCheckReferencesGodot4(t *testing.T) {
+	fsys := fstest.MapFS{
+		"project.godot": {Data: []byte("config_version=5\n\n[application]\n\nrun/main_scene=\"res://Main.tscn\"\n")},
+		"Main.tscn": {Data: []byte(`[gd_scene load_steps=3 format=3 uid="uid://b1"]
+
+[ext_resource type="Texture2D" path="res://icon.svg" id="1_a"]
+[ext_resource type="Script" path="res://Main.gd" id="2_b"]
+
+[node name="Main" type="Sprite2D"]
+texture = ExtResource("1_a")
+script = ExtResource("2_b")
+`)},
+		"Main.gd":  {Data: []byte("extends Sprite2D\n")},
+		"icon.svg": {Data: []byte{}},
+		"icon.svg.import": {Data: []byte(
+			"[remap]\n\nimporter=\"texture\"\ntype=\"CompressedTexture2D\"\n\n[deps]\n\nsource_file=\"res://icon.svg\"\n",
+		)},
+	}
+
+	p := loadTestProject(t, fsys)
+	assert.Empty(t, p.CheckReferences())
+}
+
+func TestIsResourceSubtype(t *testing.T) {
+	assert.True(t, isResourceSubtype("StreamTexture", "Texture"))
+	assert.True(t, isResourceSubtype("AudioStreamMP3", "AudioStream"))
+	assert.True(t, isResourceSubtype("ImageTexture", "Texture2D"))
+	assert.True(t, isResourceSubtype("Environment", "Resource"))
+	assert.False(t, isResourceSubtype("Environment", "Texture"))
+	assert.False(t, isResourceSubtype("PackedScene", "Script"))
+}
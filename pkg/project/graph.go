@@ -0,0 +1,418 @@
+package project
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+	"github.com/atomicptr/godot-tscn-parser/pkg/tscn"
+)
+
+// DependencyKind describes where a dependency was found
+type DependencyKind string
+
+const (
+	// DependencyExtResource is an ext_resource of a scene or resource
+	DependencyExtResource DependencyKind = "ext_resource"
+	// DependencyImport is the source_file of an .import file
+	DependencyImport DependencyKind = "import"
+	// DependencySetting is a res:// path in project.godot like the main scene, an autoload or the default environment
+	DependencySetting DependencyKind = "setting"
+	// DependencyConfig is a res:// path in a .cfg file, e.g. the script of a plugin
+	DependencyConfig DependencyKind = "config"
+)
+
+// autoloadSingletonPrefix marks autoloads which are available as global variable, e.g. "*res://Game.gd"
+const autoloadSingletonPrefix = "*"
+
+// Dependency is an edge of the dependency graph, From depends on To
+type Dependency struct {
+	From string
+	To   string
+	Kind DependencyKind
+	// Key is the setting, field or ext_resource ID the path was found in
+	Key string
+	// Position is where the path was found in From
+	Position lexer.Position
+}
+
+// Graph is the dependency graph of all files of a project, the nodes are res:// paths. Files which are referenced but
+// weren't loaded, like textures or scripts, are part of the graph as well.
+type Graph struct {
+	edges        map[string][]Dependency
+	dependencies map[string][]string
+	dependents   map[string][]string
+}
+
+// CycleError is returned by TopologicalOrder if files depend on each other
+type CycleError struct {
+	Cycles [][]string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle found: %s", strings.Join(e.Cycles[0], " -> "))
+}
+
+// DependencyGraph builds the dependency graph of the loaded files
+func (p *Project) DependencyGraph() *Graph {
+	g := &Graph{
+		edges:        make(map[string][]Dependency),
+		dependencies: make(map[string][]string),
+		dependents:   make(map[string][]string),
+	}
+
+	uids := p.uidIndex()
+	for _, resPath := range p.Paths() {
+		g.addNode(resPath)
+		for _, dependency := range fileDependencies(p.Files[resPath], uids) {
+			g.addDependency(dependency)
+		}
+	}
+
+	for node := range g.dependencies {
+		sort.Strings(g.dependencies[node])
+		sort.Strings(g.dependents[node])
+	}
+
+	return g
+}
+
+func (g *Graph) addNode(resPath string) {
+	if _, ok := g.dependencies[resPath]; !ok {
+		g.dependencies[resPath] = nil
+		g.dependents[resPath] = nil
+	}
+}
+
+func (g *Graph) addDependency(dependency Dependency) {
+	g.addNode(dependency.From)
+	g.addNode(dependency.To)
+
+	for _, edge := range g.edges[dependency.From] {
+		if edge.To == dependency.To {
+			g.edges[dependency.From] = append(g.edges[dependency.From], dependency)
+			return
+		}
+	}
+
+	g.edges[dependency.From] = append(g.edges[dependency.From], dependency)
+	g.dependencies[dependency.From] = append(g.dependencies[dependency.From], dependency.To)
+	g.dependents[dependency.To] = append(g.dependents[dependency.To], dependency.From)
+}
+
+// fileDependencies returns the dependencies of a file in the order of the file, ext_resources with a known uid
+// depend on the file of the uid like in Godot
+func fileDependencies(file *File, uids *uidIndex) []Dependency {
+	var dependencies []Dependency
+	add := func(kind DependencyKind, key, target string, pos lexer.Position) {
+		resPath, ok := uids.resolve(target)
+		if !ok {
+			resPath, ok = resolveResourcePath(file.Path, target)
+		}
+		if ok {
+			dependencies = append(dependencies, Dependency{file.Path, resPath, kind, key, pos})
+		}
+	}
+
+	extResources, _ := resourcesOf(file)
+	for _, ext := range sortedExtResources(extResources) {
+		if _, ok := uids.resolve(ext.UID); ok {
+			add(DependencyExtResource, ext.ID, ext.UID, ext.LexerPosition)
+		} else {
+			add(DependencyExtResource, ext.ID, ext.Path, ext.LexerPosition)
+		}
+	}
+
+	switch {
+	case file.Import != nil:
+		if file.Import.SourceFile != "" {
+			add(DependencyImport, "source_file", file.Import.SourceFile, positionOf(file.Import.Deps["source_file"]))
+		}
+	case file.Project != nil:
+		settingPaths(file, func(key, target string, pos lexer.Position) {
+			add(DependencySetting, key, target, pos)
+		})
+	case file.Config != nil:
+		settingPaths(file, func(key, target string, pos lexer.Position) {
+			add(DependencyConfig, key, target, pos)
+		})
+	}
+
+	sort.SliceStable(dependencies, func(i, j int) bool {
+		a, b := dependencies[i].Position, dependencies[j].Position
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return dependencies
+}
+
+// settingPaths calls visit for every res:// and uid:// path in the settings of project.godot or a config file, the
+// prefix of autoload paths is removed
+func settingPaths(file *File, visit func(key, target string, pos lexer.Position)) {
+	switch {
+	case file.Project != nil:
+		settings := file.Project.Settings()
+		for _, key := range settings.Paths() {
+			// fields like _global_script_classes are caches written by the editor
+			if strings.HasPrefix(key, "_") {
+				continue
+			}
+			value, _ := settings.Field(key)
+			findResourcePaths(value, lexer.Position{}, func(target string, pos lexer.Position) {
+				visit(key, strings.TrimPrefix(target, autoloadSingletonPrefix), pos)
+			})
+		}
+	case file.Config != nil:
+		for _, section := range file.Config.Sections() {
+			for _, key := range file.Config.SectionKeys(section) {
+				value, _ := file.Config.Field(section, key)
+				findResourcePaths(value, lexer.Position{}, func(target string, pos lexer.Position) {
+					visit(key, target, pos)
+				})
+			}
+		}
+	}
+}
+
+// findResourcePaths calls visit for every res:// and uid:// path within a value, including autoload paths like
+// *res://Game.gd
+func findResourcePaths(value interface{}, pos lexer.Position, visit func(target string, pos lexer.Position)) {
+	switch v := value.(type) {
+	case godot.Value:
+		findResourcePaths(v.Value, v.LexerPosition, visit)
+	case string:
+		target := strings.TrimPrefix(v, autoloadSingletonPrefix)
+		if strings.HasPrefix(target, tscn.ResourcePathPrefix) || strings.HasPrefix(target, tscn.UIDPathPrefix) {
+			visit(v, pos)
+		}
+	case []interface{}:
+		for _, elem := range v {
+			findResourcePaths(elem, pos, visit)
+		}
+	case map[string]interface{}:
+		for _, elem := range v {
+			findResourcePaths(elem, pos, visit)
+		}
+	case godot.Type:
+		for _, param := range v.Parameters {
+			findResourcePaths(param, v.LexerPosition, visit)
+		}
+	case godot.Object:
+		for _, property := range v.Properties {
+			findResourcePaths(property, v.LexerPosition, visit)
+		}
+	case godot.KeyValuePair:
+		findResourcePaths(v.Value, v.LexerPosition, visit)
+	}
+}
+
+// resolveResourcePath converts a path into a res:// path, relative paths are relative to the file containing them.
+// Paths of other schemes like user:// or uid:// can't be resolved.
+func resolveResourcePath(from, target string) (string, bool) {
+	if strings.HasPrefix(target, tscn.ResourcePathPrefix) {
+		name, err := tscn.ResourcePathToFSPath(target)
+		if err != nil {
+			return "", false
+		}
+		return resourcePath(name), true
+	}
+
+	if target == "" || strings.Contains(target, "://") || path.IsAbs(target) {
+		return "", false
+	}
+
+	dir := path.Dir(strings.TrimPrefix(from, tscn.ResourcePathPrefix))
+	return resolveResourcePath(from, tscn.ResourcePathPrefix+path.Join(dir, target))
+}
+
+func sortedExtResources(resources map[string]*godot.ExtResource) []*godot.ExtResource {
+	sorted := make([]*godot.ExtResource, 0, len(resources))
+	for _, ext := range resources {
+		sorted = append(sorted, ext)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+func positionOf(value interface{}) lexer.Position {
+	if v, ok := value.(godot.Value); ok {
+		return v.LexerPosition
+	}
+	return lexer.Position{}
+}
+
+// Files returns all files of the graph sorted by their path
+func (g *Graph) Files() []string {
+	files := make([]string, 0, len(g.dependencies))
+	for file := range g.dependencies {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Edges returns the dependencies of a file with the place they were found, a file can reference the same file
+// multiple times
+func (g *Graph) Edges(resPath string) []Dependency {
+	return append([]Dependency(nil), g.edges[resPath]...)
+}
+
+// Dependencies returns the files a file directly depends on
+func (g *Graph) Dependencies(resPath string) []string {
+	return append([]string(nil), g.dependencies[resPath]...)
+}
+
+// Dependents returns the files which directly depend on a file
+func (g *Graph) Dependents(resPath string) []string {
+	return append([]string(nil), g.dependents[resPath]...)
+}
+
+// TransitiveDependencies returns all files a file depends on directly or indirectly, sorted by path
+func (g *Graph) TransitiveDependencies(resPath string) []string {
+	return g.reachable(resPath, g.dependencies)
+}
+
+// TransitiveDependents returns all files which depend on a file directly or indirectly, sorted by path. These are the
+// files which break if the file is deleted.
+func (g *Graph) TransitiveDependents(resPath string) []string {
+	return g.reachable(resPath, g.dependents)
+}
+
+func (g *Graph) reachable(start string, edges map[string][]string) []string {
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+	var result []string
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[node] {
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			result = append(result, next)
+			queue = append(queue, next)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// Cycles returns groups of files which depend on each other, e.g. two scenes instancing each other. Each cycle is
+// sorted by path.
+func (g *Graph) Cycles() [][]string {
+	// Tarjan's algorithm for strongly connected components
+	index := 0
+	indices := make(map[string]int)
+	lowLinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+
+	var connect func(node string)
+	connect = func(node string) {
+		indices[node] = index
+		lowLinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range g.dependencies[node] {
+			if _, visited := indices[next]; !visited {
+				connect(next)
+				if lowLinks[next] < lowLinks[node] {
+					lowLinks[node] = lowLinks[next]
+				}
+			} else if onStack[next] && indices[next] < lowLinks[node] {
+				lowLinks[node] = indices[next]
+			}
+		}
+
+		if lowLinks[node] != indices[node] {
+			return
+		}
+
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+
+		if len(component) > 1 || g.dependsOn(node, node) {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range g.Files() {
+		if _, visited := indices[node]; !visited {
+			connect(node)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+	return cycles
+}
+
+func (g *Graph) dependsOn(from, to string) bool {
+	for _, dependency := range g.dependencies[from] {
+		if dependency == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TopologicalOrder returns all files ordered so that every file comes after its dependencies, files without an order
+// between them are sorted by path. Returns a CycleError if files depend on each other.
+func (g *Graph) TopologicalOrder() ([]string, error) {
+	remaining := make(map[string]int, len(g.dependencies))
+	var ready []string
+	for _, node := range g.Files() {
+		remaining[node] = len(g.dependencies[node])
+		if remaining[node] == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	order := make([]string, 0, len(remaining))
+	for len(ready) > 0 {
+		node := ready[0]
+		ready = ready[1:]
+		order = append(order, node)
+
+		for _, dependent := range g.dependents[node] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = insertSorted(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(remaining) {
+		return nil, &CycleError{Cycles: g.Cycles()}
+	}
+	return order, nil
+}
+
+func insertSorted(values []string, value string) []string {
+	index := sort.SearchStrings(values, value)
+	values = append(values, "")
+	copy(values[index+1:], values[index:])
+	values[index] = value
+	return values
+}
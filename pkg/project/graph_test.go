@@ -0,0 +1,166 @@
+package project
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newGraphTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"project.godot": {Data: []byte(`config_version=4
+
+_global_script_classes=[ {
+"base": "Node",
+"class": "Game",
+"language": "GDScript",
+"path": "res://globals/Game.gd"
+} ]
+
+[application]
+
+run/main_scene="res://World.tscn"
+
+[autoload]
+
+Game="*res://globals/Game.gd"
+
+[rendering]
+
+environment/default_environment="res://default_env.tres"
+`)},
+		"World.tscn": {Data: []byte(`[gd_scene load_steps=3 format=2]
+
+[ext_resource path="res://Player/Player.tscn" type="PackedScene" id=1]
+[ext_resource path="res://icon.png" type="Texture" id=2]
+
+[node name="World" type="Node2D"]
+
+[node name="Player" parent="." instance=ExtResource( 1 )]
+
+[node name="Icon" type="Sprite" parent="."]
+texture = ExtResource( 2 )
+`)},
+		"Player/Player.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="Player.gd" type="Script" id=1]
+
+[node name="Player" type="Node2D"]
+script = ExtResource( 1 )
+`)},
+		"default_env.tres": {Data: []byte(`[gd_resource type="Environment" load_steps=2 format=2]
+
+[ext_resource path="res://sky.png" type="Texture" id=1]
+
+[resource]
+background_sky = ExtResource( 1 )
+`)},
+		"icon.png.import":        {Data: []byte("[remap]\n\nimporter=\"texture\"\n\n[deps]\n\nsource_file=\"res://icon.png\"\n")},
+		"addons/tool/plugin.cfg": {Data: []byte("[plugin]\n\nname=\"Tool\"\nscript=\"res://addons/tool/plugin.gd\"\n")},
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	g := loadTestProject(t, newGraphTestFS()).DependencyGraph()
+
+	assert.Equal(t, []string{"res://Player/Player.tscn", "res://icon.png"}, g.Dependencies("res://World.tscn"))
+	assert.Equal(t, []string{"res://Player/Player.gd"}, g.Dependencies("res://Player/Player.tscn"))
+	assert.Equal(
+		t,
+		[]string{"res://World.tscn", "res://default_env.tres", "res://globals/Game.gd"},
+		g.Dependencies("res://project.godot"),
+	)
+	assert.Equal(t, []string{"res://addons/tool/plugin.gd"}, g.Dependencies("res://addons/tool/plugin.cfg"))
+	assert.Equal(t, []string{"res://World.tscn", "res://icon.png.import"}, g.Dependents("res://icon.png"))
+
+	assert.Contains(t, g.Files(), "res://sky.png")
+}
+
+func TestDependencyGraphEdges(t *testing.T) {
+	g := loadTestProject(t, newGraphTestFS()).DependencyGraph()
+
+	edges := g.Edges("res://project.godot")
+	assert.Len(t, edges, 3)
+	assert.Equal(t, "application/run/main_scene", edges[0].Key)
+	assert.Equal(t, DependencySetting, edges[0].Kind)
+	assert.Equal(t, 12, edges[0].Position.Line)
+	assert.Equal(t, "res://globals/Game.gd", edges[1].To)
+
+	importEdges := g.Edges("res://icon.png.import")
+	assert.Equal(t, DependencyImport, importEdges[0].Kind)
+	assert.Equal(t, 7, importEdges[0].Position.Line)
+
+	sceneEdges := g.Edges("res://World.tscn")
+	assert.Equal(t, DependencyExtResource, sceneEdges[0].Kind)
+	assert.Equal(t, "1", sceneEdges[0].Key)
+	assert.Equal(t, 3, sceneEdges[0].Position.Line)
+}
+
+func TestDependencyGraphTransitive(t *testing.T) {
+	g := loadTestProject(t, newGraphTestFS()).DependencyGraph()
+
+	assert.Equal(
+		t,
+		[]string{"res://Player/Player.gd", "res://Player/Player.tscn", "res://icon.png"},
+		g.TransitiveDependencies("res://World.tscn"),
+	)
+	assert.Equal(
+		t,
+		[]string{"res://Player/Player.tscn", "res://World.tscn", "res://project.godot"},
+		g.TransitiveDependents("res://Player/Player.gd"),
+	)
+	assert.Empty(t, g.TransitiveDependents("res://project.godot"))
+}
+
+func TestDependencyGraphTopologicalOrder(t *testing.T) {
+	g := loadTestProject(t, newGraphTestFS()).DependencyGraph()
+	assert.Empty(t, g.Cycles())
+
+	order, err := g.TopologicalOrder()
+	assert.NoError(t, err)
+	assert.Len(t, order, len(g.Files()))
+
+	position := make(map[string]int)
+	for index, file := range order {
+		position[file] = index
+	}
+	for _, file := range order {
+		for _, dependency := range g.Dependencies(file) {
+			assert.Less(t, position[dependency], position[file], "%s before %s", dependency, file)
+		}
+	}
+}
+
+func TestDependencyGraphCycles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"project.godot": {Data: []byte("config_version=4\n")},
+		"A.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://B.tscn" type="PackedScene" id=1]
+
+[node name="A" type="Node"]
+`)},
+		"B.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://A.tscn" type="PackedScene" id=1]
+
+[node name="B" type="Node"]
+`)},
+		"C.tres": {Data: []byte(`[gd_resource type="Resource" load_steps=2 format=2]
+
+[ext_resource path="res://C.tres" type="Resource" id=1]
+
+[resource]
+`)},
+	}
+	g := loadTestProject(t, fsys).DependencyGraph()
+
+	assert.Equal(t, [][]string{{"res://A.tscn", "res://B.tscn"}, {"res://C.tres"}}, g.Cycles())
+
+	_, err := g.TopologicalOrder()
+	var cycleErr *CycleError
+	assert.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, "dependency cycle found: res://A.tscn -> res://B.tscn", err.Error())
+}
@@ -0,0 +1,396 @@
+package project
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+	"github.com/atomicptr/godot-tscn-parser/pkg/tscn"
+)
+
+// companionExtensions are the extensions of files belonging to another file, e.g. icon.png.import
+var companionExtensions = []string{".import", ".uid"}
+
+// importedFileDirs are the directories the imported files are stored in, .import in Godot 3 and .godot/imported in
+// Godot 4
+var importedFileDirs = []string{".import", ".godot/imported"}
+
+// Refactoring is a set of changes to the files of a project, nothing is changed until it's applied
+type Refactoring struct {
+	// Moves are the files and directories to move in the order they're moved
+	Moves []Move
+	// Edits are the new contents of the files referencing moved files, the key is the res:// path after the moves
+	Edits map[string]string
+}
+
+// Move moves a file or directory, both paths are res:// paths
+type Move struct {
+	From string
+	To   string
+}
+
+// mover rewrites the paths of a move
+type mover struct {
+	from, to string
+	// importedBases maps the names of imported files of moved files to their new names, the name of the imported
+	// files of res://icon.png is "icon.png-" followed by the MD5 hash of the path
+	importedBases map[string]string
+}
+
+// MoveResource plans moving a file or directory to another path and rewrites every reference to it: ext_resource
+// paths of scenes and resources, paths in project.godot like the main scene and autoloads, the source and dest files
+// of .import files and the export filters of export_presets.cfg. Companion files like the .import file and the files
+// created by the importer are moved along. Only the values containing a path are rewritten, the rest of a file is
+// kept as it is.
+//
+// Paths within scripts aren't rewritten. The project isn't updated by the refactoring, load it again after applying
+// it.
+func (p *Project) MoveResource(oldPath, newPath string) (*Refactoring, error) {
+	oldPath = strings.TrimSuffix(oldPath, "/")
+	newPath = strings.TrimSuffix(newPath, "/")
+
+	m := &mover{from: oldPath, to: newPath, importedBases: make(map[string]string)}
+	r := &Refactoring{Edits: make(map[string]string)}
+	if oldPath == newPath {
+		return r, nil
+	}
+
+	moves, err := p.plannedMoves(m)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not move %s", oldPath)
+	}
+	r.Moves = moves
+
+	for _, resPath := range p.Paths() {
+		file := p.Files[resPath]
+		if file.Err != nil {
+			continue
+		}
+
+		content, changed, err := p.rewriteFile(file, m)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not rewrite %s", resPath)
+		}
+		if changed {
+			newResPath, _ := m.rename(resPath)
+			r.Edits[newResPath] = content
+		}
+	}
+
+	return r, nil
+}
+
+// plannedMoves returns the moves of the file or directory, its companion files and its imported files
+func (p *Project) plannedMoves(m *mover) ([]Move, error) {
+	from, err := tscn.ResourcePathToFSPath(m.from)
+	if err != nil {
+		return nil, err
+	}
+	to, err := tscn.ResourcePathToFSPath(m.to)
+	if err != nil {
+		return nil, err
+	}
+	if from == "." || strings.HasPrefix(to+"/", from+"/") {
+		return nil, fmt.Errorf("can't move %s into itself", m.from)
+	}
+
+	info, err := fs.Stat(p.FS, from)
+	if err != nil {
+		return nil, err
+	}
+
+	moves := []Move{{From: m.from, To: m.to}}
+	var sources []string
+	if info.IsDir() {
+		err = fs.WalkDir(p.FS, from, func(name string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && path.Ext(name) == ".import" {
+				sources = append(sources, resourcePath(strings.TrimSuffix(name, ".import")))
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for _, extension := range companionExtensions {
+			if p.exists(m.from + extension) {
+				moves = append(moves, Move{From: m.from + extension, To: m.to + extension})
+			}
+		}
+		if p.exists(m.from + ".import") {
+			sources = append(sources, m.from)
+		}
+	}
+
+	for _, source := range sources {
+		target, _ := m.rename(source)
+		m.importedBases[importedBaseName(source)] = importedBaseName(target)
+	}
+	moves = append(moves, p.importedFileMoves(m)...)
+
+	for _, move := range moves {
+		if p.exists(move.To) {
+			return nil, fmt.Errorf("%s already exists", move.To)
+		}
+	}
+	return moves, nil
+}
+
+// importedFileMoves returns the moves of the files the importer created for the moved files
+func (p *Project) importedFileMoves(m *mover) []Move {
+	var moves []Move
+	for _, dir := range importedFileDirs {
+		entries, err := fs.ReadDir(p.FS, dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			from := resourcePath(path.Join(dir, entry.Name()))
+			if to, ok := m.renameImportedFile(from); ok {
+				moves = append(moves, Move{From: from, To: to})
+			}
+		}
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		return moves[i].From < moves[j].From
+	})
+	return moves
+}
+
+// importedBaseName returns the name Godot gives the imported files of a file without their extension
+func importedBaseName(resPath string) string {
+	hash := md5.Sum([]byte(resPath))
+	return path.Base(resPath) + "-" + hex.EncodeToString(hash[:])
+}
+
+// rename returns the path of a file after the move, false if the file isn't moved
+func (m *mover) rename(resPath string) (string, bool) {
+	switch {
+	case resPath == m.from:
+		return m.to, true
+	case strings.HasPrefix(resPath, m.from+"/"):
+		return m.to + strings.TrimPrefix(resPath, m.from), true
+	}
+
+	for _, extension := range companionExtensions {
+		if resPath == m.from+extension {
+			return m.to + extension, true
+		}
+	}
+	return m.renameImportedFile(resPath)
+}
+
+// renameImportedFile returns the new path of a file created by the importer for a moved file
+func (m *mover) renameImportedFile(resPath string) (string, bool) {
+	dir, name := path.Split(resPath)
+	for oldBase, newBase := range m.importedBases {
+		if strings.HasPrefix(name, oldBase+".") {
+			return dir + newBase + strings.TrimPrefix(name, oldBase), true
+		}
+	}
+	return resPath, false
+}
+
+// rewriteFile rewrites the paths within a file, the content is only returned if something changed
+func (p *Project) rewriteFile(file *File, m *mover) (string, bool, error) {
+	name, err := tscn.ResourcePathToFSPath(file.Path)
+	if err != nil {
+		return "", false, err
+	}
+	f, err := p.FS.Open(name)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	doc, err := tscn.ParseDocument(f, tscn.WithFilename(file.Path))
+	if err != nil {
+		return "", false, err
+	}
+
+	_, fileMoved := m.rename(file.Path)
+	changed := false
+
+	for _, section := range doc.Sections() {
+		for _, key := range section.AttributeKeys() {
+			value, err := section.Attribute(key)
+			if err != nil {
+				return "", false, err
+			}
+
+			// relative ext_resource paths break if the scene is moved, they are replaced by absolute paths
+			relative := section.Type() == "ext_resource" && key == "path"
+			rewritten, ok := rewritePaths(value, func(s string) (string, bool) {
+				if relative && !strings.HasPrefix(s, tscn.ResourcePathPrefix) {
+					resPath, resolved := resolveResourcePath(file.Path, s)
+					if !resolved {
+						return s, false
+					}
+					target, moved := m.rename(resPath)
+					return target, moved || fileMoved
+				}
+				return m.rename(s)
+			})
+			if !ok {
+				continue
+			}
+			if err := section.SetAttribute(key, rewritten); err != nil {
+				return "", false, err
+			}
+			changed = true
+		}
+
+		for _, key := range section.FieldKeys() {
+			value, err := section.Field(key)
+			if err != nil {
+				return "", false, err
+			}
+
+			rename := m.renameAutoload
+			if strings.HasSuffix(key, "_filter") {
+				rename = m.renameFilters
+			}
+			rewritten, ok := rewritePaths(value, rename)
+			if !ok {
+				continue
+			}
+			if err := section.SetField(key, rewritten); err != nil {
+				return "", false, err
+			}
+			changed = true
+		}
+	}
+
+	return doc.String(), changed, nil
+}
+
+// renameAutoload renames a path which might be an autoload path like *res://Game.gd
+func (m *mover) renameAutoload(s string) (string, bool) {
+	if strings.HasPrefix(s, autoloadSingletonPrefix) {
+		renamed, ok := m.rename(strings.TrimPrefix(s, autoloadSingletonPrefix))
+		return autoloadSingletonPrefix + renamed, ok
+	}
+	return m.rename(s)
+}
+
+// renameFilters renames the paths of comma separated export filters like "res://levels/*, *.json"
+func (m *mover) renameFilters(s string) (string, bool) {
+	filters := strings.Split(s, ",")
+	changed := false
+	for index, filter := range filters {
+		trimmed := strings.TrimSpace(filter)
+		if renamed, ok := m.rename(trimmed); ok {
+			filters[index] = strings.Replace(filter, trimmed, renamed, 1)
+			changed = true
+		}
+	}
+	return strings.Join(filters, ","), changed
+}
+
+// rewritePaths calls rename for every string within a value and returns the value with the renamed strings, false
+// if nothing was renamed
+func rewritePaths(value interface{}, rename func(s string) (string, bool)) (interface{}, bool) {
+	switch v := value.(type) {
+	case godot.Value:
+		rewritten, ok := rewritePaths(v.Value, rename)
+		v.Value = rewritten
+		return v, ok
+	case string:
+		return rename(v)
+	case godot.KeyValuePair:
+		rewritten, ok := rewritePaths(v.Value, rename)
+		v.Value = rewritten
+		return v, ok
+	case godot.Type:
+		params, ok := rewriteSlice(v.Parameters, rename)
+		v.Parameters = params
+		return v, ok
+	case godot.Object:
+		properties, ok := rewriteMap(v.Properties, rename)
+		v.Properties = properties
+		return v, ok
+	case []interface{}:
+		return rewriteSlice(v, rename)
+	case map[string]interface{}:
+		return rewriteMap(v, rename)
+	default:
+		return value, false
+	}
+}
+
+func rewriteSlice(values []interface{}, rename func(s string) (string, bool)) ([]interface{}, bool) {
+	result := make([]interface{}, len(values))
+	changed := false
+	for index, elem := range values {
+		rewritten, ok := rewritePaths(elem, rename)
+		result[index] = rewritten
+		changed = changed || ok
+	}
+	return result, changed
+}
+
+func rewriteMap(values map[string]interface{}, rename func(s string) (string, bool)) (map[string]interface{}, bool) {
+	result := make(map[string]interface{}, len(values))
+	changed := false
+	for key, elem := range values {
+		rewritten, ok := rewritePaths(elem, rename)
+		result[key] = rewritten
+		changed = changed || ok
+	}
+	return result, changed
+}
+
+// Apply moves the files and writes the edits, dir is the project directory the project was loaded from
+func (r *Refactoring) Apply(dir string) error {
+	for _, move := range r.Moves {
+		from, err := osPath(dir, move.From)
+		if err != nil {
+			return err
+		}
+		to, err := osPath(dir, move.To)
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+			return errors.Wrapf(err, "could not create directory for %s", move.To)
+		}
+		if err := os.Rename(from, to); err != nil {
+			return errors.Wrapf(err, "could not move %s", move.From)
+		}
+	}
+
+	paths := make([]string, 0, len(r.Edits))
+	for resPath := range r.Edits {
+		paths = append(paths, resPath)
+	}
+	sort.Strings(paths)
+
+	for _, resPath := range paths {
+		name, err := osPath(dir, resPath)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(name, []byte(r.Edits[resPath]), 0o644); err != nil {
+			return errors.Wrapf(err, "could not write %s", resPath)
+		}
+	}
+	return nil
+}
+
+func osPath(dir, resPath string) (string, error) {
+	name, err := tscn.ResourcePathToFSPath(resPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.FromSlash(name)), nil
+}
@@ -0,0 +1,260 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	iconImportedBase   = "icon.png-b6a7fb2db36edd3d95dc42f1dc8c1c5d"
+	playerImportedBase = "player.png-ce289a19efa81f268b1bb13630e9f80b"
+	artImportedBase    = "icon.png-c3d958876317c5b9ff9d73fef1b6123f"
+)
+
+const worldScene = `[gd_scene load_steps=3 format=2]
+
+[ext_resource path="res://assets/icon.png" type="Texture" id=1]
+[ext_resource path="res://Player.tscn" type="PackedScene" id=2]
+
+[node name="World" type="Node2D"]
+
+[node name="Icon" type="Sprite" parent="."]
+texture = ExtResource( 1 ) ; the logo
+
+[node name="Player" parent="." instance=ExtResource( 2 )]
+`
+
+const iconImport = `[remap]
+
+importer="texture"
+type="StreamTexture"
+path="res://.import/` + iconImportedBase + `.stex"
+
+[deps]
+
+source_file="res://assets/icon.png"
+dest_files=[ "res://.import/` + iconImportedBase + `.stex" ]
+
+[params]
+
+flags/filter=true
+`
+
+func newMoveTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"project.godot": {Data: []byte(`config_version=4
+
+[application]
+
+run/main_scene="res://levels/World.tscn"
+
+[autoload]
+
+Game="*res://Game.gd"
+`)},
+		"levels/World.tscn": {Data: []byte(worldScene)},
+		"Player.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="Player.gd" type="Script" id=1]
+
+[node name="Player" type="Node2D"]
+script = ExtResource( 1 )
+`)},
+		"Player.gd":                             {Data: []byte("extends Node2D\n")},
+		"Game.gd":                               {Data: []byte("extends Node\n")},
+		"assets/icon.png":                       {Data: []byte("png")},
+		"assets/icon.png.import":                {Data: []byte(iconImport)},
+		".import/" + iconImportedBase + ".stex": {Data: []byte("stex")},
+		".import/" + iconImportedBase + ".md5":  {Data: []byte("source_md5=\"1\"\n")},
+		"export_presets.cfg": {Data: []byte(`[preset.0]
+
+name="HTML5"
+export_filter="resources"
+export_files=PoolStringArray( "res://levels/World.tscn", "res://Player.tscn" )
+include_filter="res://assets/*, *.json"
+`)},
+	}
+}
+
+func TestMoveResource(t *testing.T) {
+	p := loadTestProject(t, newMoveTestFS())
+
+	r, err := p.MoveResource("res://assets/icon.png", "res://textures/player.png")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Move{
+		{From: "res://assets/icon.png", To: "res://textures/player.png"},
+		{From: "res://assets/icon.png.import", To: "res://textures/player.png.import"},
+		{From: "res://.import/" + iconImportedBase + ".md5", To: "res://.import/" + playerImportedBase + ".md5"},
+		{From: "res://.import/" + iconImportedBase + ".stex", To: "res://.import/" + playerImportedBase + ".stex"},
+	}, r.Moves)
+
+	assert.Len(t, r.Edits, 2)
+	assert.Equal(t, `[gd_scene load_steps=3 format=2]
+
+[ext_resource path="res://textures/player.png" type="Texture" id=1]
+[ext_resource path="res://Player.tscn" type="PackedScene" id=2]
+
+[node name="World" type="Node2D"]
+
+[node name="Icon" type="Sprite" parent="."]
+texture = ExtResource( 1 ) ; the logo
+
+[node name="Player" parent="." instance=ExtResource( 2 )]
+`, r.Edits["res://levels/World.tscn"])
+
+	assert.Equal(t, `[remap]
+
+importer="texture"
+type="StreamTexture"
+path="res://.import/`+playerImportedBase+`.stex"
+
+[deps]
+
+source_file="res://textures/player.png"
+dest_files=[ "res://.import/`+playerImportedBase+`.stex" ]
+
+[params]
+
+flags/filter=true
+`, r.Edits["res://textures/player.png.import"])
+}
+
+func TestMoveResourceDirectory(t *testing.T) {
+	p := loadTestProject(t, newMoveTestFS())
+
+	r, err := p.MoveResource("res://assets/", "res://art")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Move{
+		{From: "res://assets", To: "res://art"},
+		{From: "res://.import/" + iconImportedBase + ".md5", To: "res://.import/" + artImportedBase + ".md5"},
+		{From: "res://.import/" + iconImportedBase + ".stex", To: "res://.import/" + artImportedBase + ".stex"},
+	}, r.Moves)
+
+	assert.Contains(t, r.Edits["res://levels/World.tscn"], `[ext_resource path="res://art/icon.png" type="Texture" id=1]`)
+	assert.Contains(t, r.Edits["res://art/icon.png.import"], `source_file="res://art/icon.png"`)
+	assert.Contains(t, r.Edits["res://export_presets.cfg"], `include_filter="res://art/*, *.json"`)
+}
+
+func TestMoveResourceSettings(t *testing.T) {
+	p := loadTestProject(t, newMoveTestFS())
+
+	r, err := p.MoveResource("res://levels/World.tscn", "res://World.tscn")
+	assert.NoError(t, err)
+	assert.Contains(t, r.Edits["res://project.godot"], `run/main_scene="res://World.tscn"`)
+	assert.Contains(
+		t,
+		r.Edits["res://export_presets.cfg"],
+		`export_files=PoolStringArray( "res://World.tscn", "res://Player.tscn" )`,
+	)
+
+	r, err = p.MoveResource("res://Game.gd", "res://globals/Game.gd")
+	assert.NoError(t, err)
+	assert.Equal(t, []Move{{From: "res://Game.gd", To: "res://globals/Game.gd"}}, r.Moves)
+	assert.Contains(t, r.Edits["res://project.godot"], `Game="*res://globals/Game.gd"`)
+}
+
+func TestMoveResourceRelativePaths(t *testing.T) {
+	p := loadTestProject(t, newMoveTestFS())
+
+	r, err := p.MoveResource("res://Player.tscn", "res://actors/Player.tscn")
+	assert.NoError(t, err)
+
+	// the script stays where it is, thus the relative path has to change
+	assert.Contains(t, r.Edits["res://actors/Player.tscn"], `[ext_resource path="res://Player.gd" type="Script" id=1]`)
+	assert.Contains(t, r.Edits["res://levels/World.tscn"], `path="res://actors/Player.tscn"`)
+}
+
+func TestMoveResourceGodot4(t *testing.T) {
+	mainScene := `[gd_scene load_steps=2 format=3 uid="uid://cmain"]
+
+[ext_resource type="Texture2D" uid="uid://cicon" path="res://assets/icon.png" id="1_a"]
+
+[node name="Main" type="Sprite2D"]
+texture = ExtResource("1_a")
+metadata/icons = {
+&"main": "res://assets/icon.png",
+&"other": 3
+}
+`
+	p := loadTestProject(t, fstest.MapFS{
+		"project.godot":   {Data: []byte("config_version=5\n\n[application]\n\nrun/main_scene=\"res://Main.tscn\"\n")},
+		"Main.tscn":       {Data: []byte(mainScene)},
+		"assets/icon.png": {Data: []byte("png")},
+	})
+
+	r, err := p.MoveResource("res://assets/icon.png", "res://textures/icon.png")
+	assert.NoError(t, err)
+	assert.Equal(t, []Move{{From: "res://assets/icon.png", To: "res://textures/icon.png"}}, r.Moves)
+
+	// the StringName keys of the untouched entries stay StringNames
+	assert.Len(t, r.Edits, 1)
+	assert.Equal(t, `[gd_scene load_steps=2 format=3 uid="uid://cmain"]
+
+[ext_resource type="Texture2D" uid="uid://cicon" path="res://textures/icon.png" id="1_a"]
+
+[node name="Main" type="Sprite2D"]
+texture = ExtResource("1_a")
+metadata/icons = {
+&"main": "res://textures/icon.png",
+&"other": 3
+}
+`, r.Edits["res://Main.tscn"])
+}
+
+func TestMoveResourceErrors(t *testing.T) {
+	p := loadTestProject(t, newMoveTestFS())
+
+	_, err := p.MoveResource("res://missing.png", "res://icon.png")
+	assert.Error(t, err)
+
+	_, err = p.MoveResource("res://Player.tscn", "res://Player.gd")
+	assert.EqualError(t, err, "could not move res://Player.tscn: res://Player.gd already exists")
+
+	_, err = p.MoveResource("res://assets", "res://assets/icons")
+	assert.EqualError(t, err, "could not move res://assets: can't move res://assets into itself")
+
+	r, err := p.MoveResource("res://Player.tscn", "res://Player.tscn")
+	assert.NoError(t, err)
+	assert.Empty(t, r.Moves)
+	assert.Empty(t, r.Edits)
+}
+
+// keep integration tests at the bottom please
+
+func TestRefactoringApply(t *testing.T) {
+	dir := t.TempDir()
+	for name, file := range newMoveTestFS() {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(target), 0o755))
+		assert.NoError(t, os.WriteFile(target, file.Data, 0o644))
+	}
+
+	p, err := LoadDir(dir)
+	assert.NoError(t, err)
+
+	r, err := p.MoveResource("res://assets", "res://art/textures")
+	assert.NoError(t, err)
+	assert.NoError(t, r.Apply(dir))
+
+	_, err = os.Stat(filepath.Join(dir, "assets"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "art", "textures", "icon.png"))
+	assert.NoError(t, err)
+
+	p, err = LoadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, p.Errors)
+	assert.Empty(t, p.CheckReferences().Errors())
+
+	imp, ok := p.File("res://art/textures/icon.png.import")
+	assert.True(t, ok)
+	assert.Equal(t, "res://art/textures/icon.png", imp.Import.SourceFile)
+	_, err = os.Stat(filepath.Join(dir, filepath.FromSlash(imp.Import.Path[len("res://"):])))
+	assert.NoError(t, err)
+}
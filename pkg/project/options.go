@@ -0,0 +1,41 @@
+package project
+
+import (
+	"runtime"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/tscn"
+)
+
+// Option configures how a project is loaded, e.g. project.Load(fsys, project.WithWorkers(4))
+type Option func(o *options)
+
+type options struct {
+	workers      int
+	parseOptions []tscn.Option
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{workers: runtime.NumCPU()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.workers < 1 {
+		o.workers = 1
+	}
+	return o
+}
+
+// WithWorkers sets the number of files parsed concurrently, defaults to the number of CPUs
+func WithWorkers(workers int) Option {
+	return func(o *options) {
+		o.workers = workers
+	}
+}
+
+// WithParseOptions passes options like tscn.WithRules to the parser of every file. The filename is always set to the
+// res:// path of the file.
+func WithParseOptions(opts ...tscn.Option) Option {
+	return func(o *options) {
+		o.parseOptions = append(o.parseOptions, opts...)
+	}
+}
@@ -0,0 +1,352 @@
+// Package project loads all scenes, resources, import and config files of a Godot project at once
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+	"github.com/atomicptr/godot-tscn-parser/pkg/tscn"
+)
+
+const (
+	// ProjectFileName is the name of the project configuration in the project directory
+	ProjectFileName = "project.godot"
+	// ignoreFileName marks directories which are ignored by Godot and thus by the loader
+	ignoreFileName = ".gdignore"
+	// globalClassCachePath is the file Godot 4 stores the global classes in
+	globalClassCachePath = ".godot/global_script_class_cache.cfg"
+)
+
+// FileKind is the kind of a file determined by its extension
+type FileKind string
+
+const (
+	// KindProject is the project.godot file
+	KindProject FileKind = "project"
+	// KindScene are .tscn files
+	KindScene FileKind = "scene"
+	// KindResource are .tres files
+	KindResource FileKind = "resource"
+	// KindImport are .import files
+	KindImport FileKind = "import"
+	// KindConfig are .cfg files like export_presets.cfg
+	KindConfig FileKind = "config"
+	// KindUID are the .uid files Godot 4.4 creates for files without a header like scripts, e.g. Player.gd.uid
+	KindUID FileKind = "uid"
+)
+
+var kindsByExtension = map[string]FileKind{
+	".tscn":   KindScene,
+	".tres":   KindResource,
+	".import": KindImport,
+	".cfg":    KindConfig,
+	".uid":    KindUID,
+}
+
+// File is a parsed file of the project, depending on its kind one of the models is set. If the file couldn't be
+// parsed Err is set instead.
+type File struct {
+	// Path is the res:// path of the file
+	Path     string
+	Kind     FileKind
+	Project  *godot.Project
+	Scene    *godot.Scene
+	Resource *godot.Resource
+	Import   *godot.Import
+	Config   *godot.ConfigFile
+	// UID is the content of a .uid file
+	UID string
+	Err error
+}
+
+// FileError is an error of a single file, the loader collects them instead of aborting
+type FileError struct {
+	// Path is the res:// path of the file
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// Unwrap returns the error of the file
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// Project is the index of all files of a Godot project
+type Project struct {
+	// FS is the project directory, the directory containing project.godot
+	FS fs.FS
+	// Settings is the parsed project.godot, nil if it couldn't be parsed
+	Settings *godot.Project
+	// GlobalClasses contains the global classes of Godot 3 (project.godot) and Godot 4 projects (.godot cache)
+	GlobalClasses *godot.GlobalClassRegistry
+	// Files are the parsed files, the key is their res:// path
+	Files map[string]*File
+	// Errors are the errors of all files sorted by path, including files which couldn't be read
+	Errors []*FileError
+
+	uids *uidIndex
+}
+
+// LoadDir loads the project in the directory or one of its sub directories
+func LoadDir(dir string, opts ...Option) (*Project, error) {
+	return Load(os.DirFS(dir), opts...)
+}
+
+// Load loads the project in the file system, if project.godot isn't at the root the first project found in a sub
+// directory is used. Files are parsed concurrently, directories containing a .gdignore file and hidden directories
+// like .godot are skipped.
+func Load(fsys fs.FS, opts ...Option) (*Project, error) {
+	o := newOptions(opts)
+
+	root, err := findProjectRoot(fsys)
+	if err != nil {
+		return nil, err
+	}
+	if root != "." {
+		fsys, err = fs.Sub(fsys, root)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not open project directory %s", root)
+		}
+	}
+
+	p := &Project{
+		FS:            fsys,
+		GlobalClasses: godot.NewGlobalClassRegistry(),
+		Files:         make(map[string]*File),
+	}
+
+	names, walkErrors := findProjectFiles(fsys)
+	p.Errors = append(p.Errors, walkErrors...)
+
+	for _, file := range parseFiles(fsys, names, o) {
+		p.Files[file.Path] = file
+		if file.Err != nil {
+			p.Errors = append(p.Errors, &FileError{Path: file.Path, Err: file.Err})
+		}
+		if file.Kind == KindProject && file.Path == tscn.ResourcePathPrefix+ProjectFileName {
+			p.Settings = file.Project
+		}
+	}
+
+	p.loadGlobalClasses(o)
+	p.uids = newUIDIndex(p)
+
+	sort.SliceStable(p.Errors, func(i, j int) bool {
+		return p.Errors[i].Path < p.Errors[j].Path
+	})
+
+	return p, nil
+}
+
+// findProjectRoot returns the shallowest directory containing project.godot
+func findProjectRoot(fsys fs.FS) (string, error) {
+	if _, err := fs.Stat(fsys, ProjectFileName); err == nil {
+		return ".", nil
+	}
+
+	queue := []string{"."}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+
+		entries, err := fs.ReadDir(fsys, dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() || isHidden(entry.Name()) {
+				continue
+			}
+			sub := path.Join(dir, entry.Name())
+			if _, err := fs.Stat(fsys, path.Join(sub, ProjectFileName)); err == nil {
+				return sub, nil
+			}
+			queue = append(queue, sub)
+		}
+	}
+
+	return "", fmt.Errorf("could not find %s", ProjectFileName)
+}
+
+// findProjectFiles returns the paths of all files the loader can parse, directories which can't be read are returned
+// as errors
+func findProjectFiles(fsys fs.FS) ([]string, []*FileError) {
+	var names []string
+	var fileErrors []*FileError
+
+	_ = fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			fileErrors = append(fileErrors, &FileError{Path: resourcePath(name), Err: err})
+			if entry != nil && entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if entry.IsDir() {
+			if name == "." {
+				return nil
+			}
+			if isHidden(entry.Name()) {
+				return fs.SkipDir
+			}
+			if _, err := fs.Stat(fsys, path.Join(name, ignoreFileName)); err == nil {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if _, ok := kindOf(name); ok && !isHidden(entry.Name()) {
+			names = append(names, name)
+		}
+		return nil
+	})
+
+	return names, fileErrors
+}
+
+// parseFiles parses the files with a bounded number of workers and returns them in the order of names
+func parseFiles(fsys fs.FS, names []string, o *options) []*File {
+	files := make([]*File, len(names))
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < o.workers && worker < len(names); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				files[index] = parseFile(fsys, names[index], o)
+			}
+		}()
+	}
+
+	for index := range names {
+		indices <- index
+	}
+	close(indices)
+	wg.Wait()
+
+	return files
+}
+
+func parseFile(fsys fs.FS, name string, o *options) *File {
+	kind, _ := kindOf(name)
+	file := &File{Path: resourcePath(name), Kind: kind}
+
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		file.Err = errors.Wrap(err, "could not read file")
+		return file
+	}
+
+	r := bytes.NewReader(content)
+	opts := append(append([]tscn.Option(nil), o.parseOptions...), tscn.WithFilename(file.Path))
+
+	switch kind {
+	case KindProject:
+		file.Project, file.Err = tscn.ParseProject(r, opts...)
+	case KindScene:
+		file.Scene, file.Err = tscn.ParseScene(r, opts...)
+	case KindResource:
+		file.Resource, file.Err = tscn.ParseResource(r, opts...)
+	case KindImport:
+		file.Import, file.Err = tscn.ParseImport(r, opts...)
+	case KindConfig:
+		file.Config, file.Err = tscn.ParseConfig(r, opts...)
+	case KindUID:
+		file.UID, file.Err = parseUIDFile(content)
+	}
+	return file
+}
+
+// loadGlobalClasses reads the global classes from project.godot and from the class cache of Godot 4
+func (p *Project) loadGlobalClasses(o *options) {
+	if p.Settings != nil {
+		classes, err := tscn.DecodeGlobalClasses(p.Settings)
+		if err != nil {
+			p.Errors = append(p.Errors, &FileError{Path: tscn.ResourcePathPrefix + ProjectFileName, Err: err})
+		} else {
+			p.GlobalClasses.Merge(classes)
+		}
+	}
+
+	content, err := fs.ReadFile(p.FS, globalClassCachePath)
+	if err != nil {
+		return
+	}
+
+	cachePath := resourcePath(globalClassCachePath)
+	opts := append(append([]tscn.Option(nil), o.parseOptions...), tscn.WithFilename(cachePath))
+	classes, err := tscn.ParseGlobalClassCache(bytes.NewReader(content), opts...)
+	if err != nil {
+		p.Errors = append(p.Errors, &FileError{Path: cachePath, Err: err})
+		return
+	}
+	p.GlobalClasses.Merge(classes)
+}
+
+// File returns the file with the given res:// path
+func (p *Project) File(resPath string) (*File, bool) {
+	file, ok := p.Files[resPath]
+	return file, ok
+}
+
+// Paths returns the res:// paths of all files sorted by name
+func (p *Project) Paths() []string {
+	paths := make([]string, 0, len(p.Files))
+	for resPath := range p.Files {
+		paths = append(paths, resPath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// FilesOfKind returns all files of a kind sorted by their path, e.g. all scenes
+func (p *Project) FilesOfKind(kind FileKind) []*File {
+	var files []*File
+	for _, resPath := range p.Paths() {
+		if file := p.Files[resPath]; file.Kind == kind {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// Resolver returns a resolver for the res:// and uid:// paths of the project, e.g. for tscn.InstantiateScene
+func (p *Project) Resolver() tscn.Resolver {
+	return tscn.NewUIDResolver(tscn.NewFSResolver(p.FS), p.uidIndex().paths)
+}
+
+func kindOf(name string) (FileKind, bool) {
+	if path.Base(name) == ProjectFileName {
+		return KindProject, true
+	}
+	kind, ok := kindsByExtension[path.Ext(name)]
+	return kind, ok
+}
+
+func resourcePath(name string) string {
+	if name == "." {
+		return tscn.ResourcePathPrefix
+	}
+	return tscn.ResourcePathPrefix + name
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
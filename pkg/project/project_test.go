@@ -0,0 +1,200 @@
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+)
+
+// testExportPresets contains an Android preset, Godot writes keys with dashes like architectures/arm64-v8a into it
+const testExportPresets = `[preset.0]
+
+name="HTML5"
+
+[preset.1]
+
+name="Android"
+
+[preset.1.options]
+
+architectures/armeabi-v7a=false
+architectures/arm64-v8a=true
+`
+
+func newTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"project.godot": {Data: []byte(`config_version=4
+
+_global_script_classes=[ {
+"base": "Node2D",
+"class": "Player",
+"language": "GDScript",
+"path": "res://Player/Player.gd"
+} ]
+
+[application]
+
+config/name="Test"
+run/main_scene="res://World.tscn"
+`)},
+		"World.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Player/Player.tscn" type="PackedScene" id=1]
+
+[node name="World" type="Node2D"]
+
+[node name="Player" parent="." instance=ExtResource( 1 )]
+`)},
+		"Player/Player.tscn": {Data: []byte(`[gd_scene format=2]
+
+[node name="Player" type="Node2D"]
+`)},
+		"Player/Player.gd":         {Data: []byte("extends Node2D\n")},
+		"Player/Broken.tscn":       {Data: []byte("[gd_scene format=2]\n[node type=\"Node\"]\n")},
+		"default_env.tres":         {Data: []byte("[gd_resource type=\"Environment\" format=2]\n\n[resource]\n")},
+		"icon.png.import":          {Data: []byte("[remap]\n\nimporter=\"texture\"\n\n[deps]\n\nsource_file=\"res://icon.png\"\n")},
+		"export_presets.cfg":       {Data: []byte(testExportPresets)},
+		"addons/tool/.gdignore":    {Data: []byte{}},
+		"addons/tool/Tool.tscn":    {Data: []byte("not a scene")},
+		".import/icon.png-1.md5":   {Data: []byte("source_md5=\"1\"\n")},
+		".godot/imported/Old.tscn": {Data: []byte("not a scene")},
+	}
+}
+
+// loadTestProject loads the project of the file system and fails the test if one of its files couldn't be loaded
+func loadTestProject(t *testing.T, fsys fstest.MapFS) *Project {
+	p, err := Load(fsys)
+	assert.NoError(t, err)
+	assert.Empty(t, p.Errors)
+	return p
+}
+
+func TestLoad(t *testing.T) {
+	p, err := Load(newTestFS(), WithWorkers(2))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"res://Player/Broken.tscn",
+		"res://Player/Player.tscn",
+		"res://World.tscn",
+		"res://default_env.tres",
+		"res://export_presets.cfg",
+		"res://icon.png.import",
+		"res://project.godot",
+	}, p.Paths())
+
+	assert.NotNil(t, p.Settings)
+	assert.Equal(t, "Test", p.Settings.Settings().GetString("application/config/name", ""))
+
+	world, ok := p.File("res://World.tscn")
+	assert.True(t, ok)
+	assert.Equal(t, KindScene, world.Kind)
+	assert.Equal(t, "World", world.Scene.Name)
+
+	resource, _ := p.File("res://default_env.tres")
+	assert.Equal(t, "Environment", resource.Resource.Type)
+
+	imp, _ := p.File("res://icon.png.import")
+	assert.Equal(t, "res://icon.png", imp.Import.SourceFile)
+
+	presets, _ := p.File("res://export_presets.cfg")
+	assert.Equal(t, "HTML5", presets.Config.GetValue("preset.0", "name", nil))
+	assert.Equal(t, true, presets.Config.GetValue("preset.1.options", "architectures/arm64-v8a", nil))
+
+	assert.Len(t, p.FilesOfKind(KindScene), 3)
+
+	_, ok = p.GlobalClasses.Class("Player")
+	assert.True(t, ok)
+}
+
+func TestLoadCollectsFileErrors(t *testing.T) {
+	p, err := Load(newTestFS())
+	assert.NoError(t, err)
+
+	assert.Len(t, p.Errors, 1)
+	assert.Equal(t, "res://Player/Broken.tscn", p.Errors[0].Path)
+
+	broken, _ := p.File("res://Player/Broken.tscn")
+	assert.Nil(t, broken.Scene)
+	assert.Equal(t, p.Errors[0].Err, broken.Err)
+
+	var missing *diagnostic.MissingAttributeError
+	assert.True(t, errors.As(p.Errors[0], &missing))
+	assert.Equal(t, "res://Player/Broken.tscn", missing.Position.Filename)
+}
+
+func TestLoadFindsProjectInSubDirectory(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":                {Data: []byte("# Game")},
+		"game/project.godot":       {Data: []byte("config_version=5\n")},
+		"game/Main.tscn":           {Data: []byte("[gd_scene format=3]\n\n[node name=\"Main\" type=\"Node\"]\n")},
+		"other/deep/project.godot": {Data: []byte("config_version=5\n")},
+	}
+
+	p, err := Load(fsys)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"res://Main.tscn", "res://project.godot"}, p.Paths())
+
+	f, err := p.Resolver().Open("res://Main.tscn")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func TestLoadReadsGlobalClassCache(t *testing.T) {
+	fsys := fstest.MapFS{
+		"project.godot": {Data: []byte("config_version=5\n")},
+		".godot/global_script_class_cache.cfg": {Data: []byte(`list=Array[Dictionary]([{
+"base": &"Node",
+"class": &"StateMachine",
+"icon": "",
+"language": &"GDScript",
+"path": "res://StateMachine.gd"
+}])
+`)},
+	}
+
+	p, err := Load(fsys)
+	assert.NoError(t, err)
+	assert.Empty(t, p.Errors)
+
+	class, ok := p.GlobalClasses.ClassOfScript("res://StateMachine.gd")
+	assert.True(t, ok)
+	assert.Equal(t, "StateMachine", class.Name)
+}
+
+func TestLoadWithoutProject(t *testing.T) {
+	_, err := Load(fstest.MapFS{"Main.tscn": {Data: []byte("[gd_scene format=2]\n")}})
+	assert.Error(t, err)
+}
+
+// keep integration tests at the bottom please
+func TestIntegrationLoadDir(t *testing.T) {
+	dir, err := os.MkdirTemp("", "godot-project")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fixtures := filepath.Join("..", "..", "test", "fixtures")
+	for target, fixture := range map[string]string{
+		"project.godot":      "gdquest-godot-beginner-2d-platformer-game-project.godot",
+		"Player/Player.tscn": "uheartbeast-youtube-tutorials-action-rpg-player-player.tscn",
+		"World.tscn":         "uheartbeast-youtube-tutorials-action-rpg-world.tscn",
+		"player.png.import":  "uheartbeast-youtube-tutorials-action-rpg-player-player.png.import",
+	} {
+		content, err := os.ReadFile(filepath.Join(fixtures, fixture))
+		assert.NoError(t, err)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, target)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, target), content, 0o644))
+	}
+
+	p, err := LoadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, p.Errors)
+	assert.Len(t, p.Files, 4)
+	assert.Len(t, p.FilesOfKind(KindScene), 2)
+}
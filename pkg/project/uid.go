@@ -0,0 +1,201 @@
+package project
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+	"github.com/atomicptr/godot-tscn-parser/pkg/tscn"
+)
+
+// Rule IDs of the diagnostics reported by CheckUIDs
+const (
+	// RuleDuplicateUID reports uids which are declared by more than one file
+	RuleDuplicateUID = "duplicate-uid"
+	// RuleDanglingUID reports uid:// paths and ext_resource uids no file declares
+	RuleDanglingUID = "dangling-uid"
+	// RuleUIDPathMismatch reports ext_resources whose uid belongs to another file than their path
+	RuleUIDPathMismatch = "uid-path-mismatch"
+)
+
+// UID is a uid:// path declared by a file of the project
+type UID struct {
+	UID string
+	// Path is the res:// path of the file the uid belongs to, for imported files it's the source file
+	Path string
+	// Position is where the uid is declared
+	Position lexer.Position
+}
+
+// uidIndex contains the uids of all files of a project
+type uidIndex struct {
+	uids []UID
+	// paths maps uids to the first file declaring them
+	paths map[string]string
+	// byPath maps the res:// paths of files to their uid
+	byPath map[string]string
+}
+
+// newUIDIndex collects the uids of the headers of scenes and resources, the remap sections of .import files and the
+// .uid files
+func newUIDIndex(p *Project) *uidIndex {
+	index := &uidIndex{paths: make(map[string]string), byPath: make(map[string]string)}
+
+	for _, resPath := range p.Paths() {
+		file := p.Files[resPath]
+		switch {
+		case file.Scene != nil && file.Scene.UID != "":
+			index.add(UID{file.Scene.UID, resPath, file.Scene.LexerPosition})
+		case file.Resource != nil && file.Resource.UID != "":
+			index.add(UID{file.Resource.UID, resPath, file.Resource.LexerPosition})
+		case file.Import != nil && file.Import.UID != "":
+			source := strings.TrimSuffix(resPath, path.Ext(resPath))
+			index.add(UID{file.Import.UID, source, positionOf(file.Import.Remap["uid"])})
+		case file.Kind == KindUID && file.UID != "":
+			source := strings.TrimSuffix(resPath, path.Ext(resPath))
+			index.add(UID{file.UID, source, lexer.Position{Filename: resPath, Line: 1, Column: 1}})
+		}
+	}
+
+	sort.SliceStable(index.uids, func(i, j int) bool {
+		return index.uids[i].UID < index.uids[j].UID
+	})
+	return index
+}
+
+func (index *uidIndex) add(uid UID) {
+	index.uids = append(index.uids, uid)
+	index.byPath[uid.Path] = uid.UID
+	if _, ok := index.paths[uid.UID]; !ok {
+		index.paths[uid.UID] = uid.Path
+	}
+}
+
+func (index *uidIndex) resolve(uid string) (string, bool) {
+	resPath, ok := index.paths[uid]
+	return resPath, ok
+}
+
+// parseUIDFile parses a .uid file, which contains nothing but the uid
+func parseUIDFile(content []byte) (string, error) {
+	uid := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(uid, tscn.UIDPathPrefix) {
+		return "", fmt.Errorf("invalid uid: %s", uid)
+	}
+	return uid, nil
+}
+
+func (p *Project) uidIndex() *uidIndex {
+	if p.uids == nil {
+		return newUIDIndex(p)
+	}
+	return p.uids
+}
+
+// UIDs returns the uids declared by the files of the project sorted by uid and path: the uids of scenes and resources,
+// the uids of imported files in their .import files and the .uid files of scripts. The uid cache of the editor
+// (.godot/uid_cache.bin) isn't read.
+func (p *Project) UIDs() []UID {
+	return append([]UID(nil), p.uidIndex().uids...)
+}
+
+// ResolveUID returns the res:// path of a uid:// path, false if no file declares it. If multiple files declare the uid
+// the first one by path is returned.
+func (p *Project) ResolveUID(uid string) (string, bool) {
+	return p.uidIndex().resolve(uid)
+}
+
+// UIDOf returns the uid of a file, false if it has none
+func (p *Project) UIDOf(resPath string) (string, bool) {
+	uid, ok := p.uidIndex().byPath[resPath]
+	return uid, ok
+}
+
+// CheckUIDs checks the uids of the project: uids declared by multiple files, uid:// paths and ext_resource uids which
+// no file declares and ext_resources whose uid belongs to another file than their path. Files which couldn't be
+// parsed are skipped.
+func (p *Project) CheckUIDs() diagnostic.List {
+	index := p.uidIndex()
+
+	var diagnostics diagnostic.List
+	for _, uid := range index.uids {
+		if first := index.paths[uid.UID]; first != uid.Path {
+			d := diagnostic.New(uid.Position, "uid %s of %s is already used by %s", uid.UID, uid.Path, first)
+			d.RuleID = RuleDuplicateUID
+			diagnostics.Add(d)
+		}
+	}
+
+	for _, resPath := range p.Paths() {
+		file := p.Files[resPath]
+		if file.Err != nil {
+			continue
+		}
+
+		extResources, _ := resourcesOf(file)
+		for _, ext := range sortedExtResources(extResources) {
+			diagnostics.Add(p.checkExtResourceUID(file, ext)...)
+		}
+
+		for _, dependency := range p.unresolvedUIDs(file) {
+			d := diagnostic.New(dependency.Position, "%s of %s doesn't exist", dependency.To, dependency.Key)
+			d.RuleID = RuleDanglingUID
+			diagnostics.Add(d)
+		}
+	}
+
+	diagnostics.Sort()
+	return diagnostics
+}
+
+// checkExtResourceUID checks that the uid of an ext_resource exists and belongs to the file of its path
+func (p *Project) checkExtResourceUID(file *File, ext *godot.ExtResource) diagnostic.List {
+	uid := ext.UID
+	if strings.HasPrefix(ext.Path, tscn.UIDPathPrefix) {
+		uid = ext.Path
+	}
+	if uid == "" {
+		return nil
+	}
+
+	target, resolved := p.uidIndex().resolve(uid)
+	resPath, ok := resolveResourcePath(file.Path, ext.Path)
+	pos := ext.LexerPosition
+
+	switch {
+	case !resolved && ok && p.exists(resPath):
+		d := diagnostic.New(pos, "ext_resource %s has the unknown uid %s, its path is used instead", ext.ID, uid)
+		d.Severity = diagnostic.SeverityWarning
+		d.RuleID = RuleDanglingUID
+		return diagnostic.List{d}
+	case !resolved:
+		d := diagnostic.New(pos, "ext_resource %s points to the unknown uid %s", ext.ID, uid)
+		d.RuleID = RuleDanglingUID
+		return diagnostic.List{d}
+	case ok && target != resPath:
+		d := diagnostic.New(pos, "ext_resource %s has the uid of %s but the path %s", ext.ID, target, ext.Path)
+		d.Severity = diagnostic.SeverityWarning
+		d.RuleID = RuleUIDPathMismatch
+		return diagnostic.List{d}
+	default:
+		return nil
+	}
+}
+
+// unresolvedUIDs returns the uid:// paths within the settings of project.godot or a config file which no file declares
+func (p *Project) unresolvedUIDs(file *File) []Dependency {
+	index := p.uidIndex()
+
+	var unresolved []Dependency
+	settingPaths(file, func(key, target string, pos lexer.Position) {
+		if _, ok := index.resolve(target); strings.HasPrefix(target, tscn.UIDPathPrefix) && !ok {
+			unresolved = append(unresolved, Dependency{From: file.Path, To: target, Key: key, Position: pos})
+		}
+	})
+	return unresolved
+}
@@ -0,0 +1,126 @@
+package project
+
+import (
+	"io/ioutil"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newUIDTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"project.godot": {Data: []byte(`config_version=5
+
+[application]
+
+run/main_scene="uid://cmain"
+boot_splash/image="uid://cgone"
+`)},
+		"Main.tscn": {Data: []byte(`[gd_scene load_steps=5 format=3 uid="uid://cmain"]
+
+[ext_resource type="Texture2D" uid="uid://cicon" path="res://icon.svg" id="1_a"]
+[ext_resource type="Script" uid="uid://cscript" path="res://Main.gd" id="2_b"]
+[ext_resource type="PackedScene" uid="uid://cenemy" path="res://old/Enemy.tscn" id="3_c"]
+[ext_resource type="Script" uid="uid://cunknown" path="res://Main.gd" id="4_d"]
+[ext_resource type="PackedScene" path="uid://cnothing" id="5_e"]
+
+[node name="Main" type="Sprite2D"]
+texture = ExtResource("1_a")
+script = ExtResource("2_b")
+`)},
+		"Main.gd":     {Data: []byte("extends Sprite2D\n")},
+		"Main.gd.uid": {Data: []byte("uid://cscript\n")},
+		"Enemy.tscn":  {Data: []byte("[gd_scene format=3 uid=\"uid://cenemy\"]\n\n[node name=\"Enemy\" type=\"Node2D\"]\n")},
+		"Copy.tscn":   {Data: []byte("[gd_scene format=3 uid=\"uid://cenemy\"]\n\n[node name=\"Copy\" type=\"Node2D\"]\n")},
+		"icon.svg":    {Data: []byte("<svg/>")},
+		"icon.svg.import": {Data: []byte(
+			"[remap]\n\nimporter=\"texture\"\ntype=\"CompressedTexture2D\"\nuid=\"uid://cicon\"\n\n" +
+				"[deps]\n\nsource_file=\"res://icon.svg\"\n",
+		)},
+	}
+}
+
+func TestProjectUIDs(t *testing.T) {
+	p := loadTestProject(t, newUIDTestFS())
+
+	var declared [][2]string
+	for _, uid := range p.UIDs() {
+		declared = append(declared, [2]string{uid.UID, uid.Path})
+	}
+	assert.Equal(t, [][2]string{
+		{"uid://cenemy", "res://Copy.tscn"},
+		{"uid://cenemy", "res://Enemy.tscn"},
+		{"uid://cicon", "res://icon.svg"},
+		{"uid://cmain", "res://Main.tscn"},
+		{"uid://cscript", "res://Main.gd"},
+	}, declared)
+
+	resPath, ok := p.ResolveUID("uid://cicon")
+	assert.True(t, ok)
+	assert.Equal(t, "res://icon.svg", resPath)
+
+	_, ok = p.ResolveUID("uid://cgone")
+	assert.False(t, ok)
+
+	uid, ok := p.UIDOf("res://Main.gd")
+	assert.True(t, ok)
+	assert.Equal(t, "uid://cscript", uid)
+
+	_, ok = p.UIDOf("res://project.godot")
+	assert.False(t, ok)
+}
+
+func TestProjectResolverUIDs(t *testing.T) {
+	p := loadTestProject(t, newUIDTestFS())
+
+	f, err := p.Resolver().Open("uid://cscript")
+	assert.NoError(t, err)
+	content, err := ioutil.ReadAll(f)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	assert.Equal(t, "extends Sprite2D\n", string(content))
+}
+
+func TestDependencyGraphUIDs(t *testing.T) {
+	p := loadTestProject(t, newUIDTestFS())
+	g := p.DependencyGraph()
+
+	// the uid takes precedence over the outdated path like in Godot
+	assert.Equal(
+		t,
+		[]string{"res://Copy.tscn", "res://Main.gd", "res://icon.svg"},
+		g.Dependencies("res://Main.tscn"),
+	)
+	assert.Equal(t, []string{"res://Main.tscn"}, g.Dependencies("res://project.godot"))
+}
+
+func TestCheckUIDs(t *testing.T) {
+	p := loadTestProject(t, newUIDTestFS())
+
+	var rendered []string
+	for _, d := range p.CheckUIDs() {
+		rendered = append(rendered, d.String())
+	}
+
+	assert.Equal(t, []string{
+		"res://Enemy.tscn:1:1: error: uid uid://cenemy of res://Enemy.tscn is already used by res://Copy.tscn " +
+			"[duplicate-uid]",
+		"res://Main.tscn:5:1: warning: ext_resource 3_c has the uid of res://Copy.tscn but the path " +
+			"res://old/Enemy.tscn [uid-path-mismatch]",
+		"res://Main.tscn:6:1: warning: ext_resource 4_d has the unknown uid uid://cunknown, its path is used instead " +
+			"[dangling-uid]",
+		"res://Main.tscn:7:1: error: ext_resource 5_e points to the unknown uid uid://cnothing [dangling-uid]",
+		"res://project.godot:6:19: error: uid://cgone of application/boot_splash/image doesn't exist [dangling-uid]",
+	}, rendered)
+}
+
+func TestLoadInvalidUIDFile(t *testing.T) {
+	fsys := newUIDTestFS()
+	fsys["Main.gd.uid"] = &fstest.MapFile{Data: []byte("cscript\n")}
+
+	p, err := Load(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, p.Errors, 1)
+	assert.EqualError(t, p.Errors[0], "res://Main.gd.uid: invalid uid: cscript")
+}
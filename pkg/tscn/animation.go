@@ -0,0 +1,63 @@
+package tscn
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// DecodeAnimation decodes a sub resource of the type Animation, e.g. the animations of an AnimationPlayer. The format is
+// the one of the scene containing the sub resource
+func DecodeAnimation(subResource *godot.SubResource, format int64) (*godot.Animation, error) {
+	if subResource.Type != convert.AnimationResourceType {
+		return nil, fmt.Errorf(
+			"sub resource %s is of type %s, expected %s %s",
+			subResource.ID,
+			subResource.Type,
+			convert.AnimationResourceType,
+			subResource.LexerPosition,
+		)
+	}
+	return convert.ToGodotAnimation(subResource.Fields, format, subResource.LexerPosition)
+}
+
+// DecodeAnimationResource decodes a .tres file of the type Animation
+func DecodeAnimationResource(resource *godot.Resource) (*godot.Animation, error) {
+	if resource.Type != convert.AnimationResourceType {
+		return nil, fmt.Errorf(
+			"resource is of type %s, expected %s %s",
+			resource.Type,
+			convert.AnimationResourceType,
+			resource.LexerPosition,
+		)
+	}
+	return convert.ToGodotAnimation(resource.Fields, resource.Format, resource.LexerPosition)
+}
+
+// ParseAnimation parses a .tres file of the type Animation
+func ParseAnimation(r io.Reader, opts ...Option) (*godot.Animation, error) {
+	resource, err := ParseResource(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeAnimationResource(resource)
+}
+
+// SceneAnimations decodes all animation sub resources of a scene, the key is the sub resource ID
+func SceneAnimations(scene *godot.Scene) (map[string]*godot.Animation, error) {
+	animations := make(map[string]*godot.Animation)
+	for id, subResource := range scene.SubResources {
+		if subResource.Type != convert.AnimationResourceType {
+			continue
+		}
+
+		animation, err := DecodeAnimation(subResource, scene.Format)
+		if err != nil {
+			return nil, err
+		}
+		animations[id] = animation
+	}
+	return animations, nil
+}
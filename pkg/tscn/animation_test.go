@@ -0,0 +1,112 @@
+package tscn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestDecodeAnimation(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=2]
+
+[sub_resource type="Animation" id=1]
+resource_name = "Idle"
+length = 0.1
+loop = true
+tracks/0/type = "value"
+tracks/0/path = NodePath("Sprite:frame")
+tracks/0/keys = {
+"times": PoolRealArray( 0 ),
+"transitions": PoolRealArray( 1 ),
+"update": 1,
+"values": [ 4 ]
+}
+
+[node name="AnimationPlayer" type="AnimationPlayer"]
+anims/Idle = SubResource( 1 )
+`
+	scene, err := ParseScene(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	animation, err := DecodeAnimation(scene.SubResources["1"], scene.Format)
+	assert.NoError(t, err)
+	assert.Equal(t, "Idle", animation.Name)
+	assert.True(t, animation.IsLooping())
+	assert.Equal(t, 0.1, animation.Step)
+
+	key, ok := animation.Tracks[0].KeyAt(0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), key.Value.(godot.Value).Value)
+}
+
+func TestDecodeAnimationWithWrongType(t *testing.T) {
+	_, err := DecodeAnimation(&godot.SubResource{Type: "RectangleShape2D", ID: "1"}, godot.FormatVersionGodot3)
+	assert.Error(t, err)
+
+	_, err = DecodeAnimationResource(&godot.Resource{Type: "Theme"})
+	assert.Error(t, err)
+}
+
+func TestParseAnimation(t *testing.T) {
+	content := `[gd_resource type="Animation" format=3]
+
+[resource]
+resource_name = "walk"
+length = 0.8
+loop_mode = 1
+tracks/0/type = "method"
+tracks/0/path = NodePath(".")
+tracks/0/keys = {
+"times": PackedFloat32Array(0.4),
+"transitions": PackedFloat32Array(1),
+"values": [{
+"args": [],
+"method": &"footstep"
+}]
+}
+`
+	animation, err := ParseAnimation(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, 0.8, animation.Length)
+	assert.Equal(t, godot.AnimationLoopLinear, animation.LoopMode)
+
+	call := animation.TracksOfType(godot.AnimationTrackMethod)[0].Keys[0].Value.(godot.AnimationMethodCall)
+	assert.Equal(t, "footstep", call.Method)
+
+	_, err = ParseAnimation(strings.NewReader(`[gd_resource type="Theme" format=3]`))
+	assert.Error(t, err)
+}
+
+func TestIntegrationSceneAnimations(t *testing.T) {
+	file, err := os.Open(filepath.Join("..", "..", "test", "fixtures", "uheartbeast-youtube-tutorials-action-rpg-player-player.tscn"))
+	assert.NoError(t, err)
+	defer file.Close()
+
+	scene, err := ParseScene(file)
+	assert.NoError(t, err)
+
+	animations, err := SceneAnimations(scene)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, animations)
+
+	attackDown := animations["2"]
+	assert.Equal(t, "AttackDown", attackDown.Name)
+	assert.Equal(t, 0.4, attackDown.Length)
+	assert.Equal(t, "Sprite:frame", attackDown.Tracks[0].Path)
+	assert.Len(t, attackDown.Tracks[0].Keys, 4)
+
+	methodCalls := attackDown.TracksOfType(godot.AnimationTrackMethod)
+	assert.Len(t, methodCalls, 1)
+	assert.Equal(t, "attack_animation_finished", methodCalls[0].Keys[0].Value.(godot.AnimationMethodCall).Method)
+
+	rollDown := animations["10"]
+	audio := rollDown.TracksOfType(godot.AnimationTrackAudio)
+	assert.Len(t, audio, 1)
+	assert.Equal(t, "AudioStreamPlayer", audio[0].Path)
+	assert.True(t, audio[0].Keys[0].Value.(godot.AnimationAudioClip).Stream.External)
+}
@@ -0,0 +1,11 @@
+package tscn
+
+import (
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// DecodeAutoloads decodes the autoload section of a project, the autoloads are in the order they are loaded
+func DecodeAutoloads(project *godot.Project) ([]*godot.Autoload, error) {
+	return convert.ToGodotAutoloads(project)
+}
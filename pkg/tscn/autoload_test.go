@@ -0,0 +1,42 @@
+package tscn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeAutoloads(t *testing.T) {
+	content := `config_version=4
+
+[autoload]
+
+Game="*res://globals/Game.gd"
+Music="*res://globals/Music.tscn"
+DebugTools="res://debug/DebugTools.gd"
+`
+	project, err := ParseProject(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	autoloads, err := DecodeAutoloads(project)
+	assert.NoError(t, err)
+	assert.Len(t, autoloads, 3)
+
+	assert.Equal(t, "Game", autoloads[0].Name)
+	assert.Equal(t, "res://globals/Game.gd", autoloads[0].Path)
+	assert.True(t, autoloads[0].Singleton)
+	assert.Equal(t, 5, autoloads[0].LexerPosition.Line)
+
+	assert.Equal(t, "Music", autoloads[1].Name)
+	assert.Equal(t, "DebugTools", autoloads[2].Name)
+	assert.False(t, autoloads[2].Singleton)
+}
+
+func TestDecodeAutoloadsWithInvalidPath(t *testing.T) {
+	project, err := ParseProject(strings.NewReader("config_version=4\n\n[autoload]\n\nGame=42\n"))
+	assert.NoError(t, err)
+
+	_, err = DecodeAutoloads(project)
+	assert.Error(t, err)
+}
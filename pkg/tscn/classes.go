@@ -0,0 +1,42 @@
+package tscn
+
+import (
+	"io"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// DecodeGlobalClasses decodes the global classes (scripts with a class_name) of a Godot 3 project. Godot 4 projects
+// store them in .godot/global_script_class_cache.cfg, see ParseGlobalClassCache.
+func DecodeGlobalClasses(project *godot.Project) (*godot.GlobalClassRegistry, error) {
+	return convert.ToGodotGlobalClasses(project)
+}
+
+// ParseGlobalClassCache parses the .godot/global_script_class_cache.cfg file of a Godot 4 project
+func ParseGlobalClassCache(r io.Reader, opts ...Option) (*godot.GlobalClassRegistry, error) {
+	config, err := ParseConfig(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return convert.ToGodotGlobalClassCache(config)
+}
+
+// NodeScriptClass returns the global class of the script attached to a node, false if the node has no script or the
+// script doesn't have a class_name
+func NodeScriptClass(
+	scene *godot.Scene,
+	node *godot.Node,
+	registry *godot.GlobalClassRegistry,
+) (*godot.GlobalClass, bool) {
+	ref, ok := convert.ReferenceOf(node.Fields["script"])
+	if !ok || !ref.External {
+		return nil, false
+	}
+
+	script, ok := scene.ExtResources[ref.ID]
+	if !ok {
+		return nil, false
+	}
+	return registry.ClassOfScript(script.Path)
+}
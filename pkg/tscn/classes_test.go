@@ -0,0 +1,101 @@
+package tscn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeGlobalClasses(t *testing.T) {
+	content := `config_version=4
+
+_global_script_classes=[ {
+"base": "KinematicBody2D",
+"class": "Actor",
+"language": "GDScript",
+"path": "res://src/Actors/Actor.gd"
+}, {
+"base": "Actor",
+"class": "Player",
+"language": "GDScript",
+"path": "res://src/Actors/Player.gd"
+} ]
+_global_script_class_icons={
+"Actor": "res://assets/actor.svg",
+"Player": ""
+}
+
+[application]
+
+config/name="Test"
+`
+	project, err := ParseProject(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	registry, err := DecodeGlobalClasses(project)
+	assert.NoError(t, err)
+	assert.Len(t, registry.Classes(), 2)
+
+	actor, ok := registry.Class("Actor")
+	assert.True(t, ok)
+	assert.Equal(t, "KinematicBody2D", actor.Base)
+	assert.Equal(t, "res://assets/actor.svg", actor.Icon)
+	assert.Equal(t, 3, actor.LexerPosition.Line)
+
+	player, ok := registry.ClassOfScript("res://src/Actors/Player.gd")
+	assert.True(t, ok)
+	assert.Equal(t, "Player", player.Name)
+	assert.True(t, registry.Inherits("Player", "KinematicBody2D"))
+	assert.Equal(t, "KinematicBody2D", registry.NativeBase("Player"))
+}
+
+func TestParseGlobalClassCache(t *testing.T) {
+	content := `list=Array[Dictionary]([{
+"base": &"CharacterBody2D",
+"class": &"Player",
+"icon": "res://player.svg",
+"language": &"GDScript",
+"path": "res://player.gd"
+}])
+`
+	registry, err := ParseGlobalClassCache(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	player, ok := registry.Class("Player")
+	assert.True(t, ok)
+	assert.Equal(t, "CharacterBody2D", player.Base)
+	assert.Equal(t, "res://player.svg", player.Icon)
+	assert.Equal(t, "GDScript", player.Language)
+}
+
+func TestNodeScriptClass(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://src/Actors/Player.gd" type="Script" id=1]
+
+[node name="Player" type="KinematicBody2D"]
+script = ExtResource( 1 )
+
+[node name="Sprite" type="Sprite" parent="."]
+`
+	scene, err := ParseScene(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	registry, err := ParseGlobalClassCache(strings.NewReader(`list=[{
+"base": &"KinematicBody2D",
+"class": &"Player",
+"path": "res://src/Actors/Player.gd"
+}]
+`))
+	assert.NoError(t, err)
+
+	class, ok := NodeScriptClass(scene, scene.Node, registry)
+	assert.True(t, ok)
+	assert.Equal(t, "Player", class.Name)
+
+	sprite, err := scene.GetNode("Sprite")
+	assert.NoError(t, err)
+	_, ok = NodeScriptClass(scene, sprite, registry)
+	assert.False(t, ok)
+}
@@ -0,0 +1,218 @@
+package tscn
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// Document is a lossless representation of a TSCN file which keeps comments and formatting. Writing an unmodified
+// document reproduces the original file byte by byte and changing a value only changes its line.
+type Document struct {
+	doc *parser.Document
+}
+
+// DocumentSection is a section of a Document like [node name="Player"]
+type DocumentSection struct {
+	section *parser.Section
+	doc     *Document
+}
+
+// ParseDocument parses any TSCN file (.tscn, .tres, project.godot, .import) into a Document
+func ParseDocument(r io.Reader, opts ...Option) (*Document, error) {
+	doc, err := parser.ParseDocumentFile(newOptions(opts).filename, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "parser error")
+	}
+	return &Document{doc: doc}, nil
+}
+
+// String returns the content of the document
+func (d *Document) String() string {
+	return d.doc.String()
+}
+
+// WriteTo writes the content of the document to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	return d.doc.WriteTo(w)
+}
+
+// Sections returns all sections of the document, fields in front of the first section header (e.g. config_version
+// in project.godot) are part of a section without a type
+func (d *Document) Sections() []*DocumentSection {
+	sections := make([]*DocumentSection, len(d.doc.Sections))
+	for index, section := range d.doc.Sections {
+		sections[index] = &DocumentSection{section: section, doc: d}
+	}
+	return sections
+}
+
+// SectionsOfType returns all sections of the given type, e.g. "node"
+func (d *Document) SectionsOfType(resourceType string) []*DocumentSection {
+	var sections []*DocumentSection
+	for _, section := range d.Sections() {
+		if section.Type() == resourceType {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// AddSection appends a new section to the end of the document
+func (d *Document) AddSection(resourceType string) *DocumentSection {
+	return &DocumentSection{section: d.doc.AddSection(resourceType), doc: d}
+}
+
+// RemoveSection removes a section from the document, returns false if the section isn't part of it
+func (d *Document) RemoveSection(section *DocumentSection) bool {
+	return d.doc.RemoveSection(section.section)
+}
+
+// Scene converts the current state of the document into a scene
+func (d *Document) Scene() (*godot.Scene, error) {
+	tscn, err := d.validatedFile()
+	if err != nil {
+		return nil, err
+	}
+	return convert.ToGodotScene(tscn)
+}
+
+// Resource converts the current state of the document into a resource
+func (d *Document) Resource() (*godot.Resource, error) {
+	tscn, err := d.validatedFile()
+	if err != nil {
+		return nil, err
+	}
+	return convert.ToGodotResource(tscn)
+}
+
+// Project converts the current state of the document into a project
+func (d *Document) Project() (*godot.Project, error) {
+	tscn, err := d.validatedFile()
+	if err != nil {
+		return nil, err
+	}
+	return convert.ToGodotProject(tscn)
+}
+
+func (d *Document) validatedFile() (*parser.TscnFile, error) {
+	return parseAndValidateTscnFile(strings.NewReader(d.String()), newOptions(nil))
+}
+
+// fieldSeparator returns the assignment used by the existing fields of the document, e.g. " = " for scenes
+func (d *Document) fieldSeparator(options parser.PrintOptions) string {
+	for _, section := range d.doc.Sections {
+		if len(section.Fields) > 0 {
+			return section.Fields[0].Separator
+		}
+	}
+	if options.ConfigFile {
+		return "="
+	}
+	return " = "
+}
+
+// Type returns the type of the section, e.g. "node" or "ext_resource"
+func (s *DocumentSection) Type() string {
+	return s.section.ResourceType
+}
+
+// Position returns the position of the section header, for the section without a type it's the position of its
+// first field
+func (s *DocumentSection) Position() lexer.Position {
+	return s.section.Pos
+}
+
+// AttributePosition returns the position of a header attribute
+func (s *DocumentSection) AttributePosition(key string) (lexer.Position, bool) {
+	return entryPosition(s.section.Attribute(key))
+}
+
+// FieldPosition returns the position of a field
+func (s *DocumentSection) FieldPosition(key string) (lexer.Position, bool) {
+	return entryPosition(s.section.Field(key))
+}
+
+// AttributeKeys returns the keys of all header attributes in the order they appear in
+func (s *DocumentSection) AttributeKeys() []string {
+	return entryKeys(s.section.Attributes)
+}
+
+// FieldKeys returns the keys of all fields in the order they appear in
+func (s *DocumentSection) FieldKeys() []string {
+	return entryKeys(s.section.Fields)
+}
+
+// Attribute returns the value of a header attribute
+func (s *DocumentSection) Attribute(key string) (interface{}, error) {
+	return entryValue(s.section.Attribute(key), "attribute", key, s.section)
+}
+
+// Field returns the value of a field
+func (s *DocumentSection) Field(key string) (interface{}, error) {
+	return entryValue(s.section.Field(key), "field", key, s.section)
+}
+
+// SetAttribute changes the value of a header attribute or adds it
+func (s *DocumentSection) SetAttribute(key string, value interface{}) error {
+	raw, err := convert.FormatGodotValue(value, convert.PrintOptionsForDocument(s.doc.doc))
+	if err != nil {
+		return errors.Wrapf(err, "could not set attribute %s", key)
+	}
+	s.section.SetAttribute(key, raw)
+	return nil
+}
+
+// SetField changes the value of a field in place or appends it to the end of the section
+func (s *DocumentSection) SetField(key string, value interface{}) error {
+	options := convert.PrintOptionsForDocument(s.doc.doc)
+	raw, err := convert.FormatGodotValue(value, options)
+	if err != nil {
+		return errors.Wrapf(err, "could not set field %s", key)
+	}
+	s.section.SetField(key, raw, s.doc.fieldSeparator(options))
+	return nil
+}
+
+// RemoveAttribute removes a header attribute, returns false if it doesn't exist
+func (s *DocumentSection) RemoveAttribute(key string) bool {
+	return s.section.RemoveAttribute(key)
+}
+
+// RemoveField removes a field and the comments in front of it, returns false if it doesn't exist
+func (s *DocumentSection) RemoveField(key string) bool {
+	return s.section.RemoveField(key)
+}
+
+func entryKeys(entries []*parser.Entry) []string {
+	keys := make([]string, len(entries))
+	for index, entry := range entries {
+		keys[index] = entry.Key
+	}
+	return keys
+}
+
+func entryPosition(entry *parser.Entry) (lexer.Position, bool) {
+	if entry == nil {
+		return lexer.Position{}, false
+	}
+	return entry.Pos, true
+}
+
+func entryValue(entry *parser.Entry, kind, key string, section *parser.Section) (interface{}, error) {
+	if entry == nil {
+		return nil, fmt.Errorf("unknown %s in %s: %s", kind, section.Pos, key)
+	}
+	value, err := entry.Value()
+	if err != nil {
+		return nil, err
+	}
+	return convert.ToGodotValue(value), nil
+}
@@ -0,0 +1,153 @@
+package tscn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+const playerDocument = `[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Player.gd" type="Script" id=1]
+
+[node name="Player" type="KinematicBody2D"]
+script = ExtResource( 1 )
+; pixels per second
+speed = 100 ; tweaked in playtesting
+position = Vector2( 12, 4 )
+`
+
+func TestDocumentSetFieldKeepsFormatting(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(playerDocument))
+	assert.NoError(t, err)
+	assert.Equal(t, playerDocument, doc.String())
+
+	player := doc.SectionsOfType("node")[0]
+	assert.NoError(t, player.SetField("speed", 250))
+	assert.NoError(t, player.SetField("scale", godot.Type{Identifier: "Vector2", Parameters: []interface{}{2.0, 2.0}}))
+
+	assert.Equal(t, `[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Player.gd" type="Script" id=1]
+
+[node name="Player" type="KinematicBody2D"]
+script = ExtResource( 1 )
+; pixels per second
+speed = 250 ; tweaked in playtesting
+position = Vector2( 12, 4 )
+scale = Vector2( 2, 2 )
+`, doc.String())
+}
+
+func TestDocumentGetValues(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(playerDocument))
+	assert.NoError(t, err)
+
+	player := doc.SectionsOfType("node")[0]
+	assert.Equal(t, []string{"name", "type"}, player.AttributeKeys())
+	assert.Equal(t, []string{"script", "speed", "position"}, player.FieldKeys())
+
+	name, err := player.Attribute("name")
+	assert.NoError(t, err)
+	assert.Equal(t, "Player", name.(godot.Value).Value)
+
+	speed, err := player.Field("speed")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), speed.(godot.Value).Value)
+
+	_, err = player.Field("unknown")
+	assert.Error(t, err)
+}
+
+func TestDocumentPositions(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(playerDocument), WithFilename("Player.tscn"))
+	assert.NoError(t, err)
+
+	player := doc.SectionsOfType("node")[0]
+	assert.Equal(t, "Player.tscn", player.Position().Filename)
+
+	namePos, ok := player.AttributePosition("name")
+	assert.True(t, ok)
+	assert.Equal(t, player.Position().Line, namePos.Line)
+
+	speedPos, ok := player.FieldPosition("speed")
+	assert.True(t, ok)
+	assert.Greater(t, speedPos.Line, namePos.Line)
+
+	_, ok = player.FieldPosition("unknown")
+	assert.False(t, ok)
+}
+
+func TestDocumentConvertsToScene(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(playerDocument))
+	assert.NoError(t, err)
+
+	player := doc.SectionsOfType("node")[0]
+	assert.True(t, player.RemoveField("speed"))
+	assert.NoError(t, player.SetAttribute("name", "Hero"))
+
+	scene, err := doc.Scene()
+	assert.NoError(t, err)
+	assert.Equal(t, "Hero", scene.Node.Name)
+	assert.NotContains(t, scene.Node.Fields, "speed")
+	assert.NotContains(t, doc.String(), "pixels per second")
+}
+
+func TestDocumentWithConfigFileFormat(t *testing.T) {
+	content := `config_version=4
+
+[application]
+
+config/name="Test"
+`
+	doc, err := ParseDocument(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	application := doc.SectionsOfType("application")[0]
+	assert.NoError(t, application.SetField("run/main_scene", "res://Main.tscn"))
+
+	assert.Equal(t, `config_version=4
+
+[application]
+
+config/name="Test"
+run/main_scene="res://Main.tscn"
+`, doc.String())
+}
+
+// keep integration tests at the bottom please
+func TestIntegrationDocumentFixturesRoundTrip(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(cwd, "..", "..", "test", "fixtures", "*"))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, file := range files {
+		// ignore the README.md file
+		if filepath.Base(file) == "README.md" {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			panic(err)
+		}
+
+		doc, err := ParseDocument(strings.NewReader(string(content)))
+		if !assert.NoError(t, err, file) {
+			continue
+		}
+
+		assert.Equal(t, string(content), doc.String(), file)
+	}
+}
@@ -0,0 +1,107 @@
+package tscn
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// InheritedScene is a scene whose root node instances a base scene. Its nodes either override nodes of the base
+// scene or add new nodes to it.
+type InheritedScene struct {
+	// Scene is the inheriting scene as it was parsed
+	Scene *godot.Scene
+	// BasePath is the res:// path of the base scene
+	BasePath string
+	// Base is the instantiated node tree of the base scene
+	Base *godot.Node
+	// Node is the instantiated node tree of the inheriting scene with all overrides and added nodes applied
+	Node *godot.Node
+}
+
+// LoadInheritedScene loads the base scene of an inherited scene through the resolver and merges both
+func LoadInheritedScene(scene *godot.Scene, resolver Resolver) (*InheritedScene, error) {
+	if !scene.IsInherited() {
+		return nil, fmt.Errorf("scene is not an inherited scene %s", scene.LexerPosition)
+	}
+
+	ref, ok := convert.ReferenceOf(scene.Node.Instance)
+	if !ok || !ref.External {
+		return nil, fmt.Errorf("root node has an invalid instance %s", scene.Node.LexerPosition)
+	}
+
+	ext, ok := scene.ExtResources[ref.ID]
+	if !ok {
+		return nil, fmt.Errorf("root node instances unknown external resource %s %s", ref.ID, scene.Node.LexerPosition)
+	}
+
+	i := newInstantiator(resolver)
+
+	baseScene, err := i.loadScene(ext.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := i.instantiate(baseScene, []string{ext.Path})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not instantiate base scene %s", ext.Path)
+	}
+
+	node, err := i.instantiate(scene, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InheritedScene{
+		Scene:    scene,
+		BasePath: ext.Path,
+		Base:     base,
+		Node:     node,
+	}, nil
+}
+
+// GetNode retrieves a node of the merged node tree for a given path
+func (s *InheritedScene) GetNode(path string) (*godot.Node, error) {
+	return s.Node.GetNode(path)
+}
+
+// IsInheritedNode checks if a node of the merged tree exists in the base scene
+func (s *InheritedScene) IsInheritedNode(node *godot.Node) bool {
+	_, err := s.Base.GetNode(node.Path())
+	return err == nil
+}
+
+// IsLocalNode checks if a node of the merged tree has been added by the inheriting scene
+func (s *InheritedScene) IsLocalNode(node *godot.Node) bool {
+	return !s.IsInheritedNode(node)
+}
+
+// LocalFields returns the sorted keys of all fields the inheriting scene sets on a node of the merged tree, for
+// inherited nodes these are the overridden properties
+func (s *InheritedScene) LocalFields(node *godot.Node) []string {
+	local, err := s.Scene.Node.GetNode(node.Path())
+	if err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(local.Fields))
+	for key := range local.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsLocalField checks if the inheriting scene sets the field of a node of the merged tree
+func (s *InheritedScene) IsLocalField(node *godot.Node, key string) bool {
+	local, err := s.Scene.Node.GetNode(node.Path())
+	if err != nil {
+		return false
+	}
+	_, ok := local.Fields[key]
+	return ok
+}
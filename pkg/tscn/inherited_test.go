@@ -0,0 +1,89 @@
+package tscn
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+var inheritedTestProject = fstest.MapFS{
+	"UI/BaseDialog.tscn": {Data: []byte(`[gd_scene format=2]
+
+[node name="BaseDialog" type="Control"]
+modulate = Color( 1, 1, 1, 0.9 )
+
+[node name="Panel" type="Panel" parent="."]
+
+[node name="Title" type="Label" parent="Panel"]
+text = "Title"
+align = 1
+
+[node name="Close" type="Button" parent="Panel"]
+`)},
+}
+
+const inheritedTestScene = `[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://UI/BaseDialog.tscn" type="PackedScene" id=1]
+
+[node name="QuitDialog" instance=ExtResource( 1 )]
+
+[node name="Title" parent="Panel" index="0"]
+text = "Quit?"
+
+[node name="Confirm" type="Button" parent="Panel" index="1"]
+text = "Yes"
+`
+
+func TestLoadInheritedScene(t *testing.T) {
+	scene, err := ParseScene(strings.NewReader(inheritedTestScene))
+	assert.NoError(t, err)
+	assert.True(t, scene.IsInherited())
+
+	inherited, err := LoadInheritedScene(scene, NewFSResolver(inheritedTestProject))
+	assert.NoError(t, err)
+	assert.Equal(t, "res://UI/BaseDialog.tscn", inherited.BasePath)
+	assert.Equal(t, "QuitDialog", inherited.Node.Name)
+	assert.Equal(t, "Control", inherited.Node.Type)
+
+	panel, err := inherited.GetNode("Panel")
+	assert.NoError(t, err)
+
+	var names []string
+	for _, child := range panel.Children() {
+		names = append(names, child.Name)
+	}
+	assert.Equal(t, []string{"Title", "Confirm", "Close"}, names)
+
+	title, err := inherited.GetNode("Panel/Title")
+	assert.NoError(t, err)
+	assert.Equal(t, "Label", title.Type)
+	assert.Equal(t, "Quit?", title.Fields["text"].(godot.Value).Value)
+	assert.Equal(t, int64(1), title.Fields["align"].(godot.Value).Value)
+	assert.True(t, inherited.IsInheritedNode(title))
+	assert.Equal(t, []string{"text"}, inherited.LocalFields(title))
+	assert.True(t, inherited.IsLocalField(title, "text"))
+	assert.False(t, inherited.IsLocalField(title, "align"))
+
+	confirm, err := inherited.GetNode("Panel/Confirm")
+	assert.NoError(t, err)
+	assert.True(t, inherited.IsLocalNode(confirm))
+	assert.Equal(t, []string{"text"}, inherited.LocalFields(confirm))
+
+	assert.True(t, inherited.IsInheritedNode(inherited.Node))
+	assert.Empty(t, inherited.LocalFields(inherited.Node))
+	assert.False(t, inherited.IsLocalField(inherited.Node, "modulate"))
+}
+
+func TestLoadInheritedSceneWithRegularScene(t *testing.T) {
+	scene, err := ParseScene(strings.NewReader(string(inheritedTestProject["UI/BaseDialog.tscn"].Data)))
+	assert.NoError(t, err)
+	assert.False(t, scene.IsInherited())
+
+	_, err = LoadInheritedScene(scene, NewFSResolver(inheritedTestProject))
+	assert.Error(t, err)
+}
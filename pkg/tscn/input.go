@@ -0,0 +1,11 @@
+package tscn
+
+import (
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// DecodeInputMap decodes the input actions of a project with their typed events
+func DecodeInputMap(project *godot.Project) (*godot.InputMap, error) {
+	return convert.ToGodotInputMap(project)
+}
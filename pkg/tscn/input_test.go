@@ -0,0 +1,116 @@
+package tscn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestDecodeInputMap(t *testing.T) {
+	content := `config_version=4
+
+[input]
+
+jump={
+"deadzone": 0.5,
+"events": [ Object(InputEventKey,"resource_local_to_scene":false,"resource_name":"","device":0,"alt":false,"shift":false,"control":true,"meta":false,"command":false,"pressed":false,"scancode":87,"physical_scancode":0,"unicode":0,"echo":false,"script":null)
+, Object(InputEventJoypadButton,"resource_local_to_scene":false,"resource_name":"","device":0,"button_index":0,"pressure":0.0,"pressed":false,"script":null)
+ ]
+}
+fire={
+"deadzone": 0.3,
+"events": [ Object(InputEventMouseButton,"resource_local_to_scene":false,"resource_name":"","device":0,"alt":false,"shift":false,"control":false,"meta":false,"command":false,"button_mask":0,"position":Vector2( 0, 0 ),"global_position":Vector2( 0, 0 ),"factor":1.0,"button_index":1,"pressed":false,"doubleclick":false,"script":null)
+, Object(InputEventJoypadMotion,"resource_local_to_scene":false,"resource_name":"","device":0,"axis":7,"axis_value":1.0,"script":null)
+ ]
+}
+`
+	project, err := ParseProject(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	inputMap, err := DecodeInputMap(project)
+	assert.NoError(t, err)
+	assert.Len(t, inputMap.Actions, 2)
+
+	jump := inputMap.Actions[0]
+	assert.Equal(t, "jump", jump.Name)
+	assert.Equal(t, 0.5, jump.Deadzone)
+	assert.Len(t, jump.Events, 2)
+
+	key := jump.Events[0].(*godot.InputEventKey)
+	assert.Equal(t, int64(87), key.Keycode)
+	assert.Equal(t, "W", key.Key)
+	assert.True(t, key.Ctrl)
+	assert.Equal(t, "Ctrl+W", key.Binding())
+	assert.Equal(t, "InputEventKey", key.Class)
+	assert.Equal(t, 7, key.LexerPosition.Line)
+
+	assert.Equal(t, "Joypad Button 0", jump.Events[1].Binding())
+
+	fire := inputMap.Action("fire")
+	assert.Equal(t, 0.3, fire.Deadzone)
+	assert.Equal(t, "Left Mouse Button", fire.Events[0].Binding())
+	assert.Equal(t, "Joypad Axis 7+", fire.Events[1].Binding())
+}
+
+func TestDecodeInputMapGodot4(t *testing.T) {
+	content := `config_version=5
+
+[input]
+
+ui_cancel={
+"deadzone": 0.5,
+"events": [Object(InputEventKey,"resource_local_to_scene":false,"resource_name":"","device":-1,"window_id":0,"alt_pressed":false,"shift_pressed":true,"ctrl_pressed":false,"meta_pressed":false,"pressed":false,"keycode":0,"physical_keycode":4194305,"key_label":0,"unicode":0,"echo":false,"script":null)
+]
+}
+`
+	project, err := ParseProject(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	inputMap, err := DecodeInputMap(project)
+	assert.NoError(t, err)
+
+	key := inputMap.Action("ui_cancel").Events[0].(*godot.InputEventKey)
+	assert.Equal(t, int64(-1), key.Device)
+	assert.Equal(t, int64(4194305), key.PhysicalKeycode)
+	assert.Equal(t, "Escape", key.Key)
+	assert.Equal(t, "Shift+Escape (Physical)", key.Binding())
+}
+
+func TestDecodeInputMapWithInvalidAction(t *testing.T) {
+	project, err := ParseProject(strings.NewReader("config_version=4\n\n[input]\n\njump=42\n"))
+	assert.NoError(t, err)
+
+	_, err = DecodeInputMap(project)
+	assert.Error(t, err)
+}
+
+// keep integration tests at the bottom please
+func TestIntegrationDecodeInputMapFixture(t *testing.T) {
+	f, err := os.Open(filepath.Join("..", "..", "test", "fixtures", "gdquest-godot-beginner-2d-platformer-game-project.godot"))
+	assert.NoError(t, err)
+	defer f.Close()
+
+	project, err := ParseProject(f)
+	assert.NoError(t, err)
+
+	inputMap, err := DecodeInputMap(project)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, action := range inputMap.Actions {
+		names = append(names, action.Name)
+	}
+	assert.Equal(t, []string{"move_left", "move_right", "jump", "pause"}, names)
+
+	var bindings []string
+	for _, event := range inputMap.Action("jump").Events {
+		bindings = append(bindings, event.Binding())
+	}
+	assert.Equal(t, []string{"W", "Up", "Space"}, bindings)
+	assert.Empty(t, inputMap.Conflicts())
+}
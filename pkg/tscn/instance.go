@@ -0,0 +1,252 @@
+package tscn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// volatileNodeType is the type of placeholder nodes for nodes of instanced scenes, see README.md
+const volatileNodeType = "VolatileNode"
+
+// InstantiateScene returns a copy of the node tree of the scene in which every instanced scene has been replaced by its
+// node tree. Instanced scenes are loaded recursively through the resolver and the overrides of the instancing scene
+// are applied to them. The scene itself is not modified.
+//
+// The ExtResource and SubResource references of the fields belong to the scene which set the field, use Instantiate
+// to resolve them.
+func InstantiateScene(scene *godot.Scene, resolver Resolver) (*godot.Node, error) {
+	instantiated, err := Instantiate(scene, resolver)
+	if err != nil {
+		return nil, err
+	}
+	return instantiated.Node, nil
+}
+
+// InstantiatedScene is the node tree of a scene in which every instanced scene has been replaced by its node tree
+type InstantiatedScene struct {
+	// Scene is the instantiated scene as it was parsed
+	Scene *godot.Scene
+	// Node is the root node of the instantiated node tree
+	Node *godot.Node
+	// fieldScenes maps the fields of the nodes of the tree to the scene which set them
+	fieldScenes map[*godot.Node]map[string]*godot.Scene
+}
+
+// Instantiate works like InstantiateScene but remembers which scene set the fields of the nodes, so that their
+// resource references can be resolved
+func Instantiate(scene *godot.Scene, resolver Resolver) (*InstantiatedScene, error) {
+	i := newInstantiator(resolver)
+	node, err := i.instantiate(scene, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &InstantiatedScene{Scene: scene, Node: node, fieldScenes: i.fieldScenes}, nil
+}
+
+// FieldScene returns the scene which set the field of a node of the instantiated tree, e.g. the instanced scene for
+// fields which aren't overridden. The ids of ExtResource and SubResource references within the field belong to it.
+func (s *InstantiatedScene) FieldScene(node *godot.Node, key string) (*godot.Scene, bool) {
+	scene, ok := s.fieldScenes[node][key]
+	return scene, ok
+}
+
+// ExtResource returns the external resource a field of a node of the instantiated tree references
+func (s *InstantiatedScene) ExtResource(node *godot.Node, key string) (*godot.ExtResource, bool) {
+	scene, ref, ok := s.fieldReference(node, key)
+	if !ok || !ref.External {
+		return nil, false
+	}
+	ext, ok := scene.ExtResources[ref.ID]
+	return ext, ok
+}
+
+// SubResource returns the internal resource a field of a node of the instantiated tree references
+func (s *InstantiatedScene) SubResource(node *godot.Node, key string) (*godot.SubResource, bool) {
+	scene, ref, ok := s.fieldReference(node, key)
+	if !ok || ref.External {
+		return nil, false
+	}
+	sub, ok := scene.SubResources[ref.ID]
+	return sub, ok
+}
+
+func (s *InstantiatedScene) fieldReference(node *godot.Node, key string) (*godot.Scene, godot.Reference, bool) {
+	scene, ok := s.FieldScene(node, key)
+	if !ok {
+		return nil, godot.Reference{}, false
+	}
+	ref, ok := convert.ReferenceOf(node.Fields[key])
+	return scene, ref, ok
+}
+
+type instantiator struct {
+	resolver Resolver
+	// scenes caches all scenes loaded so far by their res:// path
+	scenes map[string]*godot.Scene
+	// fieldScenes maps the fields of the instantiated nodes to the scene which set them
+	fieldScenes map[*godot.Node]map[string]*godot.Scene
+}
+
+func newInstantiator(resolver Resolver) *instantiator {
+	return &instantiator{
+		resolver:    resolver,
+		scenes:      make(map[string]*godot.Scene),
+		fieldScenes: make(map[*godot.Node]map[string]*godot.Scene),
+	}
+}
+
+func (i *instantiator) instantiate(scene *godot.Scene, stack []string) (*godot.Node, error) {
+	if scene.Node == nil {
+		return nil, fmt.Errorf("scene has no root node")
+	}
+	return i.instantiateNode(scene, scene.Node, stack)
+}
+
+func (i *instantiator) instantiateNode(scene *godot.Scene, node *godot.Node, stack []string) (*godot.Node, error) {
+	var result *godot.Node
+
+	if node.Instance.Identifier != "" {
+		base, err := i.instantiateInstance(scene, node, stack)
+		if err != nil {
+			return nil, err
+		}
+		result = base
+		i.overrideNode(scene, result, node)
+	} else {
+		result = &godot.Node{
+			Name:           node.Name,
+			Type:           node.Type,
+			InheritedIndex: node.InheritedIndex,
+		}
+		i.overrideNode(scene, result, node)
+	}
+
+	for _, child := range node.Children() {
+		if err := i.addInstantiatedChild(scene, result, child, stack); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// addInstantiatedChild adds a child of the instancing scene, children which exist in the instanced scene already
+// (e.g. editable children) are merged into the existing node
+func (i *instantiator) addInstantiatedChild(scene *godot.Scene, parent, child *godot.Node, stack []string) error {
+	existing, exists := parent.Child(child.Name)
+	if exists && child.Instance.Identifier == "" {
+		i.overrideNode(scene, existing, child)
+		for _, grandChild := range child.Children() {
+			if err := i.addInstantiatedChild(scene, existing, grandChild, stack); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if child.Type == volatileNodeType {
+		return fmt.Errorf("could not find node %s in instanced scene of %s", child.Name, parent.Name)
+	}
+
+	instantiated, err := i.instantiateNode(scene, child, stack)
+	if err != nil {
+		return err
+	}
+
+	parent.AddNode(instantiated)
+	if child.InheritedIndex != nil && *child.InheritedIndex < parent.ChildCount() {
+		return parent.MoveChild(instantiated, *child.InheritedIndex)
+	}
+	return nil
+}
+
+// overrideNode applies the name, type, groups and fields of the instancing node to a node of the instanced scene
+func (i *instantiator) overrideNode(scene *godot.Scene, target, override *godot.Node) {
+	target.Name = override.Name
+	if override.Type != "" && override.Type != volatileNodeType {
+		target.Type = override.Type
+	}
+	if override.Instance.Identifier != "" {
+		target.Instance = override.Instance
+	}
+	for _, group := range override.Groups {
+		if !containsString(target.Groups, group) {
+			target.Groups = append(target.Groups, group)
+		}
+	}
+	if target.Fields == nil {
+		target.Fields = make(map[string]interface{})
+	}
+	if i.fieldScenes[target] == nil {
+		i.fieldScenes[target] = make(map[string]*godot.Scene)
+	}
+	for key, value := range override.Fields {
+		target.Fields[key] = value
+		i.fieldScenes[target][key] = scene
+	}
+	target.MetaData = override.MetaData
+}
+
+// instantiateInstance loads the scene instanced by the node and returns its instantiated root node
+func (i *instantiator) instantiateInstance(scene *godot.Scene, node *godot.Node, stack []string) (*godot.Node, error) {
+	ref, ok := convert.ReferenceOf(node.Instance)
+	if !ok || !ref.External {
+		return nil, fmt.Errorf("node %s has an invalid instance %s", node.Name, node.LexerPosition)
+	}
+
+	ext, ok := scene.ExtResources[ref.ID]
+	if !ok {
+		return nil, fmt.Errorf("node %s instances unknown external resource %s %s", node.Name, ref.ID, node.LexerPosition)
+	}
+
+	for _, p := range stack {
+		if p == ext.Path {
+			return nil, fmt.Errorf("instance cycle detected: %s", strings.Join(append(stack, ext.Path), " -> "))
+		}
+	}
+
+	instanced, err := i.loadScene(ext.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := i.instantiate(instanced, append(stack, ext.Path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not instantiate %s", ext.Path)
+	}
+	return root, nil
+}
+
+func (i *instantiator) loadScene(resPath string) (*godot.Scene, error) {
+	if scene, ok := i.scenes[resPath]; ok {
+		return scene, nil
+	}
+
+	f, err := i.resolver.Open(resPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open %s", resPath)
+	}
+	defer f.Close()
+
+	scene, err := ParseScene(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", resPath)
+	}
+
+	i.scenes[resPath] = scene
+	return scene, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
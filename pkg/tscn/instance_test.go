@@ -0,0 +1,184 @@
+package tscn
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+var instanceTestProject = fstest.MapFS{
+	"Enemies/Bat.tscn": {Data: []byte(`[gd_scene format=2]
+
+[node name="Bat" type="KinematicBody2D"]
+speed = 50
+
+[node name="Sprite" type="Sprite" parent="."]
+hframes = 5
+`)},
+	"World/Room.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Enemies/Bat.tscn" type="PackedScene" id=1]
+
+[node name="Room" type="Node2D"]
+
+[node name="Floor" type="TileMap" parent="."]
+
+[node name="Bat" parent="." instance=ExtResource( 1 )]
+speed = 80
+`)},
+	"World.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://World/Room.tscn" type="PackedScene" id=1]
+
+[node name="World" type="Node2D"]
+
+[node name="Room1" parent="." instance=ExtResource( 1 )]
+position = Vector2( 100, 0 )
+
+[node name="Sprite" parent="Room1/Bat" index="0"]
+hframes = 8
+
+[node name="Torch" type="Light2D" parent="Room1"]
+
+[editable path="Room1"]
+[editable path="Room1/Bat"]
+`)},
+	"Cycle/A.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Cycle/B.tscn" type="PackedScene" id=1]
+
+[node name="A" type="Node"]
+
+[node name="B" parent="." instance=ExtResource( 1 )]
+`)},
+	"Cycle/B.tscn": {Data: []byte(`[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Cycle/A.tscn" type="PackedScene" id=1]
+
+[node name="B" type="Node"]
+
+[node name="A" parent="." instance=ExtResource( 1 )]
+`)},
+}
+
+func loadSceneForTest(t *testing.T, name string) *godot.Scene {
+	scene, err := ParseScene(strings.NewReader(string(instanceTestProject[name].Data)))
+	assert.NoError(t, err)
+	return scene
+}
+
+func TestInstantiateScene(t *testing.T) {
+	scene := loadSceneForTest(t, "World.tscn")
+
+	root, err := InstantiateScene(scene, NewFSResolver(instanceTestProject))
+	assert.NoError(t, err)
+
+	room, err := root.GetNode("Room1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Node2D", room.Type)
+	assert.Equal(t, godot.Vector2{X: 100}, room.Fields["position"].(godot.Value).Value)
+
+	var names []string
+	for _, child := range room.Children() {
+		names = append(names, child.Name)
+	}
+	assert.Equal(t, []string{"Floor", "Bat", "Torch"}, names)
+
+	bat, err := root.GetNode("Room1/Bat")
+	assert.NoError(t, err)
+	assert.Equal(t, "KinematicBody2D", bat.Type)
+	assert.Equal(t, int64(80), bat.Fields["speed"].(godot.Value).Value)
+
+	sprite, err := root.GetNode("Room1/Bat/Sprite")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sprite", sprite.Type)
+	assert.Equal(t, int64(8), sprite.Fields["hframes"].(godot.Value).Value)
+
+	// the parsed scene stays untouched
+	_, err = scene.GetNode("Room1/Floor")
+	assert.Error(t, err)
+}
+
+func TestInstantiateResolvesReferencesInTheirScene(t *testing.T) {
+	// Godot 3 uses the same integer ids in every file
+	resolver := NewFSResolver(fstest.MapFS{
+		"Bat.tscn": {Data: []byte(`[gd_scene load_steps=3 format=2]
+
+[ext_resource path="res://bat.png" type="Texture" id=1]
+
+[sub_resource type="CircleShape2D" id=1]
+
+[node name="Bat" type="KinematicBody2D"]
+
+[node name="Sprite" type="Sprite" parent="."]
+texture = ExtResource( 1 )
+
+[node name="Shape" type="CollisionShape2D" parent="."]
+shape = SubResource( 1 )
+`)},
+	})
+	scene, err := ParseScene(strings.NewReader(`[gd_scene load_steps=3 format=2]
+
+[ext_resource path="res://Bat.tscn" type="PackedScene" id=1]
+[ext_resource path="res://boss.png" type="Texture" id=2]
+
+[node name="World" type="Node2D"]
+
+[node name="Bat" parent="." instance=ExtResource( 1 )]
+
+[node name="Sprite" parent="Bat" index="0"]
+normal_map = ExtResource( 2 )
+
+[editable path="Bat"]
+`))
+	assert.NoError(t, err)
+
+	instantiated, err := Instantiate(scene, resolver)
+	assert.NoError(t, err)
+
+	sprite, err := instantiated.Node.GetNode("Bat/Sprite")
+	assert.NoError(t, err)
+
+	texture, ok := instantiated.ExtResource(sprite, "texture")
+	assert.True(t, ok)
+	assert.Equal(t, "res://bat.png", texture.Path)
+
+	normalMap, ok := instantiated.ExtResource(sprite, "normal_map")
+	assert.True(t, ok)
+	assert.Equal(t, "res://boss.png", normalMap.Path)
+
+	fieldScene, ok := instantiated.FieldScene(sprite, "normal_map")
+	assert.True(t, ok)
+	assert.Same(t, scene, fieldScene)
+
+	shape, err := instantiated.Node.GetNode("Bat/Shape")
+	assert.NoError(t, err)
+	sub, ok := instantiated.SubResource(shape, "shape")
+	assert.True(t, ok)
+	assert.Equal(t, "CircleShape2D", sub.Type)
+
+	_, ok = instantiated.ExtResource(shape, "shape")
+	assert.False(t, ok)
+	_, ok = instantiated.FieldScene(shape, "missing")
+	assert.False(t, ok)
+}
+
+func TestInstantiateSceneDetectsCycles(t *testing.T) {
+	scene := loadSceneForTest(t, "Cycle/A.tscn")
+
+	_, err := InstantiateScene(scene, NewFSResolver(instanceTestProject))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "instance cycle detected: res://Cycle/B.tscn -> res://Cycle/A.tscn -> res://Cycle/B.tscn")
+}
+
+func TestInstantiateSceneWithMissingFile(t *testing.T) {
+	scene := loadSceneForTest(t, "World.tscn")
+
+	_, err := InstantiateScene(scene, NewFSResolver(fstest.MapFS{}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "res://World/Room.tscn")
+}
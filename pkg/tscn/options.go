@@ -0,0 +1,48 @@
+package tscn
+
+import (
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+)
+
+// SyntaxRuleID is the rule ID of diagnostics created for files which couldn't be parsed
+const SyntaxRuleID = "syntax"
+
+// Option configures how a file is parsed, e.g. tscn.ParseScene(r, tscn.WithFilename("Player.tscn"))
+type Option func(o *options)
+
+type options struct {
+	filename    string
+	diagnostics *diagnostic.List
+	registry    *Registry
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{registry: defaultRegistry}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithFilename sets the filename which is part of the positions in errors and diagnostics
+func WithFilename(filename string) Option {
+	return func(o *options) {
+		o.filename = filename
+	}
+}
+
+// WithDiagnostics collects every problem found while validating and converting the file into diagnostics instead of
+// failing on the first one. The file is converted as far as possible and returned without error, even if the
+// diagnostics contain errors. Files with syntax errors can't be converted and still return an error.
+func WithDiagnostics(diagnostics *diagnostic.List) Option {
+	return func(o *options) {
+		o.diagnostics = diagnostics
+	}
+}
+
+// WithRules replaces the rules used to validate the file, use DefaultRegistry to extend the pre-defined rules
+func WithRules(registry *Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
@@ -0,0 +1,79 @@
+package tscn
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const (
+	// ResourcePathPrefix is the prefix of paths relative to the project directory, e.g. res://Player/Player.tscn
+	ResourcePathPrefix = "res://"
+	// UIDPathPrefix is the prefix of the unique identifiers Godot 4 assigns to resources, e.g. uid://cecaux1sm7mo0
+	UIDPathPrefix = "uid://"
+)
+
+// Resolver opens resources referenced by their res:// path
+type Resolver interface {
+	Open(resPath string) (io.ReadCloser, error)
+}
+
+// FSResolver resolves res:// paths within a file system rooted at the project directory, e.g. os.DirFS("./game")
+type FSResolver struct {
+	FS fs.FS
+}
+
+// NewFSResolver creates a resolver for the project directory fsys
+func NewFSResolver(fsys fs.FS) *FSResolver {
+	return &FSResolver{FS: fsys}
+}
+
+// Open opens the file of a res:// path
+func (r *FSResolver) Open(resPath string) (io.ReadCloser, error) {
+	name, err := ResourcePathToFSPath(resPath)
+	if err != nil {
+		return nil, err
+	}
+	return r.FS.Open(name)
+}
+
+// UIDResolver resolves uid:// paths to res:// paths and opens them with another resolver, res:// paths are passed to
+// the other resolver as they are
+type UIDResolver struct {
+	Resolver Resolver
+	// UIDs maps uid:// paths to res:// paths
+	UIDs map[string]string
+}
+
+// NewUIDResolver creates a resolver for uid:// paths which opens the files with the resolver
+func NewUIDResolver(resolver Resolver, uids map[string]string) *UIDResolver {
+	return &UIDResolver{Resolver: resolver, UIDs: uids}
+}
+
+// Open opens the file of a uid:// or res:// path
+func (r *UIDResolver) Open(resPath string) (io.ReadCloser, error) {
+	if strings.HasPrefix(resPath, UIDPathPrefix) {
+		target, ok := r.UIDs[resPath]
+		if !ok {
+			return nil, fmt.Errorf("unknown uid: %s", resPath)
+		}
+		resPath = target
+	}
+	return r.Resolver.Open(resPath)
+}
+
+// ResourcePathToFSPath converts a res:// path into a path usable with io/fs, e.g. res://Player/Player.tscn becomes
+// Player/Player.tscn
+func ResourcePathToFSPath(resPath string) (string, error) {
+	if !strings.HasPrefix(resPath, ResourcePathPrefix) {
+		return "", fmt.Errorf("not a resource path: %s", resPath)
+	}
+
+	name := path.Clean(strings.TrimPrefix(resPath, ResourcePathPrefix))
+	if !fs.ValidPath(name) {
+		return "", fmt.Errorf("resource path points outside of the project: %s", resPath)
+	}
+	return name, nil
+}
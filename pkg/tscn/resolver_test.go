@@ -0,0 +1,60 @@
+package tscn
+
+import (
+	"io/ioutil"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourcePathToFSPath(t *testing.T) {
+	name, err := ResourcePathToFSPath("res://World/Room.tscn")
+	assert.NoError(t, err)
+	assert.Equal(t, "World/Room.tscn", name)
+
+	name, err = ResourcePathToFSPath("res://World/../Player.tscn")
+	assert.NoError(t, err)
+	assert.Equal(t, "Player.tscn", name)
+
+	_, err = ResourcePathToFSPath("World/Room.tscn")
+	assert.Error(t, err)
+
+	_, err = ResourcePathToFSPath("res://../outside.tscn")
+	assert.Error(t, err)
+}
+
+func TestFSResolver(t *testing.T) {
+	resolver := NewFSResolver(fstest.MapFS{
+		"World/Room.tscn": {Data: []byte("[gd_scene format=2]")},
+	})
+
+	f, err := resolver.Open("res://World/Room.tscn")
+	assert.NoError(t, err)
+	content, err := ioutil.ReadAll(f)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	assert.Equal(t, "[gd_scene format=2]", string(content))
+
+	_, err = resolver.Open("res://World/Missing.tscn")
+	assert.Error(t, err)
+}
+
+func TestUIDResolver(t *testing.T) {
+	resolver := NewUIDResolver(
+		NewFSResolver(fstest.MapFS{"World/Room.tscn": {Data: []byte("[gd_scene format=3]")}}),
+		map[string]string{"uid://b8mxpo2hqjc1v": "res://World/Room.tscn"},
+	)
+
+	for _, path := range []string{"uid://b8mxpo2hqjc1v", "res://World/Room.tscn"} {
+		f, err := resolver.Open(path)
+		assert.NoError(t, err)
+		content, err := ioutil.ReadAll(f)
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+		assert.Equal(t, "[gd_scene format=3]", string(content))
+	}
+
+	_, err := resolver.Open("uid://unknown")
+	assert.EqualError(t, err, "unknown uid: uid://unknown")
+}
@@ -0,0 +1,243 @@
+package tscn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/internal/validate"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// Rule checks a file for problems, e.g. naming conventions or forbidden node types
+type Rule interface {
+	// ID identifies the rule in diagnostics and the Registry, e.g. "single-root-node"
+	ID() string
+	// Check returns every problem found, the rule ID of the diagnostics is set by the Registry
+	Check(ctx *RuleContext) diagnostic.List
+}
+
+// RuleContext gives a rule access to the file it checks
+type RuleContext struct {
+	// Options contains the configuration of the rule set with Registry.Configure, nil if there is none
+	Options map[string]interface{}
+	file    *ruleFile
+}
+
+// ruleFile is shared between the contexts of all rules checking the same file, the conversions are done on demand
+type ruleFile struct {
+	filename string
+	source   string
+	tscn     *parser.TscnFile
+
+	document    *Document
+	documentErr error
+	scene       *godot.Scene
+	resource    *godot.Resource
+
+	documentParsed, sceneConverted, resourceConverted bool
+}
+
+type ruleFunc struct {
+	id    string
+	check func(ctx *RuleContext) diagnostic.List
+}
+
+// NewRule creates a rule from a function
+func NewRule(id string, check func(ctx *RuleContext) diagnostic.List) Rule {
+	return &ruleFunc{id: id, check: check}
+}
+
+func (r *ruleFunc) ID() string {
+	return r.id
+}
+
+func (r *ruleFunc) Check(ctx *RuleContext) diagnostic.List {
+	return r.check(ctx)
+}
+
+// validatorRule makes the pre-defined validators available as rules
+type validatorRule struct {
+	validator validate.Validator
+}
+
+func (r *validatorRule) ID() string {
+	return r.validator.ID
+}
+
+func (r *validatorRule) Check(ctx *RuleContext) diagnostic.List {
+	return r.validator.Function(ctx.file.tscn)
+}
+
+// Filename returns the filename passed with WithFilename
+func (c *RuleContext) Filename() string {
+	return c.file.filename
+}
+
+// Type returns the type of the file, e.g. "gd_scene" or "gd_resource", empty for project.godot and other config files
+func (c *RuleContext) Type() string {
+	return c.file.tscn.Key
+}
+
+// Document returns the file as it was written, including sections which can't be converted
+func (c *RuleContext) Document() (*Document, error) {
+	f := c.file
+	if !f.documentParsed {
+		f.documentParsed = true
+		doc, err := parser.ParseDocumentFile(f.filename, strings.NewReader(f.source))
+		if err != nil {
+			f.documentErr = err
+		} else {
+			f.document = &Document{doc: doc}
+		}
+	}
+	return f.document, f.documentErr
+}
+
+// Scene returns the converted scene, broken parts of the file are left out like WithDiagnostics does. Returns nil if
+// the file isn't a scene.
+func (c *RuleContext) Scene() *godot.Scene {
+	f := c.file
+	if !f.sceneConverted && c.Type() == convert.TscnTypeGodotScene {
+		f.sceneConverted = true
+		var ignored diagnostic.List
+		f.scene, _ = convert.ToGodotScenePartially(f.tscn, &ignored)
+	}
+	return f.scene
+}
+
+// Resource returns the converted resource, broken parts of the file are left out like WithDiagnostics does. Returns
+// nil if the file isn't a resource.
+func (c *RuleContext) Resource() *godot.Resource {
+	f := c.file
+	if !f.resourceConverted && c.Type() == convert.TscnTypeGodotResource {
+		f.resourceConverted = true
+		var ignored diagnostic.List
+		f.resource, _ = convert.ToGodotResourcePartially(f.tscn, &ignored)
+	}
+	return f.resource
+}
+
+// defaultRegistry is used if no other rules are set, it's never modified
+var defaultRegistry = DefaultRegistry()
+
+// Registry is a set of rules which can be enabled, disabled and configured by their ID
+type Registry struct {
+	rules      []Rule
+	disabled   map[string]bool
+	options    map[string]map[string]interface{}
+	severities map[string]diagnostic.Severity
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		disabled:   make(map[string]bool),
+		options:    make(map[string]map[string]interface{}),
+		severities: make(map[string]diagnostic.Severity),
+	}
+}
+
+// DefaultRegistry creates a registry containing the pre-defined rules which are used if no other rules are set
+func DefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for _, validator := range validate.Validators() {
+		registry.rules = append(registry.rules, &validatorRule{validator: validator})
+	}
+	return registry
+}
+
+// Register adds an enabled rule, the ID must be unique
+func (r *Registry) Register(rule Rule) error {
+	if _, ok := r.Rule(rule.ID()); ok {
+		return fmt.Errorf("a rule with the ID %s is already registered", rule.ID())
+	}
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+// Rule returns the rule with the given ID
+func (r *Registry) Rule(id string) (Rule, bool) {
+	for _, rule := range r.rules {
+		if rule.ID() == id {
+			return rule, true
+		}
+	}
+	return nil, false
+}
+
+// Rules returns all registered rules in the order they run, including disabled ones
+func (r *Registry) Rules() []Rule {
+	return append([]Rule(nil), r.rules...)
+}
+
+// Enable enables a previously disabled rule
+func (r *Registry) Enable(id string) error {
+	if err := r.ensureRuleExists(id); err != nil {
+		return err
+	}
+	delete(r.disabled, id)
+	return nil
+}
+
+// Disable prevents a rule from running
+func (r *Registry) Disable(id string) error {
+	if err := r.ensureRuleExists(id); err != nil {
+		return err
+	}
+	r.disabled[id] = true
+	return nil
+}
+
+// IsEnabled checks if the rule exists and is enabled
+func (r *Registry) IsEnabled(id string) bool {
+	_, ok := r.Rule(id)
+	return ok && !r.disabled[id]
+}
+
+// Configure sets the options of a rule, they're passed to the rule as RuleContext.Options
+func (r *Registry) Configure(id string, options map[string]interface{}) error {
+	if err := r.ensureRuleExists(id); err != nil {
+		return err
+	}
+	r.options[id] = options
+	return nil
+}
+
+// SetSeverity overrides the severity of all diagnostics reported by a rule, e.g. to turn errors into warnings
+func (r *Registry) SetSeverity(id string, severity diagnostic.Severity) error {
+	if err := r.ensureRuleExists(id); err != nil {
+		return err
+	}
+	r.severities[id] = severity
+	return nil
+}
+
+func (r *Registry) ensureRuleExists(id string) error {
+	if _, ok := r.Rule(id); !ok {
+		return fmt.Errorf("unknown rule %s", id)
+	}
+	return nil
+}
+
+// check runs all enabled rules against the file
+func (r *Registry) check(file *ruleFile) diagnostic.List {
+	var diagnostics diagnostic.List
+	for _, rule := range r.rules {
+		if r.disabled[rule.ID()] {
+			continue
+		}
+
+		ctx := &RuleContext{Options: r.options[rule.ID()], file: file}
+		for _, d := range rule.Check(ctx) {
+			d.RuleID = rule.ID()
+			if severity, ok := r.severities[rule.ID()]; ok {
+				d.Severity = severity
+			}
+			diagnostics.Add(d)
+		}
+	}
+	return diagnostics
+}
@@ -0,0 +1,188 @@
+package tscn
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+const ruleTestScene = `[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Player.gd" type="Script" id=1]
+
+[node name="Player" type="KinematicBody2D"]
+script = ExtResource( 1 )
+
+[node name="sprite" type="Sprite" parent="."]
+
+[node name="Timer" type="Timer" parent="."]
+
+[node name="Debug" type="Node2D"]
+`
+
+// forbiddenNodeTypesRule reports nodes whose type is listed in the option "types"
+var forbiddenNodeTypesRule = NewRule("forbidden-node-types", func(ctx *RuleContext) diagnostic.List {
+	scene := ctx.Scene()
+	if scene == nil || scene.Node == nil {
+		return nil
+	}
+
+	forbidden, _ := ctx.Options["types"].([]string)
+
+	var diagnostics diagnostic.List
+	var visit func(node *godot.Node)
+	visit = func(node *godot.Node) {
+		for _, t := range forbidden {
+			if node.Type == t {
+				diagnostics.Add(diagnostic.New(node.LexerPosition, "node %s must not be of type %s", node.Name, t))
+			}
+		}
+		for _, child := range node.Children() {
+			visit(child)
+		}
+	}
+	visit(scene.Node)
+	return diagnostics
+})
+
+// pascalCaseNodeNamesRule works on the document, so that it sees every node section even if the tree is broken
+var pascalCaseNodeNamesRule = NewRule("pascal-case-node-names", func(ctx *RuleContext) diagnostic.List {
+	doc, err := ctx.Document()
+	if err != nil {
+		return diagnostic.List{diagnostic.New(lexer.Position{Filename: ctx.Filename()}, "%s", err)}
+	}
+
+	pascalCase := regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+
+	var diagnostics diagnostic.List
+	for _, section := range doc.SectionsOfType("node") {
+		name, err := section.Attribute("name")
+		if err != nil {
+			continue
+		}
+		if s, ok := name.(godot.Value).Value.(string); ok && !pascalCase.MatchString(s) {
+			pos, _ := section.AttributePosition("name")
+			d := diagnostic.New(pos, "node name %s is not in PascalCase", s)
+			d.Severity = diagnostic.SeverityWarning
+			diagnostics.Add(d)
+		}
+	}
+	return diagnostics
+})
+
+func newRuleTestRegistry(t *testing.T) *Registry {
+	registry := DefaultRegistry()
+	assert.NoError(t, registry.Register(forbiddenNodeTypesRule))
+	assert.NoError(t, registry.Register(pascalCaseNodeNamesRule))
+	assert.NoError(t, registry.Configure("forbidden-node-types", map[string]interface{}{"types": []string{"Timer"}}))
+	return registry
+}
+
+func TestRegistry(t *testing.T) {
+	registry := newRuleTestRegistry(t)
+
+	assert.Error(t, registry.Register(NewRule("single-root-node", nil)))
+	assert.Len(t, registry.Rules(), 7)
+	assert.True(t, registry.IsEnabled("single-root-node"))
+
+	assert.NoError(t, registry.Disable("single-root-node"))
+	assert.False(t, registry.IsEnabled("single-root-node"))
+	assert.NoError(t, registry.Enable("single-root-node"))
+	assert.True(t, registry.IsEnabled("single-root-node"))
+
+	assert.Error(t, registry.Disable("does-not-exist"))
+	assert.Error(t, registry.Enable("does-not-exist"))
+	assert.Error(t, registry.Configure("does-not-exist", nil))
+	assert.Error(t, registry.SetSeverity("does-not-exist", diagnostic.SeverityWarning))
+	assert.False(t, registry.IsEnabled("does-not-exist"))
+
+	rule, ok := registry.Rule("forbidden-node-types")
+	assert.True(t, ok)
+	assert.Equal(t, "forbidden-node-types", rule.ID())
+}
+
+func TestParseSceneWithRules(t *testing.T) {
+	registry := newRuleTestRegistry(t)
+
+	var diagnostics diagnostic.List
+	scene, err := ParseScene(
+		strings.NewReader(ruleTestScene),
+		WithFilename("Player.tscn"),
+		WithRules(registry),
+		WithDiagnostics(&diagnostics),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "Player", scene.Name)
+
+	assert.Len(t, diagnostics, 3)
+	assert.Equal(t, "single-root-node", diagnostics[0].RuleID)
+	assert.Equal(t, "forbidden-node-types", diagnostics[1].RuleID)
+	assert.Equal(t, "node Timer must not be of type Timer", diagnostics[1].Message)
+	assert.Equal(t, 10, diagnostics[1].Position.Line)
+	assert.Equal(t, "pascal-case-node-names", diagnostics[2].RuleID)
+	assert.Equal(t, diagnostic.SeverityWarning, diagnostics[2].Severity)
+	assert.Equal(t, "Player.tscn", diagnostics[2].Position.Filename)
+	assert.Equal(t, 8, diagnostics[2].Position.Line)
+}
+
+func TestParseSceneWithDisabledRules(t *testing.T) {
+	registry := newRuleTestRegistry(t)
+
+	_, err := ParseScene(strings.NewReader(ruleTestScene), WithRules(registry))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "[single-root-node]")
+
+	assert.NoError(t, registry.Disable("single-root-node"))
+	_, err = ParseScene(strings.NewReader(ruleTestScene), WithRules(registry))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "[forbidden-node-types]")
+
+	// warnings don't prevent parsing
+	assert.NoError(t, registry.SetSeverity("forbidden-node-types", diagnostic.SeverityWarning))
+	scene, err := ParseScene(strings.NewReader(ruleTestScene), WithRules(registry))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, scene.ChildCount())
+}
+
+func TestParseSceneWithEmptyRegistry(t *testing.T) {
+	content := `[gd_scene format=1]
+[node name="Root" type="Node2D"]`
+	_, err := ParseScene(strings.NewReader(content))
+	assert.Error(t, err)
+
+	scene, err := ParseScene(strings.NewReader(content), WithRules(NewRegistry()))
+	assert.NoError(t, err)
+	assert.Equal(t, "Root", scene.Name)
+}
+
+func TestLint(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=2]
+[ext_resource path="res://Broken.gd" type="Script"]
+[node name="Root" type="Node2D"]
+[node name="Orphan" type="Node2D" parent="Missing"]
+[node name="SecondRoot" type="Node2D"]`
+
+	diagnostics, err := Lint(strings.NewReader(content), WithFilename("Level.tscn"))
+	assert.NoError(t, err)
+
+	var lines []int
+	for _, d := range diagnostics {
+		assert.Equal(t, "Level.tscn", d.Position.Filename)
+		lines = append(lines, d.Position.Line)
+	}
+	assert.Equal(t, []int{2, 2, 4, 5}, lines)
+	assert.Equal(t, "ext-resource-required-attributes", diagnostics[0].RuleID)
+}
+
+func TestLintWithSyntaxError(t *testing.T) {
+	diagnostics, err := Lint(strings.NewReader(`[gd_scene`))
+	assert.NoError(t, err)
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, SyntaxRuleID, diagnostics[0].RuleID)
+}
@@ -2,28 +2,34 @@
 package tscn
 
 import (
+	"bytes"
 	"io"
+	"io/ioutil"
 
 	"github.com/pkg/errors"
 
 	"github.com/atomicptr/godot-tscn-parser/internal/convert"
 	"github.com/atomicptr/godot-tscn-parser/internal/parser"
-	"github.com/atomicptr/godot-tscn-parser/internal/validate"
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
 )
 
 // ParseScene parses a TSCN file of the type gd_scene
-func ParseScene(r io.Reader) (*godot.Scene, error) {
-	tscn, err := parseAndValidateTscnFile(r)
+func ParseScene(r io.Reader, opts ...Option) (*godot.Scene, error) {
+	o := newOptions(opts)
+	tscn, err := parseAndValidateTscnFile(r, o)
 	if err != nil {
 		return nil, err
 	}
+	if o.diagnostics != nil {
+		return convert.ToGodotScenePartially(tscn, o.diagnostics)
+	}
 	return convert.ToGodotScene(tscn)
 }
 
 // ParseProject parses the central project.godot project configuration file
-func ParseProject(r io.Reader) (*godot.Project, error) {
-	tscn, err := parseAndValidateTscnFile(r)
+func ParseProject(r io.Reader, opts ...Option) (*godot.Project, error) {
+	tscn, err := parseAndValidateTscnFile(r, newOptions(opts))
 	if err != nil {
 		return nil, err
 	}
@@ -31,21 +37,105 @@ func ParseProject(r io.Reader) (*godot.Project, error) {
 }
 
 // ParseResource parses .tres files
-func ParseResource(r io.Reader) (*godot.Resource, error) {
-	tscn, err := parseAndValidateTscnFile(r)
+func ParseResource(r io.Reader, opts ...Option) (*godot.Resource, error) {
+	o := newOptions(opts)
+	tscn, err := parseAndValidateTscnFile(r, o)
 	if err != nil {
 		return nil, err
 	}
+	if o.diagnostics != nil {
+		return convert.ToGodotResourcePartially(tscn, o.diagnostics)
+	}
 	return convert.ToGodotResource(tscn)
 }
 
-func parseAndValidateTscnFile(r io.Reader) (*parser.TscnFile, error) {
-	tscn, err := parser.Parse(r)
+// ParseImport parses *.import files, which contain the import settings of an asset like a texture
+func ParseImport(r io.Reader, opts ...Option) (*godot.Import, error) {
+	tscn, err := parseAndValidateTscnFile(r, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return convert.ToGodotImport(tscn)
+}
+
+// ParseConfig parses files written by Godots ConfigFile class like export_presets.cfg, plugin.cfg, override.cfg or
+// .gdextension files into a generic model of sections and keys
+func ParseConfig(r io.Reader, opts ...Option) (*godot.ConfigFile, error) {
+	tscn, err := parseAndValidateTscnFile(r, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return convert.ToGodotConfig(tscn)
+}
+
+// ParseExportPresets parses the export presets of a project (export_presets.cfg)
+func ParseExportPresets(r io.Reader, opts ...Option) (*godot.ExportPresets, error) {
+	tscn, err := parseAndValidateTscnFile(r, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return convert.ToGodotExportPresets(tscn)
+}
+
+// Lint checks a scene, resource or config file with the rules (see WithRules) and by converting it, it returns every
+// problem found sorted by position. Files with syntax errors result in a single diagnostic.
+func Lint(r io.Reader, opts ...Option) (diagnostic.List, error) {
+	var diagnostics diagnostic.List
+	o := newOptions(append(opts, WithDiagnostics(&diagnostics)))
+
+	tscn, err := parseAndValidateTscnFile(r, o)
+	if err != nil {
+		if len(diagnostics) > 0 {
+			return diagnostics, nil
+		}
+		return nil, err
+	}
+
+	switch tscn.Key {
+	case convert.TscnTypeGodotScene:
+		_, err = convert.ToGodotScenePartially(tscn, &diagnostics)
+	case convert.TscnTypeGodotResource:
+		_, err = convert.ToGodotResourcePartially(tscn, &diagnostics)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics.Sort()
+	return diagnostics, nil
+}
+
+func parseAndValidateTscnFile(r io.Reader, o *options) (*parser.TscnFile, error) {
+	content, err := ioutil.ReadAll(r)
 	if err != nil {
+		return nil, errors.Wrap(err, "could not read file")
+	}
+
+	tscn, err := parser.ParseFile(o.filename, bytes.NewReader(content))
+	if err != nil {
+		if o.diagnostics != nil {
+			o.diagnostics.Add(syntaxDiagnostic(err, o.filename))
+		}
 		return nil, errors.Wrap(err, "parser error")
 	}
-	if err = validate.TscnFileFormat(tscn); err != nil {
-		return nil, errors.Wrap(err, "invalid file format")
+
+	diagnostics := o.registry.check(&ruleFile{filename: o.filename, source: string(content), tscn: tscn})
+	if o.diagnostics != nil {
+		o.diagnostics.Add(diagnostics...)
+		return tscn, nil
+	}
+
+	if errs := diagnostics.Errors(); len(errs) > 0 {
+		return nil, errors.Wrap(errs[0], "invalid file format")
 	}
 	return tscn, nil
 }
+
+func syntaxDiagnostic(err error, filename string) diagnostic.Diagnostic {
+	d := diagnostic.FromError(err)
+	d.RuleID = SyntaxRuleID
+	if d.Position.Line == 0 {
+		d.Position.Filename = filename
+	}
+	return d
+}
@@ -4,8 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/atomicptr/godot-tscn-parser/pkg/diagnostic"
 	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
 )
 
@@ -20,6 +22,28 @@ position = Vector2(13, 37)`
 	assert.Len(t, scene.ExtResources, 1)
 }
 
+func TestParseSceneWithFormat3(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=3 uid="uid://cecaux1sm7mo0"]
+[ext_resource type="PackedScene" uid="uid://dh1x0t8pnwr3l" path="res://Test.tscn" id="1_kq1ra"]
+[node name="Root" type="Node2D"]
+[node name="Child" parent="." instance=ExtResource("1_kq1ra")]
+position = Vector2(13, 37)`
+	scene, err := ParseScene(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(godot.FormatVersionGodot4), scene.Format)
+	assert.Equal(t, "uid://cecaux1sm7mo0", scene.UID)
+	assert.Len(t, scene.ExtResources, 1)
+	assert.Equal(t, "res://Test.tscn", scene.ExtResources["1_kq1ra"].Path)
+}
+
+func TestParseSceneWithFormat3AndMissingReference(t *testing.T) {
+	content := `[gd_scene format=3]
+[node name="Root" type="Node2D"]
+script = ExtResource("1_x7k2p")`
+	_, err := ParseScene(strings.NewReader(content))
+	assert.Error(t, err)
+}
+
 func TestParseSceneWithInvalidFormat(t *testing.T) {
 	content := `[gd_scene`
 	_, err := ParseScene(strings.NewReader(content))
@@ -54,6 +78,36 @@ environment/default_environment="res://default_env.tres"`
 	assert.Equal(t, "Test Game", configName.Value)
 }
 
+func TestParseProjectSettingsWithFeatures(t *testing.T) {
+	content := `config_version=4
+
+[application]
+
+config/name="Test Game"
+
+[rendering]
+
+quality/driver/driver_name="GLES3"
+quality/driver/driver_name.mobile="GLES2"
+vram_compression/import_etc=true
+
+[custom_section]
+
+value=42
+`
+	project, err := ParseProject(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	settings := project.Settings()
+	assert.Equal(t, "GLES3", settings.GetString("rendering/quality/driver/driver_name", ""))
+	assert.Equal(t, int64(42), settings.GetInt("custom_section/value", 0))
+	assert.Equal(t, []string{"mobile"}, settings.Overrides("rendering/quality/driver/driver_name"))
+
+	mobile := settings.WithFeatures([]string{"mobile", "release"})
+	assert.Equal(t, "GLES2", mobile.GetString("rendering/quality/driver/driver_name", ""))
+	assert.Equal(t, "Test Game", mobile.GetString("application/config/name", ""))
+}
+
 func TestParseProjectWithInvalidFormat(t *testing.T) {
 	content := `[test`
 	_, err := ParseProject(strings.NewReader(content))
@@ -72,12 +126,309 @@ background_sky = SubResource( 1 )`
 	assert.Len(t, resource.Fields, 2)
 }
 
+func TestParseResourceWithFormat3(t *testing.T) {
+	content := `[gd_resource type="Environment" load_steps=2 format=3 uid="uid://b5k2j0ffbn8sx"]
+[sub_resource type="Sky" id="Sky_x3m1c"]
+[resource]
+background_mode = 2
+sky = SubResource("Sky_x3m1c")`
+	resource, err := ParseResource(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(godot.FormatVersionGodot4), resource.Format)
+	assert.Equal(t, "uid://b5k2j0ffbn8sx", resource.UID)
+	assert.Equal(t, "Sky", resource.SubResources["Sky_x3m1c"].Type)
+}
+
+func TestParseResourceWithTileSetAtlasSource(t *testing.T) {
+	content := `[gd_resource type="TileSet" load_steps=3 format=3 uid="uid://c6ia3mw1d7nbb"]
+
+[ext_resource type="Texture2D" uid="uid://cicon" path="res://tiles.png" id="1_tiles"]
+
+[sub_resource type="TileSetAtlasSource" id="TileSetAtlasSource_ajbyw"]
+texture = ExtResource("1_tiles")
+0:0/0 = 0
+0:0/0/terrain_set = 0
+0:0/0/physics_layer_0/polygon_0/points = PackedVector2Array(-8, -8, 8, -8, 8, 8, -8, 8)
+1:0/size_in_atlas = Vector2i(2, 2)
+1:0/0 = 0
+
+[resource]
+terrain_set_0/mode = 0
+sources/0 = SubResource("TileSetAtlasSource_ajbyw")
+`
+	resource, err := ParseResource(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	source := resource.SubResources["TileSetAtlasSource_ajbyw"]
+	assert.Len(t, source.Fields, 6)
+
+	tiles := godot.ExpandFields(source.Fields)
+	assert.Equal(
+		t,
+		[]string{"0", "0/physics_layer_0/polygon_0/points", "0/terrain_set"},
+		tiles["0:0"].(godot.FieldGroup).Keys(),
+	)
+	assert.Equal(t, []string{"0", "size_in_atlas"}, tiles["1:0"].(godot.FieldGroup).Keys())
+
+	var sb strings.Builder
+	assert.NoError(t, WriteResource(&sb, resource))
+	assert.Equal(t, content, sb.String())
+}
+
 func TestParseResourceWithInvalidFormat(t *testing.T) {
 	content := `[gd_resource`
 	_, err := ParseResource(strings.NewReader(content))
 	assert.Error(t, err)
 }
 
+func TestParseImport(t *testing.T) {
+	content := `[remap]
+
+importer="texture"
+type="StreamTexture"
+path.s3tc="res://.import/icon.png-487276ed1e3a0c39cad0279d744ee560.s3tc.stex"
+path.etc="res://.import/icon.png-487276ed1e3a0c39cad0279d744ee560.etc.stex"
+metadata={
+"imported_formats": [ "s3tc", "etc" ],
+"vram_texture": true
+}
+
+[deps]
+
+source_file="res://icon.png"
+dest_files=[ "res://.import/icon.png-487276ed1e3a0c39cad0279d744ee560.s3tc.stex", "res://.import/icon.png-487276ed1e3a0c39cad0279d744ee560.etc.stex" ]
+
+[params]
+
+compress/mode=2
+compress/lossy_quality=0.7
+flags/filter=true
+svg/scale=1.0
+`
+	imp, err := ParseImport(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "texture", imp.Importer)
+	assert.Equal(t, "StreamTexture", imp.Type)
+	assert.Empty(t, imp.Path)
+	assert.Empty(t, imp.UID)
+	assert.Equal(t, map[string]string{
+		"s3tc": "res://.import/icon.png-487276ed1e3a0c39cad0279d744ee560.s3tc.stex",
+		"etc":  "res://.import/icon.png-487276ed1e3a0c39cad0279d744ee560.etc.stex",
+	}, imp.PlatformPaths)
+
+	assert.Equal(t, "res://icon.png", imp.SourceFile)
+	assert.Len(t, imp.DestFiles, 2)
+
+	assert.Equal(t, int64(2), imp.Parameters["compress/mode"])
+	assert.Equal(t, 0.7, imp.Parameters["compress/lossy_quality"])
+	assert.Equal(t, true, imp.Parameters["flags/filter"])
+}
+
+func TestParseImportWithFormat4(t *testing.T) {
+	content := `[remap]
+
+importer="texture"
+type="CompressedTexture2D"
+uid="uid://b3k2p4ehb0xwy"
+path="res://.godot/imported/icon.svg-218a8f2b3041327d8a5756f3a245f83b.ctex"
+
+[deps]
+
+source_file="res://icon.svg"
+
+[params]
+
+compress/mode=0
+process/size_limit=Vector2i(256, 256)
+`
+	imp, err := ParseImport(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "uid://b3k2p4ehb0xwy", imp.UID)
+	assert.Equal(t, "res://.godot/imported/icon.svg-218a8f2b3041327d8a5756f3a245f83b.ctex", imp.Path)
+	assert.Empty(t, imp.PlatformPaths)
+	assert.Nil(t, imp.DestFiles)
+	assert.Equal(t, godot.Vector2i{X: 256, Y: 256}, imp.Parameters["process/size_limit"])
+}
+
+func TestParseImportWithInvalidRemap(t *testing.T) {
+	_, err := ParseImport(strings.NewReader("[remap]\nimporter=12"))
+
+	var invalid *diagnostic.InvalidValueError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "importer", invalid.Key)
+}
+
+func TestParseConfig(t *testing.T) {
+	content := `[preset.0]
+
+name="HTML5"
+platform="HTML5"
+runnable=true
+export_filter="all_resources"
+include_filter=""
+
+[preset.0.options]
+
+custom_template/debug=""
+vram_texture_compression/for_desktop=true
+html/head_include=""
+`
+	config, err := ParseConfig(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"preset.0", "preset.0.options"}, config.Sections())
+	assert.Equal(t, []string{"name", "platform", "runnable", "export_filter", "include_filter"}, config.SectionKeys("preset.0"))
+	assert.Equal(t, "HTML5", config.GetValue("preset.0", "name", nil))
+	assert.Equal(t, true, config.GetValue("preset.0.options", "vram_texture_compression/for_desktop", false))
+	assert.Equal(t, int64(5), config.GetValue("preset.0", "missing", int64(5)))
+	assert.Equal(t, int64(godot.FormatVersionGodot4), config.Format)
+}
+
+func TestParseConfigWithGDExtension(t *testing.T) {
+	content := `[configuration]
+
+entry_symbol="example_library_init"
+compatibility_minimum=4.1
+
+[libraries]
+
+linux.x86_64.debug="res://bin/libexample.linux.template_debug.x86_64.so"
+windows.x86_64.release="res://bin/libexample.windows.template_release.x86_64.dll"
+`
+	config, err := ParseConfig(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, 4.1, config.GetValue("configuration", "compatibility_minimum", nil))
+	assert.Equal(
+		t,
+		"res://bin/libexample.linux.template_debug.x86_64.so",
+		config.GetValue("libraries", "linux.x86_64.debug", nil),
+	)
+}
+
+func TestParseConfigWithFieldsBeforeFirstSection(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader("config_version=4\n\n[application]\n\nconfig/name=\"Test\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "application"}, config.Sections())
+	assert.Equal(t, int64(4), config.GetValue("", "config_version", nil))
+}
+
+func TestParseConfigDetectsFormat(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader("[plugin]\n\nfiles=PoolStringArray( \"a.gd\" )\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(godot.FormatVersionGodot3), config.Format)
+}
+
+func TestParseConfigWithAttributes(t *testing.T) {
+	_, err := ParseConfig(strings.NewReader("[plugin name=\"Test\"]\n"))
+
+	var invalid *diagnostic.InvalidValueError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "name", invalid.Key)
+}
+
+const exportPresetsContent = `[preset.0]
+
+name="HTML5"
+platform="HTML5"
+runnable=true
+custom_features=""
+export_filter="all_resources"
+include_filter="*.json"
+exclude_filter=""
+export_path="build/web/index.html"
+script_export_mode=1
+script_encryption_key=""
+
+[preset.0.options]
+
+custom_template/debug=""
+custom_template/release=""
+variant/export_type=0
+vram_texture_compression/for_desktop=true
+html/custom_html_shell=""
+
+[preset.1]
+
+name="Linux/X11"
+platform="Linux/X11"
+runnable=false
+custom_features="demo"
+export_filter="all_resources"
+include_filter=""
+exclude_filter=""
+export_path="build/linux/game.x86_64"
+script_export_mode=1
+script_encryption_key=""
+
+[preset.1.options]
+
+binary_format/64_bits=true
+binary_format/embed_pck=false
+texture_format/s3tc=true
+
+[preset.2]
+
+name="Android"
+platform="Android"
+runnable=false
+custom_features=""
+export_filter="all_resources"
+include_filter=""
+exclude_filter=""
+export_path="build/android/game.apk"
+script_export_mode=1
+script_encryption_key=""
+
+[preset.2.options]
+
+custom_build/use_custom_build=false
+architectures/armeabi-v7a=false
+architectures/arm64-v8a=true
+architectures/x86=false
+package/unique_name="com.example.game"
+`
+
+func TestParseExportPresets(t *testing.T) {
+	presets, err := ParseExportPresets(strings.NewReader(exportPresetsContent))
+	assert.NoError(t, err)
+	assert.Len(t, presets.Presets, 3)
+
+	web := presets.Preset("HTML5")
+	assert.Equal(t, "HTML5", web.Platform)
+	assert.True(t, web.Runnable)
+	assert.Equal(t, "*.json", web.IncludeFilter)
+	assert.Equal(t, "build/web/index.html", web.ExportPath)
+	assert.Equal(t, true, web.Options["vram_texture_compression/for_desktop"])
+	assert.Equal(t, int64(1), web.Fields["script_export_mode"].(godot.Value).Value)
+
+	linux := presets.PresetsForPlatform("Linux/X11")
+	assert.Len(t, linux, 1)
+	assert.Equal(t, "demo", linux[0].CustomFeatures)
+	assert.Equal(t, true, linux[0].Options["binary_format/64_bits"])
+
+	android := presets.Preset("Android")
+	assert.Equal(t, false, android.Options["architectures/armeabi-v7a"])
+	assert.Equal(t, true, android.Options["architectures/arm64-v8a"])
+}
+
+func TestParseExportPresetsSortsByIndex(t *testing.T) {
+	content := "[preset.1]\n\nname=\"B\"\n\n[preset.0]\n\nname=\"A\"\n\n[preset.0.options]\n\nkey=1\n"
+	presets, err := ParseExportPresets(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "A", presets.Presets[0].Name)
+	assert.Equal(t, int64(1), presets.Presets[0].Options["key"])
+	assert.Equal(t, "B", presets.Presets[1].Name)
+	assert.Empty(t, presets.Presets[1].Options)
+}
+
+func TestParseExportPresetsWithInvalidField(t *testing.T) {
+	_, err := ParseExportPresets(strings.NewReader("[preset.0]\n\nrunnable=\"yes\"\n"))
+
+	var invalid *diagnostic.InvalidValueError
+	assert.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "runnable", invalid.Key)
+}
+
 func TestParseAndValidateTscnFileWithValidatorError(t *testing.T) {
 	content := `[gd_scene]
 [ext_resource path="res://Test.tscn" type="PackedScene" id=1]
@@ -88,3 +439,76 @@ position = Vector2(13, 37)`
 	_, err := ParseScene(strings.NewReader(content))
 	assert.Error(t, err)
 }
+
+func TestParseSceneWithDiagnostics(t *testing.T) {
+	content := `[gd_scene format=3]
+[ext_resource type="Script" path="res://Player.gd" id="1_x7k2p"]
+[node name="Root" type="Node2D"]
+script = ExtResource("1_x7k2p")
+texture = ExtResource("2_missing")
+[node name="SecondRoot" type="Node2D"]
+[node name="Child" type="Node2D" parent="."]`
+
+	var diagnostics diagnostic.List
+	scene, err := ParseScene(strings.NewReader(content), WithFilename("Player.tscn"), WithDiagnostics(&diagnostics))
+	assert.NoError(t, err)
+	assert.NotNil(t, scene)
+	assert.Equal(t, "Root", scene.Name)
+	assert.Equal(t, 1, scene.ChildCount())
+
+	assert.True(t, diagnostics.HasErrors())
+	assert.Len(t, diagnostics, 2)
+	assert.Equal(t, "single-root-node", diagnostics[0].RuleID)
+	assert.Equal(t, "Player.tscn", diagnostics[0].Position.Filename)
+	assert.Equal(t, 6, diagnostics[0].Position.Line)
+	assert.Equal(t, "resource-references-exist", diagnostics[1].RuleID)
+	assert.Equal(t, 5, diagnostics[1].Position.Line)
+}
+
+func TestParseSceneWithDiagnosticsAndSyntaxError(t *testing.T) {
+	var diagnostics diagnostic.List
+	scene, err := ParseScene(strings.NewReader("[gd_scene]\n[node"), WithFilename("Broken.tscn"), WithDiagnostics(&diagnostics))
+	assert.Error(t, err)
+	assert.Nil(t, scene)
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, SyntaxRuleID, diagnostics[0].RuleID)
+	assert.Equal(t, "Broken.tscn", diagnostics[0].Position.Filename)
+	assert.Equal(t, 2, diagnostics[0].Position.Line)
+}
+
+func TestParseSceneWithFilename(t *testing.T) {
+	content := `[gd_scene]
+[node name="Root" type="Node2D"]
+[node name="Root 2" type="Node2D"]`
+	_, err := ParseScene(strings.NewReader(content), WithFilename("Level.tscn"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Level.tscn:3:1")
+}
+
+func TestParseSceneReturnsTypedErrors(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://icon.png" type="Texture" id=1]
+
+[node name="Sprite" type="Sprite"]
+texture = ExtResource( 4 )
+`
+	_, err := ParseScene(strings.NewReader(content), WithFilename("Sprite.tscn"))
+
+	var dangling *diagnostic.DanglingReferenceError
+	assert.True(t, errors.As(err, &dangling))
+	assert.Equal(t, "4", dangling.ID)
+	assert.Equal(t, "node", dangling.Section)
+	assert.Equal(
+		t,
+		"Sprite.tscn:6:11: error: could not find type reference ExtResource(4) [resource-references-exist]\n"+
+			"6 | texture = ExtResource( 4 )\n"+
+			"  |           ^^^^^^^^^^^^^^^^",
+		diagnostic.RenderError(content, err),
+	)
+
+	_, err = ParseScene(strings.NewReader("[gd_scene]\n[node name=]"), WithFilename("Broken.tscn"))
+	var parseError *diagnostic.ParseError
+	assert.True(t, errors.As(err, &parseError))
+	assert.Equal(t, 2, parseError.Position.Line)
+}
@@ -0,0 +1,20 @@
+package tscn
+
+import (
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+)
+
+// Unmarshal decodes the fields of a node or resource (e.g. node.Fields) into the struct v points to.
+//
+// Struct fields are matched by their godot tag (`godot:"collision_layer"`) or by their name in snake case, a tag of
+// "-" ignores the field. Numbers, strings, arrays, dictionaries, math types like godot.Vector2 and resource
+// references (godot.Reference) are converted, errors contain the position of the offending value.
+func Unmarshal(fields map[string]interface{}, v interface{}) error {
+	return convert.UnmarshalFields(fields, v)
+}
+
+// Marshal converts a struct into fields of a node or resource, it uses the same struct tags as Unmarshal and
+// additionally supports omitempty (`godot:"collision_layer,omitempty"`)
+func Marshal(v interface{}) (map[string]interface{}, error) {
+	return convert.MarshalFields(v)
+}
@@ -0,0 +1,43 @@
+package tscn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestUnmarshalNodeFields(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Player.gd" type="Script" id=1]
+
+[node name="Player" type="KinematicBody2D"]
+collision_layer = 3
+position = Vector2( 120, 64 )
+script = ExtResource( 1 )
+`
+	scene, err := ParseScene(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	var player struct {
+		CollisionLayer int `godot:"collision_layer"`
+		Position       godot.Vector2
+		Script         godot.Reference
+	}
+	assert.NoError(t, Unmarshal(scene.Node.Fields, &player))
+	assert.Equal(t, 3, player.CollisionLayer)
+	assert.Equal(t, godot.Vector2{X: 120, Y: 64}, player.Position)
+	assert.Equal(t, "res://Player.gd", scene.ExtResources[player.Script.ID].Path)
+
+	player.Position = player.Position.Add(godot.Vector2{X: 10})
+	fields, err := Marshal(player)
+	assert.NoError(t, err)
+	scene.Node.Fields = fields
+
+	var sb strings.Builder
+	assert.NoError(t, WriteScene(&sb, scene))
+	assert.Equal(t, strings.Replace(content, "Vector2( 120, 64 )", "Vector2( 130, 64 )", 1), sb.String())
+}
@@ -0,0 +1,65 @@
+package tscn
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/atomicptr/godot-tscn-parser/internal/convert"
+	"github.com/atomicptr/godot-tscn-parser/internal/parser"
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+// WriteScene writes a scene in the TSCN file format of its format version, the load_steps are recomputed
+func WriteScene(w io.Writer, scene *godot.Scene) error {
+	tscn, options, err := convert.FromGodotScene(scene)
+	if err != nil {
+		return errors.Wrap(err, "could not convert scene")
+	}
+	return parser.Print(w, tscn, options)
+}
+
+// WriteResource writes a resource as .tres file
+func WriteResource(w io.Writer, res *godot.Resource) error {
+	tscn, options, err := convert.FromGodotResource(res)
+	if err != nil {
+		return errors.Wrap(err, "could not convert resource")
+	}
+	return parser.Print(w, tscn, options)
+}
+
+// WriteProject writes the project.godot project configuration file
+func WriteProject(w io.Writer, project *godot.Project) error {
+	tscn, options, err := convert.FromGodotProject(project)
+	if err != nil {
+		return errors.Wrap(err, "could not convert project")
+	}
+	return parser.Print(w, tscn, options)
+}
+
+// WriteImport writes a *.import file
+func WriteImport(w io.Writer, imp *godot.Import) error {
+	tscn, options, err := convert.FromGodotImport(imp)
+	if err != nil {
+		return errors.Wrap(err, "could not convert import")
+	}
+	return parser.Print(w, tscn, options)
+}
+
+// WriteConfig writes a config file like Godots ConfigFile.save
+func WriteConfig(w io.Writer, config *godot.ConfigFile) error {
+	tscn, options, err := convert.FromGodotConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "could not convert config")
+	}
+	return parser.Print(w, tscn, options)
+}
+
+// WriteExportPresets writes the export_presets.cfg file, presets are numbered by their position in the list
+func WriteExportPresets(w io.Writer, presets *godot.ExportPresets) error {
+	tscn, options, err := convert.FromGodotExportPresets(presets)
+	if err != nil {
+		return errors.Wrap(err, "could not convert export presets")
+	}
+	return parser.Print(w, tscn, options)
+}
@@ -0,0 +1,298 @@
+package tscn
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/atomicptr/godot-tscn-parser/pkg/godot"
+)
+
+func TestWriteScene(t *testing.T) {
+	scene := &godot.Scene{
+		Format: godot.FormatVersionGodot3,
+		ExtResources: map[string]*godot.ExtResource{
+			"1": {Path: "res://Player.gd", Type: "Script", ID: "1"},
+		},
+		Node: &godot.Node{
+			Name:   "Player",
+			Type:   "KinematicBody2D",
+			Fields: map[string]interface{}{"script": godot.Type{Identifier: "ExtResource", Parameters: []interface{}{1}}},
+		},
+	}
+	scene.AddNode(&godot.Node{
+		Name:   "Sprite",
+		Type:   "Sprite",
+		Fields: map[string]interface{}{"hframes": 60},
+	})
+
+	var sb strings.Builder
+	assert.NoError(t, WriteScene(&sb, scene))
+	assert.Equal(t, `[gd_scene load_steps=2 format=2]
+
+[ext_resource path="res://Player.gd" type="Script" id=1]
+
+[node name="Player" type="KinematicBody2D"]
+script = ExtResource( 1 )
+
+[node name="Sprite" type="Sprite" parent="."]
+hframes = 60
+`, sb.String())
+}
+
+func TestWriteSceneRoundTrip(t *testing.T) {
+	content := `[gd_scene load_steps=2 format=3 uid="uid://cecaux1sm7mo0"]
+
+[ext_resource type="PackedScene" uid="uid://dh1x0t8pnwr3l" path="res://hitbox.tscn" id="1_kq1ra"]
+
+[node name="Player" type="CharacterBody2D"]
+collision_layer = 2
+
+[node name="Hitbox" parent="." instance=ExtResource("1_kq1ra")]
+position = Vector2(13, 37)
+
+[connection signal="area_entered" from="Hitbox" to="." method="_on_hitbox_area_entered" flags=3]
+`
+	scene, err := ParseScene(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	var sb strings.Builder
+	assert.NoError(t, WriteScene(&sb, scene))
+	assert.Equal(t, content, sb.String())
+}
+
+func TestWriteSceneWithRemovedNode(t *testing.T) {
+	content := `[gd_scene format=2]
+
+[node name="Root" type="Node2D"]
+
+[node name="A" type="Node2D" parent="."]
+
+[node name="B" type="Node2D" parent="A"]
+`
+	scene, err := ParseScene(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.NoError(t, scene.RemoveNode("A/B"))
+
+	var sb strings.Builder
+	assert.NoError(t, WriteScene(&sb, scene))
+	assert.Equal(t, `[gd_scene format=2]
+
+[node name="Root" type="Node2D"]
+
+[node name="A" type="Node2D" parent="."]
+`, sb.String())
+}
+
+func TestWriteResource(t *testing.T) {
+	content := `[gd_resource type="Environment" load_steps=2 format=2]
+
+[sub_resource type="ProceduralSky" id=1]
+
+[resource]
+background_mode = 2
+background_sky = SubResource( 1 )
+`
+	res, err := ParseResource(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	var sb strings.Builder
+	assert.NoError(t, WriteResource(&sb, res))
+	assert.Equal(t, content, sb.String())
+}
+
+func TestWriteProject(t *testing.T) {
+	content := `config_version=4
+
+[application]
+
+config/name="Test Game"
+run/main_scene="res://World.tscn"
+
+[display]
+
+window/size/width=320
+`
+	project, err := ParseProject(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	var sb strings.Builder
+	assert.NoError(t, WriteProject(&sb, project))
+	assert.Equal(t, content, sb.String())
+}
+
+func TestWriteImport(t *testing.T) {
+	imp := &godot.Import{
+		Importer:   "texture",
+		SourceFile: "res://icon.png",
+		Remap:      map[string]interface{}{},
+		Deps:       map[string]interface{}{},
+		Params:     map[string]interface{}{},
+	}
+
+	var sb strings.Builder
+	assert.NoError(t, WriteImport(&sb, imp))
+	assert.Equal(t, `[remap]
+
+importer="texture"
+
+[deps]
+
+source_file="res://icon.png"
+
+[params]
+`, sb.String())
+}
+
+func TestWriteConfig(t *testing.T) {
+	content := `[plugin]
+
+name="Test Plugin"
+description="A plugin"
+author="atomicptr"
+version="1.0"
+script="plugin.gd"
+
+[preset.0]
+
+name="Linux/X11"
+custom_features=""
+`
+	config, err := ParseConfig(strings.NewReader(content))
+	assert.NoError(t, err)
+
+	var sb strings.Builder
+	assert.NoError(t, WriteConfig(&sb, config))
+	assert.Equal(t, content, sb.String())
+}
+
+func TestWriteConfigWithNewValues(t *testing.T) {
+	config := godot.NewConfigFile()
+	config.SetValue("", "config_version", 5)
+	config.SetValue("application", "config/name", "Test")
+	config.SetValue("application", "config/features", []interface{}{"4.2"})
+	config.SetValue("display", "window/size/viewport_width", 320)
+	config.SetValue("display", "window/size/viewport_width", nil)
+
+	var sb strings.Builder
+	assert.NoError(t, WriteConfig(&sb, config))
+	assert.Equal(t, `config_version=5
+
+[application]
+
+config/name="Test"
+config/features=["4.2"]
+`, sb.String())
+}
+
+func TestWriteExportPresets(t *testing.T) {
+	presets, err := ParseExportPresets(strings.NewReader(exportPresetsContent))
+	assert.NoError(t, err)
+
+	var sb strings.Builder
+	assert.NoError(t, WriteExportPresets(&sb, presets))
+	assert.Equal(t, exportPresetsContent, sb.String())
+}
+
+func TestWriteExportPresetsWithChanges(t *testing.T) {
+	presets, err := ParseExportPresets(strings.NewReader(exportPresetsContent))
+	assert.NoError(t, err)
+
+	web := presets.Preset("HTML5")
+	web.ExportPath = "build/staging/index.html"
+	web.Options["variant/export_type"] = 1
+
+	android := godot.NewExportPreset("Android", "Android")
+	android.Options["package/unique_name"] = "com.example.game"
+	presets.Presets = []*godot.ExportPreset{web, android}
+
+	var sb strings.Builder
+	assert.NoError(t, WriteExportPresets(&sb, presets))
+
+	expected := strings.Replace(exportPresetsContent, "build/web/index.html", "build/staging/index.html", 1)
+	expected = strings.Replace(expected, "variant/export_type=0", "variant/export_type=1", 1)
+	expected = expected[:strings.Index(expected, "[preset.1]")] + `[preset.1]
+
+custom_features=""
+exclude_filter=""
+export_filter="all_resources"
+export_path=""
+include_filter=""
+name="Android"
+platform="Android"
+runnable=false
+
+[preset.1.options]
+
+package/unique_name="com.example.game"
+`
+	assert.Equal(t, expected, sb.String())
+}
+
+// keep integration tests at the bottom please
+func TestIntegrationWriteSceneFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "test", "fixtures", "*.tscn"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		f, err := os.Open(filepath.Clean(file))
+		assert.NoError(t, err)
+
+		scene, err := ParseScene(f)
+		assert.NoError(t, err, file)
+		assert.NoError(t, f.Close())
+
+		var sb strings.Builder
+		assert.NoError(t, WriteScene(&sb, scene), file)
+
+		rewritten, err := ParseScene(strings.NewReader(sb.String()))
+		assert.NoError(t, err, file)
+		assert.Len(t, rewritten.ExtResources, len(scene.ExtResources), file)
+		assert.Len(t, rewritten.SubResources, len(scene.SubResources), file)
+		assert.Len(t, rewritten.Connections, len(scene.Connections), file)
+	}
+}
+
+func TestIntegrationWriteGodot4SceneFixture(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("..", "..", "test", "fixtures", "atomicptr-godot-tscn-parser-godot4-player.tscn"))
+	assert.NoError(t, err)
+
+	scene, err := ParseScene(strings.NewReader(string(content)))
+	assert.NoError(t, err)
+
+	// StringName keys like &"attack" have to stay StringNames
+	var sb strings.Builder
+	assert.NoError(t, WriteScene(&sb, scene))
+	assert.Equal(t, string(content), sb.String())
+}
+
+func TestIntegrationWriteImportFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "test", "fixtures", "*.import"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		f, err := os.Open(filepath.Clean(file))
+		assert.NoError(t, err)
+
+		imp, err := ParseImport(f)
+		assert.NoError(t, err, file)
+		assert.NoError(t, f.Close())
+		assert.Equal(t, "texture", imp.Importer, file)
+		assert.NotEmpty(t, imp.SourceFile, file)
+		assert.Len(t, imp.DestFiles, 1, file)
+
+		var sb strings.Builder
+		assert.NoError(t, WriteImport(&sb, imp), file)
+
+		rewritten, err := ParseImport(strings.NewReader(sb.String()))
+		assert.NoError(t, err, file)
+		assert.Equal(t, imp.Path, rewritten.Path, file)
+		assert.Equal(t, imp.DestFiles, rewritten.DestFiles, file)
+		assert.Equal(t, imp.Parameters, rewritten.Parameters, file)
+	}
+}